@@ -1 +1,429 @@
 package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Token kinds produced by the lexer
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokOp
+	tokLParen
+	tokRParen
+	tokAssign
+)
+
+// Token is a single lexical element with the column it started at (1-based)
+type Token struct {
+	Kind  tokenKind
+	Text  string
+	Value float64
+	Col   int
+}
+
+// ParseError reports a problem at a specific column of the input
+type ParseError struct {
+	Col     int
+	Message string
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.Col, e.Message)
+}
+
+// tokenize splits an input line into tokens
+func tokenize(input string) ([]Token, error) {
+	var tokens []Token
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		col := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			// Optional exponent, e.g. 1e3 or 2.5E-4
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				j := i + 1
+				if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+					j++
+				}
+				if j < len(runes) && unicode.IsDigit(runes[j]) {
+					i = j
+					for i < len(runes) && unicode.IsDigit(runes[i]) {
+						i++
+					}
+				}
+			}
+			text := string(runes[start:i])
+			value, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, ParseError{Col: col, Message: fmt.Sprintf("invalid number %q", text)}
+			}
+			tokens = append(tokens, Token{Kind: tokNumber, Text: text, Value: value, Col: col})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, Token{Kind: tokIdent, Text: string(runes[start:i]), Col: col})
+		case strings.ContainsRune("+-*/%^", r):
+			tokens = append(tokens, Token{Kind: tokOp, Text: string(r), Col: col})
+			i++
+		case r == '(':
+			tokens = append(tokens, Token{Kind: tokLParen, Text: "(", Col: col})
+			i++
+		case r == ')':
+			tokens = append(tokens, Token{Kind: tokRParen, Text: ")", Col: col})
+			i++
+		case r == '=':
+			tokens = append(tokens, Token{Kind: tokAssign, Text: "=", Col: col})
+			i++
+		default:
+			return nil, ParseError{Col: col, Message: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+
+	tokens = append(tokens, Token{Kind: tokEOF, Col: len(runes) + 1})
+	return tokens, nil
+}
+
+// Node is an expression tree node
+type Node interface {
+	Eval(env map[string]float64) (float64, error)
+}
+
+type numberNode struct {
+	value float64
+}
+
+type varNode struct {
+	name string
+	col  int
+}
+
+type unaryNode struct {
+	op      string
+	operand Node
+}
+
+type binaryNode struct {
+	op          string
+	left, right Node
+	col         int
+}
+
+func (n numberNode) Eval(env map[string]float64) (float64, error) {
+	return n.value, nil
+}
+
+func (n varNode) Eval(env map[string]float64) (float64, error) {
+	value, ok := env[n.name]
+	if !ok {
+		return 0, ParseError{Col: n.col, Message: fmt.Sprintf("undefined variable %q", n.name)}
+	}
+	return value, nil
+}
+
+func (n unaryNode) Eval(env map[string]float64) (float64, error) {
+	value, err := n.operand.Eval(env)
+	if err != nil {
+		return 0, err
+	}
+	if n.op == "-" {
+		return -value, nil
+	}
+	return value, nil
+}
+
+func (n binaryNode) Eval(env map[string]float64) (float64, error) {
+	left, err := n.left.Eval(env)
+	if err != nil {
+		return 0, err
+	}
+	right, err := n.right.Eval(env)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n.op {
+	case "+":
+		return left + right, nil
+	case "-":
+		return left - right, nil
+	case "*":
+		return left * right, nil
+	case "/":
+		if right == 0 {
+			return 0, ParseError{Col: n.col, Message: "division by zero"}
+		}
+		return left / right, nil
+	case "%":
+		if right == 0 {
+			return 0, ParseError{Col: n.col, Message: "modulo by zero"}
+		}
+		return math.Mod(left, right), nil
+	case "^":
+		return math.Pow(left, right), nil
+	}
+	return 0, ParseError{Col: n.col, Message: fmt.Sprintf("unknown operator %q", n.op)}
+}
+
+// Statement is either an assignment (Name != "") or a bare expression
+type Statement struct {
+	Name string
+	Expr Node
+}
+
+// Parser is a recursive descent parser over the token stream.
+//
+// Grammar (lowest to highest precedence):
+//
+//	statement  = [ident "="] expression
+//	expression = term { ("+" | "-") term }
+//	term       = unary { ("*" | "/" | "%") unary }
+//	unary      = ("-" | "+") unary | power
+//	power      = primary [ "^" unary ]        (right associative)
+//	primary    = number | ident | "(" expression ")"
+type Parser struct {
+	tokens []Token
+	pos    int
+}
+
+func (p *Parser) peek() Token {
+	return p.tokens[p.pos]
+}
+
+func (p *Parser) next() Token {
+	tok := p.tokens[p.pos]
+	if tok.Kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *Parser) isOp(ops ...string) bool {
+	tok := p.peek()
+	if tok.Kind != tokOp {
+		return false
+	}
+	for _, op := range ops {
+		if tok.Text == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse parses a full input line into a statement
+func Parse(input string) (Statement, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return Statement{}, err
+	}
+	p := &Parser{tokens: tokens}
+
+	var stmt Statement
+	if len(tokens) > 2 && tokens[0].Kind == tokIdent && tokens[1].Kind == tokAssign {
+		stmt.Name = tokens[0].Text
+		p.pos = 2
+	}
+
+	stmt.Expr, err = p.parseExpression()
+	if err != nil {
+		return Statement{}, err
+	}
+
+	if tok := p.peek(); tok.Kind != tokEOF {
+		return Statement{}, ParseError{Col: tok.Col, Message: fmt.Sprintf("unexpected %q", tok.Text)}
+	}
+	return stmt, nil
+}
+
+func (p *Parser) parseExpression() (Node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("+", "-") {
+		op := p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op.Text, left: left, right: right, col: op.Col}
+	}
+	return left, nil
+}
+
+func (p *Parser) parseTerm() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("*", "/", "%") {
+		op := p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op.Text, left: left, right: right, col: op.Col}
+	}
+	return left, nil
+}
+
+func (p *Parser) parseUnary() (Node, error) {
+	if p.isOp("-", "+") {
+		op := p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: op.Text, operand: operand}, nil
+	}
+	return p.parsePower()
+}
+
+func (p *Parser) parsePower() (Node, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOp("^") {
+		op := p.next()
+		// Recurse through parseUnary so 2^-1 works and 2^3^2 == 2^(3^2)
+		exponent, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return binaryNode{op: op.Text, left: base, right: exponent, col: op.Col}, nil
+	}
+	return base, nil
+}
+
+func (p *Parser) parsePrimary() (Node, error) {
+	tok := p.next()
+	switch tok.Kind {
+	case tokNumber:
+		return numberNode{value: tok.Value}, nil
+	case tokIdent:
+		return varNode{name: tok.Text, col: tok.Col}, nil
+	case tokLParen:
+		expr, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.Kind != tokRParen {
+			return nil, ParseError{Col: closing.Col, Message: fmt.Sprintf("expected ')' to close '(' at column %d", tok.Col)}
+		}
+		return expr, nil
+	case tokEOF:
+		return nil, ParseError{Col: tok.Col, Message: "unexpected end of input"}
+	}
+	return nil, ParseError{Col: tok.Col, Message: fmt.Sprintf("unexpected %q", tok.Text)}
+}
+
+// Calculator evaluates statements and remembers assigned variables
+type Calculator struct {
+	vars map[string]float64
+}
+
+func NewCalculator() *Calculator {
+	return &Calculator{vars: map[string]float64{
+		"pi": math.Pi,
+		"e":  math.E,
+	}}
+}
+
+// Eval parses and evaluates one line, storing the result for assignments
+func (c *Calculator) Eval(line string) (float64, error) {
+	stmt, err := Parse(line)
+	if err != nil {
+		return 0, err
+	}
+	value, err := stmt.Expr.Eval(c.vars)
+	if err != nil {
+		return 0, err
+	}
+	if stmt.Name != "" {
+		c.vars[stmt.Name] = value
+	}
+	// "ans" always holds the last successful result
+	c.vars["ans"] = value
+	return value, nil
+}
+
+// printError shows the input with a caret under the failing column. It
+// writes to stderr so results on stdout can be piped without diagnostics.
+func printError(line string, err error) {
+	var parseErr ParseError
+	if errors.As(err, &parseErr) {
+		fmt.Fprintln(os.Stderr, "  "+line)
+		fmt.Fprintln(os.Stderr, "  "+strings.Repeat(" ", parseErr.Col-1)+"^")
+	}
+	fmt.Fprintln(os.Stderr, "Error:", err)
+}
+
+func repl(calc *Calculator) {
+	fmt.Println("Simple calculator. Operators: + - * / % ^ ( ). Assign with x = 3*4.")
+	fmt.Println("Type 'exit' or press Ctrl-D to quit.")
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print("> ")
+		if !scanner.Scan() {
+			fmt.Println()
+			return
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == "exit" || line == "quit" {
+			return
+		}
+
+		result, err := calc.Eval(line)
+		if err != nil {
+			printError(line, err)
+			continue
+		}
+		fmt.Println(strconv.FormatFloat(result, 'g', -1, 64))
+	}
+}
+
+func main() {
+	calc := NewCalculator()
+
+	if len(os.Args) < 2 {
+		repl(calc)
+		return
+	}
+
+	// Allow both `main.go "1 + 2"` and `main.go 1 + 2`
+	line := strings.Join(os.Args[1:], " ")
+	result, err := calc.Eval(line)
+	if err != nil {
+		printError(line, err)
+		os.Exit(1)
+	}
+	fmt.Println(strconv.FormatFloat(result, 'g', -1, 64))
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tokens, err := tokenize("x = 2.5e-1*(y+10)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		kind tokenKind
+		text string
+		col  int
+	}{
+		{tokIdent, "x", 1},
+		{tokAssign, "=", 3},
+		{tokNumber, "2.5e-1", 5},
+		{tokOp, "*", 11},
+		{tokLParen, "(", 12},
+		{tokIdent, "y", 13},
+		{tokOp, "+", 14},
+		{tokNumber, "10", 15},
+		{tokRParen, ")", 17},
+		{tokEOF, "", 18},
+	}
+	if len(tokens) != len(want) {
+		t.Fatalf("tokenize returned %d tokens; want %d: %+v", len(tokens), len(want), tokens)
+	}
+	for i, w := range want {
+		got := tokens[i]
+		if got.Kind != w.kind || got.Text != w.text || got.Col != w.col {
+			t.Errorf("token %d = {%v %q col %d}; want {%v %q col %d}", i, got.Kind, got.Text, got.Col, w.kind, w.text, w.col)
+		}
+	}
+	if tokens[2].Value != 0.25 {
+		t.Errorf("2.5e-1 = %v; want 0.25", tokens[2].Value)
+	}
+}
+
+func TestEval(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  float64
+	}{
+		{"1 + 2", 3},
+		{"2 + 3 * 4", 14},
+		{"(2 + 3) * 4", 20},
+		{"10 - 4 - 3", 3},
+		{"100 / 10 / 5", 2},
+		{"7 % 4", 3},
+		{"2 ^ 3 ^ 2", 512},
+		{"2 ^ -1", 0.5},
+		{"-2 ^ 2", -4},
+		{"--3", 3},
+		{"+4", 4},
+		{"1e3 + .5", 1000.5},
+		{"pi", math.Pi},
+		{"2 * e", 2 * math.E},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := NewCalculator().Eval(tc.input)
+			if err != nil {
+				t.Fatalf("Eval(%q) error: %v", tc.input, err)
+			}
+			if got != tc.want {
+				t.Errorf("Eval(%q) = %v; want %v", tc.input, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	testCases := []struct {
+		input   string
+		wantCol int
+		wantMsg string
+	}{
+		{"1 +", 4, "unexpected end of input"},
+		{"(1 + 2", 7, "expected ')' to close '(' at column 1"},
+		{"1 + 2)", 6, `unexpected ")"`},
+		{"3 $ 4", 3, `unexpected character '$'`},
+		{"1..2", 1, `invalid number "1..2"`},
+		{"5 / (2 - 2)", 3, "division by zero"},
+		{"5 % 0", 3, "modulo by zero"},
+		{"x + 1", 1, `undefined variable "x"`},
+		{"* 2", 1, `unexpected "*"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			_, err := NewCalculator().Eval(tc.input)
+			var parseErr ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Eval(%q) error = %v; want a ParseError", tc.input, err)
+			}
+			if parseErr.Col != tc.wantCol || parseErr.Message != tc.wantMsg {
+				t.Errorf("Eval(%q) error = %v; want column %d: %s", tc.input, err, tc.wantCol, tc.wantMsg)
+			}
+		})
+	}
+}
+
+func TestCalculatorVariables(t *testing.T) {
+	calc := NewCalculator()
+	steps := []struct {
+		input string
+		want  float64
+	}{
+		{"x = 3 * 4", 12},
+		{"x + 1", 13},
+		{"ans * 2", 26},
+		{"y = x / ans", 12.0 / 26},
+		{"pi = 3", 3},
+		{"pi", 3},
+	}
+	for _, step := range steps {
+		got, err := calc.Eval(step.input)
+		if err != nil {
+			t.Fatalf("Eval(%q) error: %v", step.input, err)
+		}
+		if got != step.want {
+			t.Errorf("Eval(%q) = %v; want %v", step.input, got, step.want)
+		}
+	}
+
+	// A failed line leaves variables and ans untouched
+	if _, err := calc.Eval("x = 1 / 0"); err == nil {
+		t.Fatal("Eval(x = 1 / 0) succeeded")
+	}
+	if got, _ := calc.Eval("x + ans"); got != 12+3 {
+		t.Errorf("after a failed assignment x + ans = %v; want 15", got)
+	}
+}
@@ -1,40 +1,376 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"math"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 )
 
-func main() {
-	if len(os.Args) != 3 {
-		fmt.Println("Usage: go run main.go <temp> <unit>")
-		fmt.Println("Example: go run main.go 25 C")
-		return
+// Exit codes so scripts can tell failures apart. Usage errors exit with 2,
+// as the flag package does for unknown flags.
+const (
+	exitOK           = 0
+	exitFailure      = 1 // reading input or writing output failed
+	exitUsage        = 2
+	exitInvalidValue = 3
+	exitInvalidUnit  = 4
+	exitIncompatible = 5
+)
+
+var (
+	ErrInvalidValue = errors.New("invalid value")
+	ErrUnknownUnit  = errors.New("unknown unit")
+	ErrIncompatible = errors.New("incompatible units")
+)
+
+// Unit converts to and from its family's base unit
+type Unit struct {
+	Symbol   string
+	Name     string
+	Aliases  []string
+	ToBase   func(float64) float64
+	FromBase func(float64) float64
+	family   *Family
+}
+
+// Family groups units that can be converted into each other
+type Family struct {
+	Name  string
+	Base  string
+	Units []*Unit
+}
+
+// linear builds a unit that is a constant multiple of the base unit
+func linear(symbol, name string, factor float64, aliases ...string) *Unit {
+	return &Unit{
+		Symbol:   symbol,
+		Name:     name,
+		Aliases:  aliases,
+		ToBase:   func(v float64) float64 { return v * factor },
+		FromBase: func(v float64) float64 { return v / factor },
 	}
+}
+
+// Registry looks units up by symbol, name, or alias (case-insensitive)
+type Registry struct {
+	families []*Family
+	units    map[string]*Unit
+}
+
+func NewRegistry() *Registry {
+	return &Registry{units: make(map[string]*Unit)}
+}
 
-	tempStr := os.Args[1]
-	unit := os.Args[2]
+// Register adds a family and indexes all of its units
+func (r *Registry) Register(family *Family) error {
+	for _, u := range family.Units {
+		u.family = family
+		keys := append([]string{u.Symbol, u.Name}, u.Aliases...)
+		for _, key := range keys {
+			key = strings.ToLower(key)
+			if existing, ok := r.units[key]; ok && existing != u {
+				return fmt.Errorf("unit key %q already registered by %s", key, existing.Name)
+			}
+			r.units[key] = u
+		}
+	}
+	r.families = append(r.families, family)
+	return nil
+}
+
+// Lookup finds a unit by any of its names
+func (r *Registry) Lookup(name string) (*Unit, error) {
+	u, ok := r.units[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownUnit, name)
+	}
+	return u, nil
+}
 
-	temp, err := strconv.ParseFloat(tempStr, 64)
+// Convert converts value from one unit to another in the same family
+func (r *Registry) Convert(value float64, from, to string) (float64, error) {
+	src, err := r.Lookup(from)
+	if err != nil {
+		return 0, err
+	}
+	dst, err := r.Lookup(to)
 	if err != nil {
-		fmt.Println("Error: invalid temperature value")
-		return
+		return 0, err
+	}
+	if src.family != dst.family {
+		return 0, fmt.Errorf("%w: %s is %s but %s is %s",
+			ErrIncompatible, src.Symbol, src.family.Name, dst.Symbol, dst.family.Name)
+	}
+	return dst.FromBase(src.ToBase(value)), nil
+}
+
+func defaultRegistry() *Registry {
+	temperature := &Family{
+		Name: "temperature",
+		Base: "K",
+		Units: []*Unit{
+			{
+				Symbol: "C", Name: "celsius",
+				ToBase:   func(v float64) float64 { return v + 273.15 },
+				FromBase: func(v float64) float64 { return v - 273.15 },
+			},
+			{
+				Symbol: "F", Name: "fahrenheit",
+				ToBase:   func(v float64) float64 { return (v + 459.67) * 5 / 9 },
+				FromBase: func(v float64) float64 { return v*9/5 - 459.67 },
+			},
+			linear("K", "kelvin", 1),
+			linear("R", "rankine", 5.0/9.0),
+		},
+	}
+
+	length := &Family{
+		Name: "length",
+		Base: "m",
+		Units: []*Unit{
+			linear("mm", "millimeter", 0.001, "millimeters"),
+			linear("cm", "centimeter", 0.01, "centimeters"),
+			linear("m", "meter", 1, "meters"),
+			linear("km", "kilometer", 1000, "kilometers"),
+			linear("in", "inch", 0.0254, "inches"),
+			linear("ft", "foot", 0.3048, "feet"),
+			linear("yd", "yard", 0.9144, "yards"),
+			linear("mi", "mile", 1609.344, "miles"),
+			linear("nmi", "nautical-mile", 1852, "nautical-miles"),
+		},
 	}
 
-	var result float64
-	var resultUnit string
+	mass := &Family{
+		Name: "mass",
+		Base: "kg",
+		Units: []*Unit{
+			linear("mg", "milligram", 1e-6, "milligrams"),
+			linear("g", "gram", 0.001, "grams"),
+			linear("kg", "kilogram", 1, "kilograms"),
+			linear("t", "tonne", 1000, "tonnes"),
+			linear("oz", "ounce", 0.028349523125, "ounces"),
+			linear("lb", "pound", 0.45359237, "pounds", "lbs"),
+			linear("st", "stone", 6.35029318, "stones"),
+		},
+	}
+
+	duration := &Family{
+		Name: "duration",
+		Base: "s",
+		Units: []*Unit{
+			linear("ns", "nanosecond", 1e-9, "nanoseconds"),
+			linear("us", "microsecond", 1e-6, "microseconds", "µs"),
+			linear("ms", "millisecond", 1e-3, "milliseconds"),
+			linear("s", "second", 1, "seconds", "sec"),
+			linear("min", "minute", 60, "minutes"),
+			linear("h", "hour", 3600, "hours", "hr"),
+			linear("d", "day", 86400, "days"),
+			linear("wk", "week", 604800, "weeks"),
+		},
+	}
 
-	if unit == "C" {
-		result = (temp * 9 / 5) + 32
-		resultUnit = "F"
-	} else if unit == "F" {
-		result = (temp - 32) * 5 / 9
-		resultUnit = "C"
-	} else {
-		fmt.Println("Error: Unit must be 'C' or 'F'")
-		return
+	r := NewRegistry()
+	for _, f := range []*Family{temperature, length, mass, duration} {
+		if err := r.Register(f); err != nil {
+			panic(err) // built-in tables are static, so this is a programming error
+		}
 	}
+	return r
+}
+
+// Result is one conversion, as printed in text or JSON mode
+type Result struct {
+	Input  string  `json:"input,omitempty"`
+	Value  float64 `json:"value"`
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Result float64 `json:"result"`
+	Error  string  `json:"error,omitempty"`
+	Code   int     `json:"code,omitempty"`
+}
+
+// exitCode maps a conversion error to the process exit code
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, ErrInvalidValue):
+		return exitInvalidValue
+	case errors.Is(err, ErrUnknownUnit):
+		return exitInvalidUnit
+	case errors.Is(err, ErrIncompatible):
+		return exitIncompatible
+	}
+	return exitUsage
+}
 
-	fmt.Printf("%.2f%s = %.2f%s\n", temp, unit, result, resultUnit)
+// parseValue accepts finite numbers only; ParseFloat also takes "NaN" and
+// "Inf", which no unit can measure and JSON cannot encode
+func parseValue(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidValue, s)
+	}
+	return v, nil
+}
+
+// convertOne converts a single value and records any error in the result
+func (r *Registry) convertOne(input, valueStr, from, to string) Result {
+	res := Result{Input: input, From: from, To: to}
+
+	value, err := parseValue(valueStr)
+	if err == nil {
+		res.Value = value
+		res.Result, err = r.Convert(value, from, to)
+	}
+	if err == nil && math.IsInf(res.Result, 0) {
+		err = fmt.Errorf("%w: %s%s is out of range in %s", ErrInvalidValue, valueStr, from, to)
+		res.Result = 0
+	}
+	if err != nil {
+		res.Error = err.Error()
+		res.Code = exitCode(err)
+	}
+	return res
+}
+
+// parseLine parses "value unit -> unit", e.g. "25 C -> F"
+func (r *Registry) parseLine(line string) Result {
+	left, to, ok := strings.Cut(line, "->")
+	fields := strings.Fields(left)
+	if !ok || len(fields) != 2 || strings.TrimSpace(to) == "" {
+		err := fmt.Errorf("expected 'value unit -> unit', got %q", line)
+		return Result{Input: line, Error: err.Error(), Code: exitUsage}
+	}
+	return r.convertOne(line, fields[0], fields[1], strings.TrimSpace(to))
+}
+
+// writeResult prints res to out. In text mode a failed conversion is a
+// diagnostic and goes to errOut instead; in JSON mode it stays in the
+// stream as a record with "error" and "code" set.
+func writeResult(out, errOut io.Writer, res Result, asJSON bool) error {
+	if asJSON {
+		// One object per line so output can be streamed
+		enc := json.NewEncoder(out)
+		enc.SetEscapeHTML(false)
+		return enc.Encode(res)
+	}
+	if res.Error != "" {
+		_, err := fmt.Fprintf(errOut, "Error: %s\n", res.Error)
+		return err
+	}
+	// %g keeps small results such as 0.001km that %.2f would round to zero
+	_, err := fmt.Fprintf(out, "%.6g%s = %.6g%s\n", res.Value, res.From, res.Result, res.To)
+	return err
+}
+
+// mustWrite writes res to stdout, exiting if the output cannot be written
+func mustWrite(res Result, asJSON bool) {
+	if err := writeResult(os.Stdout, os.Stderr, res, asJSON); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing result: %v\n", err)
+		os.Exit(exitFailure)
+	}
+}
+
+// runBatch converts every line on in and returns the first non-zero exit code
+func (r *Registry) runBatch(in io.Reader, out, errOut io.Writer, asJSON bool) int {
+	code := exitOK
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		res := r.parseLine(line)
+		if err := writeResult(out, errOut, res, asJSON); err != nil {
+			fmt.Fprintf(errOut, "Error writing result: %v\n", err)
+			return exitFailure
+		}
+		if code == exitOK {
+			code = res.Code
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(errOut, "Error reading input: %v\n", err)
+		return exitFailure
+	}
+	return code
+}
+
+func (r *Registry) printUnits(w io.Writer) {
+	for _, f := range r.families {
+		symbols := make([]string, len(f.Units))
+		for i, u := range f.Units {
+			symbols[i] = u.Symbol
+		}
+		fmt.Fprintf(w, "  %-12s %s\n", f.Name, strings.Join(symbols, ", "))
+	}
+}
+
+// usage is printed for a wrong number of arguments, so it goes to stderr
+func usage(r *Registry) {
+	w := os.Stderr
+	fmt.Fprintln(w, "Usage: go run main.go [-json] <value> <from> [to]")
+	fmt.Fprintln(w, "       go run main.go [-json] -batch < conversions.txt")
+	fmt.Fprintln(w, "Examples:")
+	fmt.Fprintln(w, "  go run main.go 25 C        (converts to every other temperature unit)")
+	fmt.Fprintln(w, "  go run main.go 10 km mi")
+	fmt.Fprintln(w, "  echo '3 h -> min' | go run main.go -batch -json")
+	fmt.Fprintln(w, "Units:")
+	r.printUnits(w)
+}
+
+func main() {
+	asJSON := flag.Bool("json", false, "print results as JSON lines")
+	batch := flag.Bool("batch", false, "read 'value unit -> unit' lines from stdin")
+	flag.Parse()
+
+	registry := defaultRegistry()
+	args := flag.Args()
+
+	if *batch {
+		os.Exit(registry.runBatch(os.Stdin, os.Stdout, os.Stderr, *asJSON))
+	}
+
+	switch len(args) {
+	case 3:
+		res := registry.convertOne("", args[0], args[1], args[2])
+		mustWrite(res, *asJSON)
+		os.Exit(res.Code)
+	case 2:
+		// No target given: show the value in every other unit of its family
+		from, err := registry.Lookup(args[1])
+		if err != nil {
+			mustWrite(Result{Error: err.Error(), Code: exitCode(err)}, *asJSON)
+			os.Exit(exitCode(err))
+		}
+		targets := make([]string, 0, len(from.family.Units))
+		for _, u := range from.family.Units {
+			if u != from {
+				targets = append(targets, u.Symbol)
+			}
+		}
+		sort.Strings(targets)
+		code := exitOK
+		for _, to := range targets {
+			res := registry.convertOne("", args[0], from.Symbol, to)
+			mustWrite(res, *asJSON)
+			if res.Code != exitOK {
+				code = res.Code
+				break
+			}
+		}
+		os.Exit(code)
+	default:
+		usage(registry)
+		os.Exit(exitUsage)
+	}
 }
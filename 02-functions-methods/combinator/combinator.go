@@ -0,0 +1,173 @@
+// Package combinator provides generic higher-order functions: composition,
+// partial application, memoization, once, debounce, retry, and middleware
+// chaining for context-aware handlers.
+package combinator
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"grok-study-plan/06-error-handling/retry"
+)
+
+// Compose returns g∘f, i.e. a function computing g(f(a))
+func Compose[A, B, C any](g func(B) C, f func(A) B) func(A) C {
+	return func(a A) C {
+		return g(f(a))
+	}
+}
+
+// Pipe returns a function computing g(f(a)); it reads left to right
+func Pipe[A, B, C any](f func(A) B, g func(B) C) func(A) C {
+	return func(a A) C {
+		return g(f(a))
+	}
+}
+
+// Chain pipes a value through any number of same-typed functions in order
+func Chain[T any](fns ...func(T) T) func(T) T {
+	return func(v T) T {
+		for _, fn := range fns {
+			v = fn(v)
+		}
+		return v
+	}
+}
+
+// Identity returns its argument unchanged
+func Identity[T any](v T) T {
+	return v
+}
+
+// Partial fixes the first argument of a two-argument function
+func Partial[A, B, R any](f func(A, B) R, a A) func(B) R {
+	return func(b B) R {
+		return f(a, b)
+	}
+}
+
+// PartialRight fixes the second argument of a two-argument function
+func PartialRight[A, B, R any](f func(A, B) R, b B) func(A) R {
+	return func(a A) R {
+		return f(a, b)
+	}
+}
+
+// Curry turns f(a, b) into f(a)(b)
+func Curry[A, B, R any](f func(A, B) R) func(A) func(B) R {
+	return func(a A) func(B) R {
+		return func(b B) R {
+			return f(a, b)
+		}
+	}
+}
+
+// Curry3 turns f(a, b, c) into f(a)(b)(c)
+func Curry3[A, B, C, R any](f func(A, B, C) R) func(A) func(B) func(C) R {
+	return func(a A) func(B) func(C) R {
+		return func(b B) func(C) R {
+			return func(c C) R {
+				return f(a, b, c)
+			}
+		}
+	}
+}
+
+// Uncurry turns f(a)(b) back into f(a, b)
+func Uncurry[A, B, R any](f func(A) func(B) R) func(A, B) R {
+	return func(a A, b B) R {
+		return f(a)(b)
+	}
+}
+
+// Flip swaps the arguments of a two-argument function
+func Flip[A, B, R any](f func(A, B) R) func(B, A) R {
+	return func(b B, a A) R {
+		return f(a, b)
+	}
+}
+
+// Once returns a function that calls f on first use and then keeps
+// returning the same result. It is safe for concurrent use.
+func Once[T any](f func() T) func() T {
+	var (
+		once   sync.Once
+		result T
+	)
+	return func() T {
+		once.Do(func() {
+			result = f()
+		})
+		return result
+	}
+}
+
+// OnceErr is like Once but only caches a successful result, so a failed
+// initialisation is attempted again on the next call.
+func OnceErr[T any](f func() (T, error)) func() (T, error) {
+	var (
+		mu     sync.Mutex
+		done   bool
+		result T
+	)
+	return func() (T, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if done {
+			return result, nil
+		}
+		v, err := f()
+		if err != nil {
+			return v, err
+		}
+		result, done = v, true
+		return result, nil
+	}
+}
+
+// Debounce returns a function that delays calling f until d has passed
+// without another call; f receives the argument of the last call.
+// The returned cancel function drops any pending call.
+func Debounce[T any](d time.Duration, f func(T)) (call func(T), cancel func()) {
+	var (
+		mu    sync.Mutex
+		timer *time.Timer
+	)
+
+	call = func(v T) {
+		mu.Lock()
+		defer mu.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(d, func() { f(v) })
+	}
+
+	cancel = func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if timer != nil {
+			timer.Stop()
+			timer = nil
+		}
+	}
+	return call, cancel
+}
+
+// Retry calls fn up to attempts times, at least once, until it succeeds or
+// ctx is cancelled. The waits grow from delay, doubling after each failure,
+// with full jitter. It is a shorthand for retry.DoValue with a fixed
+// policy; use the retry package directly for error classifiers, a time
+// budget or a fake clock. A delay of 0 means retry.DefaultInitialDelay.
+// When it gives up, the error is a *retry.Error that wraps the last failure.
+func Retry[T any](ctx context.Context, attempts int, delay time.Duration, fn func(context.Context) (T, error)) (T, error) {
+	policy := retry.Policy{
+		MaxAttempts:  max(attempts, 1),
+		InitialDelay: delay,
+		MaxDelay:     math.MaxInt64, // no cap on the doubling
+		Multiplier:   2,
+	}
+	return retry.DoValue(ctx, policy, fn)
+}
@@ -0,0 +1,374 @@
+package combinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"grok-study-plan/06-error-handling/retry"
+)
+
+func add(a, b int) int { return a + b }
+func mul(a, b int) int { return a * b }
+
+func TestComposeAndPipe(t *testing.T) {
+	double := Partial(mul, 2)
+	inc := Partial(add, 1)
+
+	// Compose applies right to left, Pipe left to right
+	if got := Compose(double, inc)(3); got != 8 {
+		t.Errorf("Compose(double, inc)(3) = %d; want 8", got)
+	}
+	if got := Pipe(double, inc)(3); got != 7 {
+		t.Errorf("Pipe(double, inc)(3) = %d; want 7", got)
+	}
+
+	// Type-changing pipeline built from smaller pieces
+	describe := Pipe(Pipe(double, strconv.Itoa), strings.NewReplacer("1", "one").Replace)
+	if got := describe(5); got != "one0" {
+		t.Errorf("describe(5) = %q; want %q", got, "one0")
+	}
+
+	if got := Chain(inc, double, inc)(1); got != 5 {
+		t.Errorf("Chain(inc, double, inc)(1) = %d; want 5", got)
+	}
+	if got := Chain[int]()(42); got != 42 {
+		t.Errorf("empty Chain = %d; want 42", got)
+	}
+}
+
+func TestPartialAndCurry(t *testing.T) {
+	sub := func(a, b int) int { return a - b }
+
+	testCases := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{"Partial", Partial(sub, 10)(3), 7},
+		{"PartialRight", PartialRight(sub, 10)(3), -7},
+		{"Curry", Curry(sub)(10)(3), 7},
+		{"Uncurry(Curry)", Uncurry(Curry(sub))(10, 3), 7},
+		{"Flip", Flip(sub)(10, 3), -7},
+		{"Curry3", Curry3(func(a, b, c int) int { return a*100 + b*10 + c })(1)(2)(3), 123},
+		{"Compose(Curry)", Compose(Curry(add)(1), Curry(mul)(3))(4), 13},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.got != tc.want {
+				t.Errorf("got %d; want %d", tc.got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMemoize(t *testing.T) {
+	calls := 0
+	square := Memoize(func(n int) int {
+		calls++
+		return n * n
+	}, nil)
+
+	for i := 0; i < 3; i++ {
+		if got := square(4); got != 16 {
+			t.Errorf("square(4) = %d; want 16", got)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("underlying function called %d times; want 1", calls)
+	}
+
+	// Memoized functions compose like any other function
+	calls = 0
+	plusOneSquared := Pipe(Partial(add, 1), square)
+	plusOneSquared(3)
+	plusOneSquared(3)
+	if calls != 0 {
+		t.Errorf("square(4) should already be cached, got %d calls", calls)
+	}
+}
+
+func TestMemoizeLRU(t *testing.T) {
+	cache := NewLRUCache[int, int](2)
+	calls := 0
+	f := Memoize(func(n int) int {
+		calls++
+		return n
+	}, cache)
+
+	f(1)
+	f(2)
+	f(1) // 1 becomes most recent
+	f(3) // evicts 2
+	f(1)
+	if calls != 3 {
+		t.Errorf("calls = %d; want 3", calls)
+	}
+	if _, ok := cache.Get(2); ok {
+		t.Error("key 2 should have been evicted")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("cache.Len() = %d; want 2", cache.Len())
+	}
+}
+
+func TestMemoizeRec(t *testing.T) {
+	calls := 0
+	fib := MemoizeRec(func(fib func(int) int, n int) int {
+		calls++
+		if n < 2 {
+			return n
+		}
+		return fib(n-1) + fib(n-2)
+	}, nil)
+
+	if got := fib(50); got != 12586269025 {
+		t.Errorf("fib(50) = %d; want 12586269025", got)
+	}
+	if calls != 51 {
+		t.Errorf("calls = %d; want 51", calls)
+	}
+}
+
+func TestMemoizeErr(t *testing.T) {
+	calls := 0
+	parse := MemoizeErr(func(s string) (int, error) {
+		calls++
+		return strconv.Atoi(s)
+	}, nil)
+
+	parse("x")
+	parse("x")
+	parse("7")
+	parse("7")
+	if calls != 3 {
+		t.Errorf("calls = %d; want 3 (errors are not cached)", calls)
+	}
+}
+
+func TestOnce(t *testing.T) {
+	var calls atomic.Int32
+	get := Once(func() int {
+		calls.Add(1)
+		return 42
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := get(); got != 42 {
+				t.Errorf("get() = %d; want 42", got)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d; want 1", calls.Load())
+	}
+}
+
+func TestOnceErr(t *testing.T) {
+	attempts := 0
+	load := OnceErr(func() (string, error) {
+		attempts++
+		if attempts < 2 {
+			return "", errors.New("not ready")
+		}
+		return "config", nil
+	})
+
+	if _, err := load(); err == nil {
+		t.Error("first load should fail")
+	}
+	for i := 0; i < 2; i++ {
+		if got, err := load(); err != nil || got != "config" {
+			t.Errorf("load() = %q, %v; want config, nil", got, err)
+		}
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d; want 2", attempts)
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		seen []int
+	)
+	done := make(chan struct{}, 1)
+
+	call, cancel := Debounce(20*time.Millisecond, func(v int) {
+		mu.Lock()
+		seen = append(seen, v)
+		mu.Unlock()
+		done <- struct{}{}
+	})
+	defer cancel()
+
+	for i := 1; i <= 5; i++ {
+		call(i)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("debounced function never ran")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(seen) != 1 || seen[0] != 5 {
+		t.Errorf("seen = %v; want [5]", seen)
+	}
+}
+
+func TestDebounceCancel(t *testing.T) {
+	var ran atomic.Bool
+	call, cancel := Debounce(10*time.Millisecond, func(struct{}) { ran.Store(true) })
+
+	call(struct{}{})
+	cancel()
+	time.Sleep(30 * time.Millisecond)
+
+	if ran.Load() {
+		t.Error("cancelled call should not run")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	attempts := 0
+	got, err := Retry(context.Background(), 3, time.Millisecond, func(ctx context.Context) (string, error) {
+		attempts++
+		if attempts < 3 {
+			return "", errors.New("flaky")
+		}
+		return "ok", nil
+	})
+	if err != nil || got != "ok" {
+		t.Errorf("Retry = %q, %v; want ok, nil", got, err)
+	}
+
+	attempts = 0
+	_, err = Retry(context.Background(), 2, time.Millisecond, func(ctx context.Context) (int, error) {
+		attempts++
+		return 0, errors.New("always")
+	})
+	if err == nil || attempts != 2 {
+		t.Errorf("Retry = %v after %d attempts; want error after 2", err, attempts)
+	}
+}
+
+func TestRetryAlwaysCallsOnce(t *testing.T) {
+	for _, attempts := range []int{0, -3} {
+		calls := 0
+		_, err := Retry(context.Background(), attempts, time.Millisecond, func(ctx context.Context) (int, error) {
+			calls++
+			return 0, errors.New("fail")
+		})
+		if calls != 1 || err == nil {
+			t.Errorf("Retry(attempts=%d) made %d calls, err %v; want 1 call and an error", attempts, calls, err)
+		}
+	}
+}
+
+func TestRetryWrapsLastError(t *testing.T) {
+	errLast := errors.New("last")
+	_, err := Retry(context.Background(), 2, time.Millisecond, func(ctx context.Context) (int, error) {
+		return 0, errLast
+	})
+	if !errors.Is(err, errLast) || !errors.Is(err, retry.ErrAttemptsExhausted) {
+		t.Errorf("err = %v; want it to wrap the last error and retry.ErrAttemptsExhausted", err)
+	}
+}
+
+func TestRetryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Retry(ctx, 5, time.Hour, func(ctx context.Context) (int, error) {
+		return 0, errors.New("fail")
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v; want context.Canceled", err)
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var trace []string
+	tag := func(name string) Middleware[int, int] {
+		return WithHook[int, int](
+			func(int) { trace = append(trace, name+":before") },
+			func(int, int, error) { trace = append(trace, name+":after") },
+		)
+	}
+
+	h := Use(Lift(Partial(mul, 2)), tag("outer"), tag("inner"))
+	if got, _ := h(context.Background(), 21); got != 42 {
+		t.Errorf("h(21) = %d; want 42", got)
+	}
+
+	want := "outer:before inner:before inner:after outer:after"
+	if got := strings.Join(trace, " "); got != want {
+		t.Errorf("trace = %q; want %q", got, want)
+	}
+}
+
+func TestMiddlewareComposition(t *testing.T) {
+	var calls atomic.Int32
+	flaky := func(ctx context.Context, n int) (string, error) {
+		// Fails every other call
+		if calls.Add(1)%2 == 1 {
+			return "", errors.New("transient")
+		}
+		return fmt.Sprint(n * n), nil
+	}
+
+	// Cache outside retry: a cached value skips the retry loop entirely
+	h := Use(flaky,
+		WithCache[int, string](nil),
+		WithRetry[int, string](3, time.Millisecond),
+		WithTimeout[int, string](time.Second),
+	)
+
+	for i := 0; i < 3; i++ {
+		got, err := h(context.Background(), 9)
+		if err != nil || got != "81" {
+			t.Errorf("h(9) = %q, %v; want 81, nil", got, err)
+		}
+	}
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d; want 2 (one failure, one success, then cached)", calls.Load())
+	}
+
+	// Then chains handlers with different types
+	length := Then(Handler[int, string](h), Lift(func(s string) int { return len(s) }))
+	if got, err := length(context.Background(), 100); err != nil || got != 5 {
+		t.Errorf("length(100) = %d, %v; want 5, nil", got, err)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	slow := func(ctx context.Context, _ struct{}) (int, error) {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(time.Second):
+			return 1, nil
+		}
+	}
+
+	h := Stack(WithTimeout[struct{}, int](5 * time.Millisecond))(slow)
+	if _, err := h(context.Background(), struct{}{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v; want context.DeadlineExceeded", err)
+	}
+}
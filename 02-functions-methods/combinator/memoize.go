@@ -0,0 +1,149 @@
+package combinator
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache is the storage used by Memoize. Implementations must be safe for
+// concurrent use if the memoized function is called from several goroutines.
+type Cache[K comparable, V any] interface {
+	Get(key K) (V, bool)
+	Set(key K, value V)
+}
+
+// MapCache is an unbounded cache backed by a map
+type MapCache[K comparable, V any] struct {
+	mu    sync.RWMutex
+	items map[K]V
+}
+
+func NewMapCache[K comparable, V any]() *MapCache[K, V] {
+	return &MapCache[K, V]{items: make(map[K]V)}
+}
+
+func (c *MapCache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.items[key]
+	return v, ok
+}
+
+func (c *MapCache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = value
+}
+
+// Len returns the number of cached entries
+func (c *MapCache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.items)
+}
+
+// LRUCache keeps at most capacity entries, evicting the least recently used
+type LRUCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	items    map[K]*list.Element
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func NewLRUCache[K comparable, V any](capacity int) *LRUCache[K, V] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUCache[K, V]{
+		capacity: capacity,
+		order:    list.New(),
+		items:    make(map[K]*list.Element),
+	}
+}
+
+func (c *LRUCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.order.MoveToFront(el)
+		return el.Value.(lruEntry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (c *LRUCache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		el.Value = lruEntry[K, V]{key, value}
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(lruEntry[K, V]{key, value})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(lruEntry[K, V]).key)
+	}
+}
+
+// Len returns the number of cached entries
+func (c *LRUCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Memoize caches the results of f by argument. A nil cache uses a MapCache.
+//
+// Concurrent first calls for the same key may both run f; the cache simply
+// keeps whichever result is stored last.
+func Memoize[K comparable, V any](f func(K) V, cache Cache[K, V]) func(K) V {
+	if cache == nil {
+		cache = NewMapCache[K, V]()
+	}
+	return func(key K) V {
+		if v, ok := cache.Get(key); ok {
+			return v
+		}
+		v := f(key)
+		cache.Set(key, v)
+		return v
+	}
+}
+
+// MemoizeErr is like Memoize but does not cache failed calls
+func MemoizeErr[K comparable, V any](f func(K) (V, error), cache Cache[K, V]) func(K) (V, error) {
+	if cache == nil {
+		cache = NewMapCache[K, V]()
+	}
+	return func(key K) (V, error) {
+		if v, ok := cache.Get(key); ok {
+			return v, nil
+		}
+		v, err := f(key)
+		if err != nil {
+			return v, err
+		}
+		cache.Set(key, v)
+		return v, nil
+	}
+}
+
+// MemoizeRec memoizes a recursive function. f receives the memoized version
+// of itself so that recursive calls also hit the cache:
+//
+//	fib := MemoizeRec(func(fib func(int) int, n int) int { ... }, nil)
+func MemoizeRec[K comparable, V any](f func(self func(K) V, key K) V, cache Cache[K, V]) func(K) V {
+	var memo func(K) V
+	memo = Memoize(func(key K) V {
+		return f(memo, key)
+	}, cache)
+	return memo
+}
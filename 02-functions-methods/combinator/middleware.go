@@ -0,0 +1,104 @@
+package combinator
+
+import (
+	"context"
+	"time"
+)
+
+// Handler is a context-aware function from In to Out
+type Handler[In, Out any] func(ctx context.Context, in In) (Out, error)
+
+// Middleware wraps a handler with extra behaviour
+type Middleware[In, Out any] func(next Handler[In, Out]) Handler[In, Out]
+
+// Use wraps h with the given middleware. The first middleware is the
+// outermost, so Use(h, a, b) runs a, then b, then h.
+func Use[In, Out any](h Handler[In, Out], mws ...Middleware[In, Out]) Handler[In, Out] {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
+
+// Stack combines several middleware into one, outermost first
+func Stack[In, Out any](mws ...Middleware[In, Out]) Middleware[In, Out] {
+	return func(next Handler[In, Out]) Handler[In, Out] {
+		return Use(next, mws...)
+	}
+}
+
+// Lift turns a plain function into a handler that never fails
+func Lift[In, Out any](f func(In) Out) Handler[In, Out] {
+	return func(_ context.Context, in In) (Out, error) {
+		return f(in), nil
+	}
+}
+
+// Then runs h and feeds its result into next
+func Then[A, B, C any](h Handler[A, B], next Handler[B, C]) Handler[A, C] {
+	return func(ctx context.Context, a A) (C, error) {
+		b, err := h(ctx, a)
+		if err != nil {
+			var zero C
+			return zero, err
+		}
+		return next(ctx, b)
+	}
+}
+
+// WithRetry retries the wrapped handler using Retry
+func WithRetry[In, Out any](attempts int, delay time.Duration) Middleware[In, Out] {
+	return func(next Handler[In, Out]) Handler[In, Out] {
+		return func(ctx context.Context, in In) (Out, error) {
+			return Retry(ctx, attempts, delay, func(ctx context.Context) (Out, error) {
+				return next(ctx, in)
+			})
+		}
+	}
+}
+
+// WithTimeout gives each call its own deadline
+func WithTimeout[In, Out any](d time.Duration) Middleware[In, Out] {
+	return func(next Handler[In, Out]) Handler[In, Out] {
+		return func(ctx context.Context, in In) (Out, error) {
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+			return next(ctx, in)
+		}
+	}
+}
+
+// WithCache serves repeated inputs from cache; errors are not cached
+func WithCache[In comparable, Out any](cache Cache[In, Out]) Middleware[In, Out] {
+	if cache == nil {
+		cache = NewMapCache[In, Out]()
+	}
+	return func(next Handler[In, Out]) Handler[In, Out] {
+		return func(ctx context.Context, in In) (Out, error) {
+			if v, ok := cache.Get(in); ok {
+				return v, nil
+			}
+			v, err := next(ctx, in)
+			if err == nil {
+				cache.Set(in, v)
+			}
+			return v, err
+		}
+	}
+}
+
+// WithHook calls before and after around each call; either may be nil
+func WithHook[In, Out any](before func(In), after func(In, Out, error)) Middleware[In, Out] {
+	return func(next Handler[In, Out]) Handler[In, Out] {
+		return func(ctx context.Context, in In) (Out, error) {
+			if before != nil {
+				before(in)
+			}
+			out, err := next(ctx, in)
+			if after != nil {
+				after(in, out, err)
+			}
+			return out, err
+		}
+	}
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"fmt"
-	"math"
+
+	"grok-study-plan/02-functions-methods/combinator"
+	"grok-study-plan/04-structs-interfaces/geometry"
+	"grok-study-plan/06-error-handling/recovery"
 )
 
 // Basic function with parameters and return value
@@ -58,36 +61,21 @@ func (p *Person) Birthday() {
 	p.Age++
 }
 
-// Rectangle struct for method demonstration
-type Rectangle struct {
-	Width, Height float64
-}
-
-// Method with value receiver
-func (r Rectangle) Area() float64 {
-	return r.Width * r.Height
-}
-
-// Method with pointer receiver
-func (r *Rectangle) Scale(factor float64) {
-	r.Width *= factor
-	r.Height *= factor
-}
+// Rectangle and Circle come from the shared geometry package, which adds
+// positions, perimeters, containment tests and in-place transforms.
+// Rectangle.Area has a value receiver; Rectangle.Scale has a pointer receiver.
+// Scale resizes about the center, so the rectangle's Min corner moves too.
+type (
+	Rectangle = geometry.Rectangle
+	Circle    = geometry.Circle
+)
 
-// Interface definition
+// Interface definition - any type with an Area method satisfies it,
+// including every shape in the geometry package
 type Shape interface {
 	Area() float64
 }
 
-// Circle struct implementing Shape interface
-type Circle struct {
-	Radius float64
-}
-
-func (c Circle) Area() float64 {
-	return math.Pi * c.Radius * c.Radius
-}
-
 // Function demonstrating defer
 func demonstrateDefer() {
 	fmt.Println("Start")
@@ -96,13 +84,11 @@ func demonstrateDefer() {
 	fmt.Println("End")
 }
 
-// Function demonstrating panic and recover
+// Function demonstrating panic and recover. recovery.Recover calls recover
+// in the deferred call and stores the panic as a *recovery.PanicError, which
+// keeps the panic value and stack instead of flattening them into a string.
 func safeDivision(a, b int) (result int, err error) {
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("panic recovered: %v", r)
-		}
-	}()
+	defer recovery.Recover(&err)
 
 	if b == 0 {
 		panic("division by zero")
@@ -179,7 +165,7 @@ func main() {
 
 	result, err = safeDivision(10, 0)
 	if err != nil {
-		fmt.Printf("Recovered from panic: %v\n", err)
+		fmt.Printf("Recovered from panic: %v (IsPanic=%t)\n", err, recovery.IsPanic(err))
 	}
 
 	// Anonymous function
@@ -188,4 +174,12 @@ func main() {
 
 	multiplyResult := applyOperation(3, 4, func(a, b int) int { return a * b })
 	fmt.Printf("3 * 4 = %d (using anonymous function)\n", multiplyResult)
+
+	// The combinator package generalises applyOperation-style helpers
+	double := combinator.Partial(func(a, b int) int { return a * b }, 2)
+	addThreeThenDouble := combinator.Pipe(combinator.Partial(add, 3), double)
+	fmt.Printf("(4 + 3) * 2 = %d (using combinator.Pipe)\n", addThreeThenDouble(4))
+
+	memoFactorial := combinator.Memoize(factorial, nil)
+	fmt.Printf("10! = %d (memoized)\n", memoFactorial(10))
 }
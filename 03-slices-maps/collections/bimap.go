@@ -0,0 +1,105 @@
+package collections
+
+import (
+	"iter"
+	"maps"
+)
+
+// BiMap is a one-to-one map that can be looked up in both directions.
+// The zero value is not usable; create one with NewBiMap.
+type BiMap[K, V comparable] struct {
+	forward  map[K]V
+	backward map[V]K
+}
+
+func NewBiMap[K, V comparable]() *BiMap[K, V] {
+	return &BiMap[K, V]{
+		forward:  make(map[K]V),
+		backward: make(map[V]K),
+	}
+}
+
+// Set maps key to value. Any existing pairing of key or of value is removed
+// first so the mapping stays one-to-one.
+func (b *BiMap[K, V]) Set(key K, value V) {
+	if old, ok := b.forward[key]; ok {
+		delete(b.backward, old)
+	}
+	if old, ok := b.backward[value]; ok {
+		delete(b.forward, old)
+	}
+	b.forward[key] = value
+	b.backward[value] = key
+}
+
+// Get returns the value for key
+func (b *BiMap[K, V]) Get(key K) (V, bool) {
+	v, ok := b.forward[key]
+	return v, ok
+}
+
+// GetKey returns the key for value
+func (b *BiMap[K, V]) GetKey(value V) (K, bool) {
+	k, ok := b.backward[value]
+	return k, ok
+}
+
+// Has reports whether key is present
+func (b *BiMap[K, V]) Has(key K) bool {
+	_, ok := b.forward[key]
+	return ok
+}
+
+// HasValue reports whether value is present
+func (b *BiMap[K, V]) HasValue(value V) bool {
+	_, ok := b.backward[value]
+	return ok
+}
+
+// Delete removes key and its value, reporting whether key was present
+func (b *BiMap[K, V]) Delete(key K) bool {
+	v, ok := b.forward[key]
+	if !ok {
+		return false
+	}
+	delete(b.forward, key)
+	delete(b.backward, v)
+	return true
+}
+
+// DeleteValue removes value and its key, reporting whether value was present
+func (b *BiMap[K, V]) DeleteValue(value V) bool {
+	return b.Inverse().Delete(value)
+}
+
+// Inverse returns a view with keys and values swapped. It shares storage
+// with b, so changes through either are visible in both.
+func (b *BiMap[K, V]) Inverse() *BiMap[V, K] {
+	return &BiMap[V, K]{forward: b.backward, backward: b.forward}
+}
+
+// Len returns the number of pairs
+func (b *BiMap[K, V]) Len() int {
+	return len(b.forward)
+}
+
+// Clear removes every pair
+func (b *BiMap[K, V]) Clear() {
+	clear(b.forward)
+	clear(b.backward)
+}
+
+// All yields key/value pairs in unspecified order
+func (b *BiMap[K, V]) All() iter.Seq2[K, V] {
+	return maps.All(b.forward)
+}
+
+// Keys yields keys in unspecified order
+func (b *BiMap[K, V]) Keys() iter.Seq[K] {
+	return maps.Keys(b.forward)
+}
+
+// Values yields values in unspecified order
+func (b *BiMap[K, V]) Values() iter.Seq[V] {
+	return maps.Keys(b.backward)
+}
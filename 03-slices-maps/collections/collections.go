@@ -0,0 +1,178 @@
+// Package collections provides generic helpers for slices, maps and
+// iterators. Transformations take and return iter.Seq values so they can be
+// chained lazily; nothing runs until the final sequence is ranged over or
+// collected with slices.Collect.
+package collections
+
+import (
+	"iter"
+	"slices"
+)
+
+// Map applies f to every element
+func Map[T, U any](seq iter.Seq[T], f func(T) U) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for v := range seq {
+			if !yield(f(v)) {
+				return
+			}
+		}
+	}
+}
+
+// Filter keeps the elements for which keep returns true
+func Filter[T any](seq iter.Seq[T], keep func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if keep(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Reduce folds the sequence into a single value, starting from initial
+func Reduce[T, A any](seq iter.Seq[T], initial A, f func(A, T) A) A {
+	acc := initial
+	for v := range seq {
+		acc = f(acc, v)
+	}
+	return acc
+}
+
+// Take yields at most n elements
+func Take[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		i := 0
+		for v := range seq {
+			if !yield(v) {
+				return
+			}
+			i++
+			if i == n {
+				return
+			}
+		}
+	}
+}
+
+// Chunk splits the sequence into consecutive slices of size n; the last
+// chunk may be shorter. Each yielded slice is freshly allocated.
+func Chunk[T any](seq iter.Seq[T], n int) iter.Seq[[]T] {
+	if n < 1 {
+		panic("collections: Chunk size must be at least 1")
+	}
+	return func(yield func([]T) bool) {
+		chunk := make([]T, 0, n)
+		for v := range seq {
+			chunk = append(chunk, v)
+			if len(chunk) == n {
+				if !yield(chunk) {
+					return
+				}
+				chunk = make([]T, 0, n)
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// Window yields every run of n consecutive elements (a sliding window).
+// Sequences shorter than n yield nothing. Each yielded slice is a copy.
+func Window[T any](seq iter.Seq[T], n int) iter.Seq[[]T] {
+	if n < 1 {
+		panic("collections: Window size must be at least 1")
+	}
+	return func(yield func([]T) bool) {
+		buf := make([]T, 0, n)
+		for v := range seq {
+			if len(buf) == n {
+				buf = append(buf[:0], buf[1:]...)
+			}
+			buf = append(buf, v)
+			if len(buf) == n && !yield(slices.Clone(buf)) {
+				return
+			}
+		}
+	}
+}
+
+// Distinct yields each value the first time it appears
+func Distinct[T comparable](seq iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		seen := make(map[T]struct{})
+		for v := range seq {
+			if _, ok := seen[v]; ok {
+				continue
+			}
+			seen[v] = struct{}{}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Zip pairs up elements of a and b, stopping at the shorter sequence
+func Zip[A, B any](a iter.Seq[A], b iter.Seq[B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		nextB, stop := iter.Pull(b)
+		defer stop()
+		for va := range a {
+			vb, ok := nextB()
+			if !ok || !yield(va, vb) {
+				return
+			}
+		}
+	}
+}
+
+// GroupBy collects elements into slices keyed by key(element), keeping the
+// original order within each group
+func GroupBy[T any, K comparable](seq iter.Seq[T], key func(T) K) map[K][]T {
+	groups := make(map[K][]T)
+	for v := range seq {
+		k := key(v)
+		groups[k] = append(groups[k], v)
+	}
+	return groups
+}
+
+// Partition splits elements into those matching pred and the rest
+func Partition[T any](seq iter.Seq[T], pred func(T) bool) (matched, rest []T) {
+	for v := range seq {
+		if pred(v) {
+			matched = append(matched, v)
+		} else {
+			rest = append(rest, v)
+		}
+	}
+	return matched, rest
+}
+
+// RemoveAt removes the element at index i in place and returns the shortened
+// slice. Like append, it reuses the underlying array.
+func RemoveAt[S ~[]T, T any](s S, i int) S {
+	return slices.Delete(s, i, i+1)
+}
+
+// InsertAt inserts values at index i and returns the resulting slice
+func InsertAt[S ~[]T, T any](s S, i int, values ...T) S {
+	return slices.Insert(s, i, values...)
+}
+
+// Copy returns a new slice with the same elements, so later changes to
+// either slice do not affect the other. Copy(nil) returns nil.
+func Copy[S ~[]T, T any](s S) S {
+	if s == nil {
+		return nil
+	}
+	dst := make(S, len(s))
+	copy(dst, s)
+	return dst
+}
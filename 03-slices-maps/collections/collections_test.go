@@ -0,0 +1,182 @@
+package collections
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMapFilterReduce(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5, 6}
+
+	evens := slices.Collect(Filter(slices.Values(numbers), func(n int) bool { return n%2 == 0 }))
+	if want := []int{2, 4, 6}; !slices.Equal(evens, want) {
+		t.Errorf("Filter = %v; want %v", evens, want)
+	}
+
+	squares := slices.Collect(Map(slices.Values(numbers), func(n int) int { return n * n }))
+	if want := []int{1, 4, 9, 16, 25, 36}; !slices.Equal(squares, want) {
+		t.Errorf("Map = %v; want %v", squares, want)
+	}
+
+	sum := Reduce(slices.Values(numbers), 0, func(acc, n int) int { return acc + n })
+	if sum != 21 {
+		t.Errorf("Reduce sum = %d; want 21", sum)
+	}
+
+	// Reduce into a different type
+	joined := Reduce(slices.Values([]string{"a", "b", "c"}), "", func(acc, s string) string { return acc + s })
+	if joined != "abc" {
+		t.Errorf("Reduce join = %q; want %q", joined, "abc")
+	}
+}
+
+func TestLazyChaining(t *testing.T) {
+	evaluated := 0
+	source := func(yield func(int) bool) {
+		for i := 1; ; i++ {
+			evaluated++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	// Works on an infinite sequence because every stage is lazy
+	pipeline := Take(Map(Filter(source, func(n int) bool { return n%3 == 0 }), func(n int) string {
+		return strings.Repeat("x", n/3)
+	}), 3)
+
+	got := slices.Collect(pipeline)
+	if want := []string{"x", "xx", "xxx"}; !slices.Equal(got, want) {
+		t.Errorf("pipeline = %v; want %v", got, want)
+	}
+	if evaluated != 9 {
+		t.Errorf("source evaluated %d elements; want 9", evaluated)
+	}
+}
+
+func TestChunkAndWindow(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input []int
+		size  int
+		chunk [][]int
+		win   [][]int
+	}{
+		{"even", []int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {3, 4}}, [][]int{{1, 2}, {2, 3}, {3, 4}}},
+		{"remainder", []int{1, 2, 3, 4, 5}, 3, [][]int{{1, 2, 3}, {4, 5}}, [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}},
+		{"short", []int{1}, 2, [][]int{{1}}, nil},
+		{"empty", nil, 2, nil, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			chunks := slices.Collect(Chunk(slices.Values(tc.input), tc.size))
+			if !reflect.DeepEqual(chunks, tc.chunk) {
+				t.Errorf("Chunk = %v; want %v", chunks, tc.chunk)
+			}
+			windows := slices.Collect(Window(slices.Values(tc.input), tc.size))
+			if !reflect.DeepEqual(windows, tc.win) {
+				t.Errorf("Window = %v; want %v", windows, tc.win)
+			}
+		})
+	}
+}
+
+func TestWindowCopies(t *testing.T) {
+	windows := slices.Collect(Window(slices.Values([]int{1, 2, 3, 4}), 2))
+	windows[0][0] = 99
+	if windows[1][0] != 2 {
+		t.Errorf("windows share storage: %v", windows)
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	got := slices.Collect(Distinct(slices.Values([]string{"b", "a", "b", "c", "a"})))
+	if want := []string{"b", "a", "c"}; !slices.Equal(got, want) {
+		t.Errorf("Distinct = %v; want %v", got, want)
+	}
+}
+
+func TestZip(t *testing.T) {
+	names := []string{"Alice", "Bob", "Charlie"}
+	ages := []int{25, 30}
+
+	var got []string
+	for name, age := range Zip(slices.Values(names), slices.Values(ages)) {
+		got = append(got, name+":"+strings.Repeat("|", age/10))
+	}
+	if want := []string{"Alice:||", "Bob:|||"}; !slices.Equal(got, want) {
+		t.Errorf("Zip = %v; want %v", got, want)
+	}
+}
+
+func TestGroupByAndPartition(t *testing.T) {
+	words := []string{"apple", "avocado", "banana", "blueberry", "cherry"}
+
+	groups := GroupBy(slices.Values(words), func(w string) byte { return w[0] })
+	want := map[byte][]string{
+		'a': {"apple", "avocado"},
+		'b': {"banana", "blueberry"},
+		'c': {"cherry"},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("GroupBy = %v; want %v", groups, want)
+	}
+
+	long, short := Partition(slices.Values(words), func(w string) bool { return len(w) > 6 })
+	if !slices.Equal(long, []string{"avocado", "blueberry"}) || !slices.Equal(short, []string{"apple", "banana", "cherry"}) {
+		t.Errorf("Partition = %v, %v", long, short)
+	}
+}
+
+func TestRemoveInsertCopy(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+
+	s = RemoveAt(s, 3)
+	if want := []int{1, 2, 3, 5}; !slices.Equal(s, want) {
+		t.Errorf("RemoveAt = %v; want %v", s, want)
+	}
+
+	s = InsertAt(s, 2, 99)
+	if want := []int{1, 2, 99, 3, 5}; !slices.Equal(s, want) {
+		t.Errorf("InsertAt = %v; want %v", s, want)
+	}
+
+	c := Copy(s)
+	c[0] = -1
+	if s[0] != 1 {
+		t.Error("Copy shares storage with the original")
+	}
+	if Copy([]int(nil)) != nil {
+		t.Error("Copy(nil) should be nil")
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	a := map[string]int{"go": 1, "rust": 2, "zig": 3}
+	b := map[string]bool{"go": true, "python": true, "zig": false}
+
+	testCases := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"Union", slices.Sorted(Union(a, b)), []string{"go", "python", "rust", "zig"}},
+		{"Intersect", slices.Sorted(Intersect(a, b)), []string{"go", "zig"}},
+		{"Difference", slices.Sorted(Difference(a, b)), []string{"rust"}},
+		{"SymmetricDifference", slices.Sorted(SymmetricDifference(a, b)), []string{"python", "rust"}},
+		{"KeySet", slices.Sorted(maps.Keys(KeySet(Intersect(a, b)))), []string{"go", "zig"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !slices.Equal(tc.got, tc.want) {
+				t.Errorf("got %v; want %v", tc.got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package collections
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func orderedKeys[K comparable, V any](m *OrderedMap[K, V]) []K {
+	return slices.Collect(m.Keys())
+}
+
+func TestOrderedMapInsertionOrder(t *testing.T) {
+	m := NewOrderedMap[string, int]()
+	for i, k := range []string{"zebra", "apple", "mango", "kiwi"} {
+		m.Set(k, i)
+	}
+
+	// Updating a key keeps its position
+	m.Set("apple", 100)
+
+	if got, want := orderedKeys(m), []string{"zebra", "apple", "mango", "kiwi"}; !slices.Equal(got, want) {
+		t.Errorf("Keys = %v; want %v", got, want)
+	}
+	if v, ok := m.Get("apple"); !ok || v != 100 {
+		t.Errorf("Get(apple) = %d, %t; want 100, true", v, ok)
+	}
+	if got, want := slices.Collect(m.Values()), []int{0, 100, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("Values = %v; want %v", got, want)
+	}
+}
+
+func TestOrderedMapReorderAndDelete(t *testing.T) {
+	m := NewOrderedMap[int, string]()
+	for i := 1; i <= 4; i++ {
+		m.Set(i, strings.Repeat("*", i))
+	}
+
+	testCases := []struct {
+		name string
+		op   func() bool
+		ok   bool
+		want []int
+	}{
+		{"MoveToEnd", func() bool { return m.MoveToEnd(1) }, true, []int{2, 3, 4, 1}},
+		{"MoveToFront", func() bool { return m.MoveToFront(4) }, true, []int{4, 2, 3, 1}},
+		{"Delete middle", func() bool { return m.Delete(2) }, true, []int{4, 3, 1}},
+		{"Delete missing", func() bool { return m.Delete(42) }, false, []int{4, 3, 1}},
+		{"Move missing", func() bool { return m.MoveToEnd(42) }, false, []int{4, 3, 1}},
+	}
+
+	for _, tc := range testCases {
+		if ok := tc.op(); ok != tc.ok {
+			t.Errorf("%s returned %t; want %t", tc.name, ok, tc.ok)
+		}
+		if got := orderedKeys(m); !slices.Equal(got, tc.want) {
+			t.Errorf("after %s: keys = %v; want %v", tc.name, got, tc.want)
+		}
+	}
+
+	if k, _, ok := m.First(); !ok || k != 4 {
+		t.Errorf("First = %d, %t; want 4, true", k, ok)
+	}
+	if k, _, ok := m.Last(); !ok || k != 1 {
+		t.Errorf("Last = %d, %t; want 1, true", k, ok)
+	}
+
+	var backward []int
+	for k := range m.Backward() {
+		backward = append(backward, k)
+	}
+	if want := []int{1, 3, 4}; !slices.Equal(backward, want) {
+		t.Errorf("Backward = %v; want %v", backward, want)
+	}
+
+	m.Clear()
+	if m.Len() != 0 || len(orderedKeys(m)) != 0 {
+		t.Error("Clear left entries behind")
+	}
+	if _, _, ok := m.First(); ok {
+		t.Error("First on empty map should report false")
+	}
+}
+
+func TestOrderedMapDeleteWhileIterating(t *testing.T) {
+	m := NewOrderedMap[int, int]()
+	for i := 0; i < 6; i++ {
+		m.Set(i, i)
+	}
+	for k, v := range m.All() {
+		if v%2 == 1 {
+			m.Delete(k)
+		}
+	}
+	if got, want := orderedKeys(m), []int{0, 2, 4}; !slices.Equal(got, want) {
+		t.Errorf("Keys = %v; want %v", got, want)
+	}
+}
+
+func TestOrderedMapDeleteAheadWhileIterating(t *testing.T) {
+	m := NewOrderedMap[int, int]()
+	for i := 0; i < 6; i++ {
+		m.Set(i, i)
+	}
+
+	var visited []int
+	for k := range m.All() {
+		visited = append(visited, k)
+		// Delete the next two entries before the loop reaches them
+		m.Delete(k + 1)
+		m.Delete(k + 2)
+	}
+	if want := []int{0, 3}; !slices.Equal(visited, want) {
+		t.Errorf("All visited %v; want %v", visited, want)
+	}
+
+	m.Set(10, 10)
+	m.Set(11, 11)
+	m.Set(12, 12)
+	visited = nil
+	for k := range m.Backward() {
+		visited = append(visited, k)
+		if k == 12 {
+			m.Delete(11)
+		}
+	}
+	if want := []int{12, 10, 3, 0}; !slices.Equal(visited, want) {
+		t.Errorf("Backward visited %v; want %v", visited, want)
+	}
+
+	visited = nil
+	for k := range m.All() {
+		visited = append(visited, k)
+		m.Clear()
+	}
+	if want := []int{0}; !slices.Equal(visited, want) {
+		t.Errorf("All after Clear visited %v; want %v", visited, want)
+	}
+}
+
+func TestSetOperationsOnSet(t *testing.T) {
+	a := NewSet(1, 2, 3, 4)
+	b := NewSet(3, 4, 5)
+
+	testCases := []struct {
+		name string
+		got  *Set[int]
+		want []int
+	}{
+		{"Union", a.Union(b), []int{1, 2, 3, 4, 5}},
+		{"Intersection", a.Intersection(b), []int{3, 4}},
+		{"Difference", a.Difference(b), []int{1, 2}},
+		{"SymmetricDifference", a.SymmetricDifference(b), []int{1, 2, 5}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := slices.Sorted(tc.got.All()); !slices.Equal(got, tc.want) {
+				t.Errorf("got %v; want %v", got, tc.want)
+			}
+		})
+	}
+
+	// Operations must not modify their inputs
+	if a.Len() != 4 || b.Len() != 3 {
+		t.Errorf("inputs modified: a=%v b=%v", slices.Sorted(a.All()), slices.Sorted(b.All()))
+	}
+}
+
+func TestSetSubsetAndEqual(t *testing.T) {
+	small := NewSet("go")
+	big := NewSet("go", "rust")
+
+	if !small.IsSubset(big) || big.IsSubset(small) {
+		t.Error("IsSubset gave the wrong answer")
+	}
+	if !big.IsSuperset(small) {
+		t.Error("big should be a superset of small")
+	}
+	if !NewSet[string]().IsSubset(small) {
+		t.Error("empty set is a subset of every set")
+	}
+
+	clone := big.Clone()
+	if !clone.Equal(big) {
+		t.Error("clone should equal the original")
+	}
+	clone.Delete("rust")
+	if clone.Equal(big) || !big.Has("rust") {
+		t.Error("clone should be independent of the original")
+	}
+}
+
+func TestMultiMap(t *testing.T) {
+	m := NewMultiMap[string, string]()
+	m.Add("fruit", "apple", "banana")
+	m.Add("veg", "carrot")
+	m.Add("fruit", "cherry")
+	m.Add("empty")
+
+	if got, want := m.Get("fruit"), []string{"apple", "banana", "cherry"}; !slices.Equal(got, want) {
+		t.Errorf("Get(fruit) = %v; want %v", got, want)
+	}
+	if m.Len() != 2 || m.Count() != 4 {
+		t.Errorf("Len, Count = %d, %d; want 2, 4", m.Len(), m.Count())
+	}
+	if m.Has("empty") {
+		t.Error("adding no values should not create a key")
+	}
+
+	// Get returns a copy
+	m.Get("fruit")[0] = "mutated"
+	if m.Get("fruit")[0] != "apple" {
+		t.Error("Get should not expose internal storage")
+	}
+
+	removed := m.DeleteFunc("fruit", func(v string) bool { return strings.HasPrefix(v, "b") })
+	if removed != 1 || m.Count() != 3 {
+		t.Errorf("DeleteFunc removed %d, Count = %d; want 1, 3", removed, m.Count())
+	}
+
+	pairs := 0
+	for range m.All() {
+		pairs++
+	}
+	if pairs != m.Count() {
+		t.Errorf("All yielded %d pairs; want %d", pairs, m.Count())
+	}
+
+	m.DeleteFunc("veg", func(string) bool { return true })
+	if m.Has("veg") {
+		t.Error("removing every value should remove the key")
+	}
+	if !m.Delete("fruit") || m.Count() != 0 || m.Len() != 0 {
+		t.Error("Delete should remove the key and its values")
+	}
+}
+
+func TestBiMap(t *testing.T) {
+	codes := NewBiMap[string, int]()
+	codes.Set("OK", 200)
+	codes.Set("NotFound", 404)
+
+	if v, _ := codes.Get("OK"); v != 200 {
+		t.Errorf("Get(OK) = %d; want 200", v)
+	}
+	if k, _ := codes.GetKey(404); k != "NotFound" {
+		t.Errorf("GetKey(404) = %q; want NotFound", k)
+	}
+
+	// Re-using a value drops its old key to stay one-to-one
+	codes.Set("Missing", 404)
+	if codes.Has("NotFound") || codes.Len() != 2 {
+		t.Errorf("old key should be gone: %v", maps.Collect(codes.All()))
+	}
+
+	// Re-using a key drops its old value
+	codes.Set("OK", 204)
+	if codes.HasValue(200) {
+		t.Error("old value 200 should be gone")
+	}
+
+	inverse := codes.Inverse()
+	if k, ok := inverse.Get(204); !ok || k != "OK" {
+		t.Errorf("Inverse().Get(204) = %q, %t; want OK, true", k, ok)
+	}
+
+	// The inverse shares storage with the original
+	inverse.Set(500, "Error")
+	if v, ok := codes.Get("Error"); !ok || v != 500 {
+		t.Errorf("Get(Error) = %d, %t; want 500, true", v, ok)
+	}
+
+	if !codes.DeleteValue(500) || codes.Has("Error") {
+		t.Error("DeleteValue should remove both directions")
+	}
+	if got, want := slices.Sorted(codes.Keys()), []string{"Missing", "OK"}; !slices.Equal(got, want) {
+		t.Errorf("Keys = %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,83 @@
+package collections
+
+import "iter"
+
+// The set operations below treat the keys of a map as a set. The value
+// types of the two maps may differ, so map[string]int can be intersected
+// with map[string]bool. Results are yielded in map iteration order, which
+// is random; sort them with slices.Sorted when order matters.
+
+// Union yields every key present in a or b
+func Union[K comparable, V1, V2 any](a map[K]V1, b map[K]V2) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range a {
+			if !yield(k) {
+				return
+			}
+		}
+		for k := range b {
+			if _, dup := a[k]; dup {
+				continue
+			}
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Intersect yields the keys present in both a and b
+func Intersect[K comparable, V1, V2 any](a map[K]V1, b map[K]V2) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		// Range over the smaller map
+		if len(b) < len(a) {
+			for k := range b {
+				if _, ok := a[k]; ok && !yield(k) {
+					return
+				}
+			}
+			return
+		}
+		for k := range a {
+			if _, ok := b[k]; ok && !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Difference yields the keys of a that are not in b
+func Difference[K comparable, V1, V2 any](a map[K]V1, b map[K]V2) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range a {
+			if _, ok := b[k]; !ok && !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// SymmetricDifference yields keys that are in exactly one of a and b
+func SymmetricDifference[K comparable, V1, V2 any](a map[K]V1, b map[K]V2) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range Difference(a, b) {
+			if !yield(k) {
+				return
+			}
+		}
+		for k := range Difference(b, a) {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// KeySet collects a sequence into a map usable with the operations above
+func KeySet[K comparable](seq iter.Seq[K]) map[K]struct{} {
+	set := make(map[K]struct{})
+	for k := range seq {
+		set[k] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,102 @@
+package collections
+
+import (
+	"iter"
+	"maps"
+	"slices"
+)
+
+// MultiMap maps each key to any number of values, kept in insertion order.
+// The zero value is not usable; create one with NewMultiMap.
+type MultiMap[K comparable, V any] struct {
+	items map[K][]V
+	count int
+}
+
+func NewMultiMap[K comparable, V any]() *MultiMap[K, V] {
+	return &MultiMap[K, V]{items: make(map[K][]V)}
+}
+
+// Add appends values to key
+func (m *MultiMap[K, V]) Add(key K, values ...V) {
+	if len(values) == 0 {
+		return
+	}
+	m.items[key] = append(m.items[key], values...)
+	m.count += len(values)
+}
+
+// Get returns a copy of the values stored under key
+func (m *MultiMap[K, V]) Get(key K) []V {
+	return slices.Clone(m.items[key])
+}
+
+// Has reports whether key has at least one value
+func (m *MultiMap[K, V]) Has(key K) bool {
+	_, ok := m.items[key]
+	return ok
+}
+
+// Delete removes key and all its values, reporting whether it was present
+func (m *MultiMap[K, V]) Delete(key K) bool {
+	values, ok := m.items[key]
+	if !ok {
+		return false
+	}
+	m.count -= len(values)
+	delete(m.items, key)
+	return true
+}
+
+// DeleteFunc removes the values of key for which del returns true and
+// returns how many were removed
+func (m *MultiMap[K, V]) DeleteFunc(key K, del func(V) bool) int {
+	values, ok := m.items[key]
+	if !ok {
+		return 0
+	}
+	kept := slices.DeleteFunc(values, del)
+	removed := len(values) - len(kept)
+	m.count -= removed
+	if len(kept) == 0 {
+		delete(m.items, key)
+	} else {
+		m.items[key] = kept
+	}
+	return removed
+}
+
+// Len returns the number of distinct keys
+func (m *MultiMap[K, V]) Len() int {
+	return len(m.items)
+}
+
+// Count returns the total number of values across all keys
+func (m *MultiMap[K, V]) Count() int {
+	return m.count
+}
+
+// Clear removes every key
+func (m *MultiMap[K, V]) Clear() {
+	clear(m.items)
+	m.count = 0
+}
+
+// All yields every key/value pair; keys are visited in unspecified order
+// and the values of one key in insertion order
+func (m *MultiMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, values := range m.items {
+			for _, v := range values {
+				if !yield(k, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Keys yields each distinct key once, in unspecified order
+func (m *MultiMap[K, V]) Keys() iter.Seq[K] {
+	return maps.Keys(m.items)
+}
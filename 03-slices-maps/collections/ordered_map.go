@@ -0,0 +1,195 @@
+package collections
+
+import "iter"
+
+// OrderedMap is a map that remembers insertion order. Updating an existing
+// key keeps its position; MoveToEnd and MoveToFront reorder explicitly.
+// The zero value is not usable; create one with NewOrderedMap.
+type OrderedMap[K comparable, V any] struct {
+	items map[K]*omEntry[K, V]
+	// root is a sentinel: root.next is the oldest entry, root.prev the newest
+	root omEntry[K, V]
+}
+
+type omEntry[K comparable, V any] struct {
+	key        K
+	value      V
+	prev, next *omEntry[K, V]
+}
+
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	m := &OrderedMap[K, V]{items: make(map[K]*omEntry[K, V])}
+	m.root.next = &m.root
+	m.root.prev = &m.root
+	return m
+}
+
+// unlink removes e from the list. e keeps its own links, so an iterator
+// holding e can still find its way back to the live entries.
+func (m *OrderedMap[K, V]) unlink(e *omEntry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+}
+
+// insertAfter places e directly after at
+func (m *OrderedMap[K, V]) insertAfter(e, at *omEntry[K, V]) {
+	e.prev = at
+	e.next = at.next
+	at.next.prev = e
+	at.next = e
+}
+
+// Set stores value under key. New keys go to the end; existing keys keep
+// their position.
+func (m *OrderedMap[K, V]) Set(key K, value V) {
+	if e, ok := m.items[key]; ok {
+		e.value = value
+		return
+	}
+	e := &omEntry[K, V]{key: key, value: value}
+	m.insertAfter(e, m.root.prev)
+	m.items[key] = e
+}
+
+// Get returns the value for key and whether it was present
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	if e, ok := m.items[key]; ok {
+		return e.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Has reports whether key is present
+func (m *OrderedMap[K, V]) Has(key K) bool {
+	_, ok := m.items[key]
+	return ok
+}
+
+// Delete removes key and reports whether it was present
+func (m *OrderedMap[K, V]) Delete(key K) bool {
+	e, ok := m.items[key]
+	if !ok {
+		return false
+	}
+	m.unlink(e)
+	delete(m.items, key)
+	return true
+}
+
+// MoveToEnd makes key the newest entry; it reports whether key was present
+func (m *OrderedMap[K, V]) MoveToEnd(key K) bool {
+	e, ok := m.items[key]
+	if !ok {
+		return false
+	}
+	m.unlink(e)
+	m.insertAfter(e, m.root.prev)
+	return true
+}
+
+// MoveToFront makes key the oldest entry; it reports whether key was present
+func (m *OrderedMap[K, V]) MoveToFront(key K) bool {
+	e, ok := m.items[key]
+	if !ok {
+		return false
+	}
+	m.unlink(e)
+	m.insertAfter(e, &m.root)
+	return true
+}
+
+// First returns the oldest entry
+func (m *OrderedMap[K, V]) First() (K, V, bool) {
+	return m.entry(m.root.next)
+}
+
+// Last returns the newest entry
+func (m *OrderedMap[K, V]) Last() (K, V, bool) {
+	return m.entry(m.root.prev)
+}
+
+func (m *OrderedMap[K, V]) entry(e *omEntry[K, V]) (K, V, bool) {
+	if e == &m.root {
+		var (
+			k K
+			v V
+		)
+		return k, v, false
+	}
+	return e.key, e.value, true
+}
+
+// Len returns the number of entries
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.items)
+}
+
+// Clear removes every entry
+func (m *OrderedMap[K, V]) Clear() {
+	clear(m.items)
+	m.root.next = &m.root
+	m.root.prev = &m.root
+}
+
+// live reports whether e is still in the map; deleted entries, and entries
+// dropped by Clear, are no longer the ones items points to
+func (m *OrderedMap[K, V]) live(e *omEntry[K, V]) bool {
+	return e == &m.root || m.items[e.key] == e
+}
+
+// All yields key/value pairs from oldest to newest. Entries may be deleted
+// during the loop: deleted entries that haven't been reached are skipped.
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := m.root.next; e != &m.root; {
+			next := e.next
+			if !yield(e.key, e.value) {
+				return
+			}
+			for !m.live(next) {
+				next = next.next
+			}
+			e = next
+		}
+	}
+}
+
+// Backward yields key/value pairs from newest to oldest, with the same
+// rules for deleting during the loop as All
+func (m *OrderedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := m.root.prev; e != &m.root; {
+			prev := e.prev
+			if !yield(e.key, e.value) {
+				return
+			}
+			for !m.live(prev) {
+				prev = prev.prev
+			}
+			e = prev
+		}
+	}
+}
+
+// Keys yields keys in insertion order
+func (m *OrderedMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range m.All() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Values yields values in insertion order
+func (m *OrderedMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range m.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package collections
+
+import (
+	"iter"
+	"maps"
+)
+
+// Set is an unordered collection of unique values.
+// The zero value is not usable; create one with NewSet.
+type Set[T comparable] struct {
+	items map[T]struct{}
+}
+
+// NewSet returns a set containing items
+func NewSet[T comparable](items ...T) *Set[T] {
+	s := &Set[T]{items: make(map[T]struct{}, len(items))}
+	s.Add(items...)
+	return s
+}
+
+// SetOf collects a sequence into a new set
+func SetOf[T comparable](seq iter.Seq[T]) *Set[T] {
+	return &Set[T]{items: KeySet(seq)}
+}
+
+// Add inserts items into the set
+func (s *Set[T]) Add(items ...T) {
+	for _, item := range items {
+		s.items[item] = struct{}{}
+	}
+}
+
+// Delete removes item and reports whether it was present
+func (s *Set[T]) Delete(item T) bool {
+	if _, ok := s.items[item]; !ok {
+		return false
+	}
+	delete(s.items, item)
+	return true
+}
+
+// Has reports whether item is in the set
+func (s *Set[T]) Has(item T) bool {
+	_, ok := s.items[item]
+	return ok
+}
+
+// Len returns the number of items
+func (s *Set[T]) Len() int {
+	return len(s.items)
+}
+
+// Clear removes every item
+func (s *Set[T]) Clear() {
+	clear(s.items)
+}
+
+// All yields the items in unspecified order
+func (s *Set[T]) All() iter.Seq[T] {
+	return maps.Keys(s.items)
+}
+
+// Clone returns an independent copy of the set
+func (s *Set[T]) Clone() *Set[T] {
+	return &Set[T]{items: maps.Clone(s.items)}
+}
+
+// Union returns a new set with the items of s and other
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	return SetOf(Union(s.items, other.items))
+}
+
+// Intersection returns a new set with the items in both s and other
+func (s *Set[T]) Intersection(other *Set[T]) *Set[T] {
+	return SetOf(Intersect(s.items, other.items))
+}
+
+// Difference returns a new set with the items of s not in other
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+	return SetOf(Difference(s.items, other.items))
+}
+
+// SymmetricDifference returns a new set with items in exactly one of the sets
+func (s *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	return SetOf(SymmetricDifference(s.items, other.items))
+}
+
+// IsSubset reports whether every item of s is in other
+func (s *Set[T]) IsSubset(other *Set[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+	for item := range s.items {
+		if !other.Has(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset reports whether s contains every item of other
+func (s *Set[T]) IsSuperset(other *Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+// Equal reports whether both sets hold the same items
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	return s.Len() == other.Len() && s.IsSubset(other)
+}
@@ -2,7 +2,10 @@ package main
 
 import (
 	"fmt"
+	"slices"
 	"sort"
+
+	"grok-study-plan/03-slices-maps/collections"
 )
 
 func main() {
@@ -46,24 +49,32 @@ func main() {
 	newSlice := make([]int, 3, 10) // len=3, cap=10
 	fmt.Println("Make slice:", newSlice, "len:", len(newSlice), "cap:", cap(newSlice))
 
-	// Copy slice
+	// Copy slice (collections.Copy wraps make + copy)
 	source := []int{1, 2, 3, 4, 5}
-	destination := make([]int, len(source))
-	copy(destination, source)
+	destination := collections.Copy(source)
 	fmt.Println("Copied slice:", destination)
 
 	// Slice operations
 	numbersSlice := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
 
 	// Remove element at index 3
-	index := 3
-	numbersSlice = append(numbersSlice[:index], numbersSlice[index+1:]...)
+	numbersSlice = collections.RemoveAt(numbersSlice, 3)
 	fmt.Println("After removing index 3:", numbersSlice)
 
 	// Insert element at index 2
-	numbersSlice = append(numbersSlice[:2], append([]int{99}, numbersSlice[2:]...)...)
+	numbersSlice = collections.InsertAt(numbersSlice, 2, 99)
 	fmt.Println("After inserting 99 at index 2:", numbersSlice)
 
+	// Lazy filter/map chain: nothing runs until slices.Collect
+	evens := collections.Filter(slices.Values(numbersSlice), func(n int) bool { return n%2 == 0 })
+	doubled := slices.Collect(collections.Map(evens, func(n int) int { return n * 2 }))
+	fmt.Println("Even numbers doubled:", doubled)
+
+	total := collections.Reduce(slices.Values(numbersSlice), 0, func(acc, n int) int { return acc + n })
+	fmt.Println("Sum:", total)
+
+	fmt.Println("Chunks of 4:", slices.Collect(collections.Chunk(slices.Values(numbersSlice), 4)))
+
 	// Maps - key-value pairs
 	var person map[string]string
 	fmt.Println("Nil map:", person)
@@ -103,6 +114,17 @@ func main() {
 		fmt.Printf("  %s: %s\n", key, value)
 	}
 
+	// Map iteration order is random; OrderedMap keeps insertion order
+	profile := collections.NewOrderedMap[string, string]()
+	profile.Set("name", "Alice")
+	profile.Set("age", "25")
+	profile.Set("city", "New York")
+	profile.MoveToEnd("name")
+	fmt.Println("Iterating over ordered map (name moved to end):")
+	for key, value := range profile.All() {
+		fmt.Printf("  %s: %s\n", key, value)
+	}
+
 	// Map of maps
 	users := map[string]map[string]string{
 		"user1": {"name": "Alice", "role": "admin"},
@@ -110,6 +132,17 @@ func main() {
 	}
 	fmt.Println("Users:", users)
 
+	// Set operations over map keys
+	skillsA := map[string]int{"go": 5, "sql": 3, "rust": 2}
+	skillsB := map[string]int{"go": 4, "python": 5, "sql": 1}
+	fmt.Println("Shared skills:", slices.Sorted(collections.Intersect(skillsA, skillsB)))
+	fmt.Println("Only in A:", slices.Sorted(collections.Difference(skillsA, skillsB)))
+
+	// Set type built on the same operations
+	backend := collections.NewSet("go", "sql")
+	fmt.Println("Backend skills are a subset of all skills:",
+		backend.IsSubset(collections.SetOf(collections.Union(skillsA, skillsB))))
+
 	// Sorting slices
 	unsorted := []int{3, 1, 4, 1, 5, 9, 2, 6}
 	fmt.Println("Unsorted:", unsorted)
@@ -0,0 +1,285 @@
+// Package geometry provides 2D points, shapes, bounding boxes, and the
+// containment, intersection, and transformation helpers the exercises need.
+package geometry
+
+import (
+	"fmt"
+	"math"
+)
+
+// Epsilon is the tolerance used when comparing floating point coordinates
+const Epsilon = 1e-9
+
+// Point is a location (or vector) in the plane
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Pt is shorthand for Point{X: x, Y: y}
+func Pt(x, y float64) Point {
+	return Point{X: x, Y: y}
+}
+
+func (p Point) String() string {
+	return fmt.Sprintf("(%.2f, %.2f)", p.X, p.Y)
+}
+
+// Add returns p + q
+func (p Point) Add(q Point) Point {
+	return Point{p.X + q.X, p.Y + q.Y}
+}
+
+// Sub returns p - q
+func (p Point) Sub(q Point) Point {
+	return Point{p.X - q.X, p.Y - q.Y}
+}
+
+// Mul returns p scaled by factor
+func (p Point) Mul(factor float64) Point {
+	return Point{p.X * factor, p.Y * factor}
+}
+
+// Dot returns the dot product of p and q
+func (p Point) Dot(q Point) float64 {
+	return p.X*q.X + p.Y*q.Y
+}
+
+// Cross returns the z component of the cross product of p and q
+func (p Point) Cross(q Point) float64 {
+	return p.X*q.Y - p.Y*q.X
+}
+
+// Dist returns the Euclidean distance between p and q
+func (p Point) Dist(q Point) float64 {
+	return math.Hypot(p.X-q.X, p.Y-q.Y)
+}
+
+// Eq reports whether p and q are equal within Epsilon
+func (p Point) Eq(q Point) bool {
+	return math.Abs(p.X-q.X) <= Epsilon && math.Abs(p.Y-q.Y) <= Epsilon
+}
+
+// scaleAbout moves p away from (or towards) origin by factor
+func (p Point) scaleAbout(origin Point, factor float64) Point {
+	return origin.Add(p.Sub(origin).Mul(factor))
+}
+
+// BBox is an axis-aligned bounding box
+type BBox struct {
+	Min, Max Point
+}
+
+// BoundsOf returns the smallest box containing every point
+func BoundsOf(points ...Point) BBox {
+	if len(points) == 0 {
+		return BBox{}
+	}
+	b := BBox{Min: points[0], Max: points[0]}
+	for _, p := range points[1:] {
+		b.Min.X = math.Min(b.Min.X, p.X)
+		b.Min.Y = math.Min(b.Min.Y, p.Y)
+		b.Max.X = math.Max(b.Max.X, p.X)
+		b.Max.Y = math.Max(b.Max.Y, p.Y)
+	}
+	return b
+}
+
+func (b BBox) Width() float64  { return b.Max.X - b.Min.X }
+func (b BBox) Height() float64 { return b.Max.Y - b.Min.Y }
+
+// Center returns the midpoint of the box
+func (b BBox) Center() Point {
+	return Point{(b.Min.X + b.Max.X) / 2, (b.Min.Y + b.Max.Y) / 2}
+}
+
+// Contains reports whether p lies inside or on the edge of the box
+func (b BBox) Contains(p Point) bool {
+	return p.X >= b.Min.X-Epsilon && p.X <= b.Max.X+Epsilon &&
+		p.Y >= b.Min.Y-Epsilon && p.Y <= b.Max.Y+Epsilon
+}
+
+// Intersects reports whether two boxes overlap (touching counts)
+func (b BBox) Intersects(o BBox) bool {
+	return b.Min.X <= o.Max.X+Epsilon && o.Min.X <= b.Max.X+Epsilon &&
+		b.Min.Y <= o.Max.Y+Epsilon && o.Min.Y <= b.Max.Y+Epsilon
+}
+
+// Union returns the smallest box containing both boxes
+func (b BBox) Union(o BBox) BBox {
+	return BoundsOf(b.Min, b.Max, o.Min, o.Max)
+}
+
+// Shape is anything with an area and a perimeter
+type Shape interface {
+	Area() float64
+	Perimeter() float64
+}
+
+// Figure is a shape with a position in the plane
+type Figure interface {
+	Shape
+	Bounds() BBox
+	Centroid() Point
+	Contains(p Point) bool
+}
+
+// Transformer is implemented by pointers to shapes that can move in place.
+// Scale works about the shape's centroid, so the centroid stays put. A
+// negative factor also turns the shape half a turn about it; for circles and
+// rectangles that is the same as scaling by the absolute value.
+type Transformer interface {
+	Translate(dx, dy float64)
+	Scale(factor float64)
+}
+
+// Polygonal is implemented by shapes made of straight edges
+type Polygonal interface {
+	Vertices() []Point
+}
+
+// segmentDist returns the distance from p to the segment a-b
+func segmentDist(p, a, b Point) float64 {
+	ab := b.Sub(a)
+	lenSq := ab.Dot(ab)
+	if lenSq == 0 {
+		return p.Dist(a)
+	}
+	t := math.Max(0, math.Min(1, p.Sub(a).Dot(ab)/lenSq))
+	return p.Dist(a.Add(ab.Mul(t)))
+}
+
+// orientation returns >0 for counter-clockwise, <0 for clockwise, 0 if collinear
+func orientation(a, b, c Point) float64 {
+	v := b.Sub(a).Cross(c.Sub(a))
+	if math.Abs(v) <= Epsilon {
+		return 0
+	}
+	return v
+}
+
+func onSegment(p, a, b Point) bool {
+	return orientation(a, b, p) == 0 && BoundsOf(a, b).Contains(p)
+}
+
+// SegmentsIntersect reports whether segment p1-p2 touches segment q1-q2
+func SegmentsIntersect(p1, p2, q1, q2 Point) bool {
+	d1 := orientation(q1, q2, p1)
+	d2 := orientation(q1, q2, p2)
+	d3 := orientation(p1, p2, q1)
+	d4 := orientation(p1, p2, q2)
+
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) &&
+		((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+
+	// Collinear and touching cases
+	return onSegment(p1, q1, q2) || onSegment(p2, q1, q2) ||
+		onSegment(q1, p1, p2) || onSegment(q2, p1, p2)
+}
+
+// edges calls fn for every edge of a closed polyline
+func edges(vertices []Point, fn func(a, b Point) bool) {
+	for i := range vertices {
+		if !fn(vertices[i], vertices[(i+1)%len(vertices)]) {
+			return
+		}
+	}
+}
+
+func polygonsIntersect(a, b []Point) bool {
+	hit := false
+	edges(a, func(a1, a2 Point) bool {
+		edges(b, func(b1, b2 Point) bool {
+			hit = SegmentsIntersect(a1, a2, b1, b2)
+			return !hit
+		})
+		return !hit
+	})
+	if hit {
+		return true
+	}
+	// No edges cross, so either one polygon is inside the other or they are apart
+	return len(a) > 0 && len(b) > 0 &&
+		(polygonContains(a, b[0]) || polygonContains(b, a[0]))
+}
+
+func circlePolygonIntersect(c Circle, vertices []Point) bool {
+	if polygonContains(vertices, c.Center) {
+		return true
+	}
+	hit := false
+	edges(vertices, func(a, b Point) bool {
+		hit = segmentDist(c.Center, a, b) <= c.Radius+Epsilon
+		return !hit
+	})
+	return hit
+}
+
+// Intersects reports whether two figures overlap or touch.
+// Circles and polygonal figures are tested exactly; any other figure
+// falls back to comparing bounding boxes.
+func Intersects(a, b Figure) bool {
+	if !a.Bounds().Intersects(b.Bounds()) {
+		return false
+	}
+
+	ca, aIsCircle := asCircle(a)
+	cb, bIsCircle := asCircle(b)
+	pa, aIsPoly := a.(Polygonal)
+	pb, bIsPoly := b.(Polygonal)
+
+	switch {
+	case aIsCircle && bIsCircle:
+		return ca.Center.Dist(cb.Center) <= ca.Radius+cb.Radius+Epsilon
+	case aIsCircle && bIsPoly:
+		return circlePolygonIntersect(ca, pb.Vertices())
+	case aIsPoly && bIsCircle:
+		return circlePolygonIntersect(cb, pa.Vertices())
+	case aIsPoly && bIsPoly:
+		return polygonsIntersect(pa.Vertices(), pb.Vertices())
+	}
+	return true // bounding boxes overlap and we cannot be more precise
+}
+
+func asCircle(f Figure) (Circle, bool) {
+	switch c := f.(type) {
+	case Circle:
+		return c, true
+	case *Circle:
+		return *c, true
+	}
+	return Circle{}, false
+}
+
+// ContainsAll reports whether every point lies inside the figure
+func ContainsAll(f Figure, points ...Point) bool {
+	for _, p := range points {
+		if !f.Contains(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// TotalArea sums the area of several shapes
+func TotalArea(shapes ...Shape) float64 {
+	total := 0.0
+	for _, s := range shapes {
+		total += s.Area()
+	}
+	return total
+}
+
+// BoundsOfAll returns the box enclosing all figures
+func BoundsOfAll(figures ...Figure) BBox {
+	if len(figures) == 0 {
+		return BBox{}
+	}
+	b := figures[0].Bounds()
+	for _, f := range figures[1:] {
+		b = b.Union(f.Bounds())
+	}
+	return b
+}
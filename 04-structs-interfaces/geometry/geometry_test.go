@@ -0,0 +1,225 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSegmentsIntersect(t *testing.T) {
+	testCases := []struct {
+		name           string
+		p1, p2, q1, q2 Point
+		want           bool
+	}{
+		{"crossing", Pt(0, 0), Pt(4, 4), Pt(0, 4), Pt(4, 0), true},
+		{"apart", Pt(0, 0), Pt(1, 1), Pt(3, 0), Pt(4, 1), false},
+		{"parallel", Pt(0, 0), Pt(4, 0), Pt(0, 1), Pt(4, 1), false},
+		{"parallel diagonal", Pt(0, 0), Pt(2, 2), Pt(1, 0), Pt(3, 2), false},
+		{"collinear overlapping", Pt(0, 0), Pt(3, 0), Pt(2, 0), Pt(5, 0), true},
+		{"collinear contained", Pt(0, 0), Pt(6, 6), Pt(2, 2), Pt(3, 3), true},
+		{"collinear disjoint", Pt(0, 0), Pt(1, 0), Pt(2, 0), Pt(3, 0), false},
+		{"touching endpoints", Pt(0, 0), Pt(2, 2), Pt(2, 2), Pt(4, 0), true},
+		{"collinear touching endpoints", Pt(0, 0), Pt(2, 0), Pt(2, 0), Pt(5, 0), true},
+		{"endpoint on interior", Pt(0, 0), Pt(4, 0), Pt(2, 0), Pt(2, 3), true},
+		{"near miss", Pt(0, 0), Pt(4, 0), Pt(2, 0.001), Pt(2, 3), false},
+		{"degenerate point on segment", Pt(1, 1), Pt(1, 1), Pt(0, 0), Pt(2, 2), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := SegmentsIntersect(tc.p1, tc.p2, tc.q1, tc.q2); got != tc.want {
+				t.Errorf("SegmentsIntersect(%v, %v, %v, %v) = %t; want %t", tc.p1, tc.p2, tc.q1, tc.q2, got, tc.want)
+			}
+			// The answer must not depend on argument order
+			if got := SegmentsIntersect(tc.q2, tc.q1, tc.p2, tc.p1); got != tc.want {
+				t.Errorf("SegmentsIntersect reversed = %t; want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPolygonContains(t *testing.T) {
+	square := []Point{Pt(0, 0), Pt(4, 0), Pt(4, 4), Pt(0, 4)}
+	// A U shape: the notch between x=1 and x=3 above y=1 is outside
+	u := []Point{Pt(0, 0), Pt(4, 0), Pt(4, 4), Pt(3, 4), Pt(3, 1), Pt(1, 1), Pt(1, 4), Pt(0, 4)}
+
+	testCases := []struct {
+		name     string
+		vertices []Point
+		p        Point
+		want     bool
+	}{
+		{"inside", square, Pt(2, 2), true},
+		{"outside", square, Pt(5, 2), false},
+		{"on edge", square, Pt(4, 2), true},
+		{"on bottom edge", square, Pt(2, 0), true},
+		{"vertex", square, Pt(4, 4), true},
+		{"level with a vertex outside", square, Pt(-1, 4), false},
+		{"just outside edge", square, Pt(4.001, 2), false},
+		{"concave arm", u, Pt(0.5, 3), true},
+		{"concave other arm", u, Pt(3.5, 3), true},
+		{"concave base", u, Pt(2, 0.5), true},
+		{"concave notch", u, Pt(2, 3), false},
+		{"concave notch edge", u, Pt(2, 1), true},
+		{"concave reflex vertex", u, Pt(3, 1), true},
+		{"ray through reflex vertices", u, Pt(-1, 1), false},
+		{"too few vertices", []Point{Pt(0, 0), Pt(1, 1)}, Pt(0.5, 0.5), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := polygonContains(tc.vertices, tc.p); got != tc.want {
+				t.Errorf("polygonContains(%v) = %t; want %t", tc.p, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPolygonCentroid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		polygon Polygon
+		want    Point
+	}{
+		{"square", Polygon{Points: []Point{Pt(0, 0), Pt(2, 0), Pt(2, 2), Pt(0, 2)}}, Pt(1, 1)},
+		{"clockwise square", Polygon{Points: []Point{Pt(0, 0), Pt(0, 2), Pt(2, 2), Pt(2, 0)}}, Pt(1, 1)},
+		{"triangle", Polygon{Points: []Point{Pt(0, 0), Pt(3, 0), Pt(0, 3)}}, Pt(1, 1)},
+		// Two unit squares side by side plus one on top of the left one
+		{"L shape", Polygon{Points: []Point{Pt(0, 0), Pt(2, 0), Pt(2, 1), Pt(1, 1), Pt(1, 2), Pt(0, 2)}}, Pt(5.0/6, 5.0/6)},
+		{"collinear vertices", Polygon{Points: []Point{Pt(0, 0), Pt(1, 1), Pt(2, 2), Pt(5, 5)}}, Pt(2, 2)},
+		{"repeated vertex", Polygon{Points: []Point{Pt(3, 4), Pt(3, 4), Pt(3, 4)}}, Pt(3, 4)},
+		{"empty", Polygon{}, Pt(0, 0)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.polygon.Centroid(); !got.Eq(tc.want) {
+				t.Errorf("Centroid() = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIntersects(t *testing.T) {
+	square := NewPolygon(Pt(0, 0), Pt(4, 0), Pt(4, 4), Pt(0, 4))
+
+	testCases := []struct {
+		name string
+		a, b Figure
+		want bool
+	}{
+		{"circle circle overlap", Circle{Center: Pt(0, 0), Radius: 2}, Circle{Center: Pt(3, 0), Radius: 2}, true},
+		{"circle circle touching", Circle{Center: Pt(0, 0), Radius: 1}, Circle{Center: Pt(2, 0), Radius: 1}, true},
+		{"circle circle apart", Circle{Center: Pt(0, 0), Radius: 1}, Circle{Center: Pt(3, 0), Radius: 1}, false},
+		{"circle inside circle", Circle{Center: Pt(0, 0), Radius: 5}, Circle{Center: Pt(1, 1), Radius: 1}, true},
+		// The boxes overlap at the corner but the circle stays clear of it
+		{"circle near rectangle corner", Circle{Center: Pt(-0.8, -0.8), Radius: 1}, Rectangle{Width: 2, Height: 2}, false},
+		{"circle crossing rectangle edge", Circle{Center: Pt(-0.5, 1), Radius: 1}, Rectangle{Width: 2, Height: 2}, true},
+		{"circle inside polygon", Circle{Center: Pt(2, 2), Radius: 1}, square, true},
+		{"rectangle circle", Rectangle{Width: 2, Height: 2}, Circle{Center: Pt(3, 1), Radius: 1}, true},
+		{"rectangle rectangle overlap", Rectangle{Width: 2, Height: 2}, Rectangle{Min: Pt(1, 1), Width: 2, Height: 2}, true},
+		{"rectangle rectangle apart", Rectangle{Width: 2, Height: 2}, Rectangle{Min: Pt(3, 0), Width: 2, Height: 2}, false},
+		{"rectangle inside polygon", Rectangle{Min: Pt(1, 1), Width: 1, Height: 1}, square, true},
+		{"triangle triangle crossing", Triangle{Pt(0, 0), Pt(4, 0), Pt(2, 4)}, Triangle{Pt(0, 3), Pt(4, 3), Pt(2, -1)}, true},
+		// The boxes overlap but the hypotenuses face away from each other
+		{"triangle triangle apart", Triangle{Pt(0, 0), Pt(2, 0), Pt(0, 2)}, Triangle{Pt(2, 2), Pt(1.2, 2), Pt(2, 1.2)}, false},
+		{"triangle rectangle", Triangle{Pt(-1, -1), Pt(1, -1), Pt(0, 1)}, Rectangle{Width: 2, Height: 2}, true},
+		{"polygon triangle sharing vertex", square, Triangle{Pt(4, 4), Pt(6, 4), Pt(5, 6)}, true},
+		{"polygon polygon apart", square, NewPolygon(Pt(5, 5), Pt(6, 5), Pt(6, 6)), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Intersects(tc.a, tc.b); got != tc.want {
+				t.Errorf("Intersects(%T, %T) = %t; want %t", tc.a, tc.b, got, tc.want)
+			}
+			if got := Intersects(tc.b, tc.a); got != tc.want {
+				t.Errorf("Intersects(%T, %T) = %t; want %t", tc.b, tc.a, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestScale(t *testing.T) {
+	t.Run("circle", func(t *testing.T) {
+		c := Circle{Center: Pt(1, 2), Radius: 3}
+		c.Scale(-2)
+		if c.Radius != 6 || c.Center != Pt(1, 2) {
+			t.Errorf("Scale(-2) = %+v; want radius 6 about (1, 2)", c)
+		}
+	})
+
+	t.Run("rectangle", func(t *testing.T) {
+		testCases := []struct {
+			factor float64
+			want   Rectangle
+		}{
+			{2, Rectangle{Min: Pt(-1, -1), Width: 4, Height: 4}},
+			{-2, Rectangle{Min: Pt(-1, -1), Width: 4, Height: 4}},
+			{0.5, Rectangle{Min: Pt(0.5, 0.5), Width: 1, Height: 1}},
+			{0, Rectangle{Min: Pt(1, 1), Width: 0, Height: 0}},
+		}
+		for _, tc := range testCases {
+			r := Rectangle{Width: 2, Height: 2}
+			r.Scale(tc.factor)
+			if !r.Min.Eq(tc.want.Min) || r.Width != tc.want.Width || r.Height != tc.want.Height {
+				t.Errorf("Scale(%v) = %+v; want %+v", tc.factor, r, tc.want)
+			}
+		}
+	})
+
+	t.Run("triangle negative", func(t *testing.T) {
+		tri := Triangle{Pt(0, 0), Pt(3, 0), Pt(0, 3)}
+		area := tri.Area()
+		tri.Scale(-1)
+		// A half turn about the centroid (1, 1)
+		want := Triangle{Pt(2, 2), Pt(-1, 2), Pt(2, -1)}
+		if !tri.A.Eq(want.A) || !tri.B.Eq(want.B) || !tri.C.Eq(want.C) {
+			t.Errorf("Scale(-1) = %+v; want %+v", tri, want)
+		}
+		if math.Abs(tri.Area()-area) > Epsilon {
+			t.Errorf("Area after Scale(-1) = %v; want %v", tri.Area(), area)
+		}
+	})
+
+	t.Run("polygon negative", func(t *testing.T) {
+		p := NewPolygon(Pt(0, 0), Pt(2, 0), Pt(2, 1), Pt(1, 1), Pt(1, 2), Pt(0, 2))
+		centroid, area := p.Centroid(), p.Area()
+		p.Scale(-2)
+		if got := p.Centroid(); !got.Eq(centroid) {
+			t.Errorf("Centroid after Scale(-2) = %v; want %v", got, centroid)
+		}
+		if math.Abs(p.Area()-4*area) > Epsilon {
+			t.Errorf("Area after Scale(-2) = %v; want %v", p.Area(), 4*area)
+		}
+	})
+}
+
+func TestTranslateAndBounds(t *testing.T) {
+	figures := []interface {
+		Figure
+		Transformer
+	}{
+		&Circle{Center: Pt(0, 0), Radius: 1},
+		&Rectangle{Width: 2, Height: 1},
+		&Triangle{Pt(0, 0), Pt(2, 0), Pt(0, 2)},
+		NewPolygon(Pt(0, 0), Pt(1, 0), Pt(1, 1)),
+	}
+	for _, f := range figures {
+		before, centroid := f.Bounds(), f.Centroid()
+		f.Translate(3, -2)
+		after := f.Bounds()
+		d := Pt(3, -2)
+		if !after.Min.Eq(before.Min.Add(d)) || !after.Max.Eq(before.Max.Add(d)) {
+			t.Errorf("%T bounds after Translate = %v; want %v", f, after, BBox{before.Min.Add(d), before.Max.Add(d)})
+		}
+		if got := f.Centroid(); !got.Eq(centroid.Add(d)) {
+			t.Errorf("%T centroid after Translate = %v; want %v", f, got, centroid.Add(d))
+		}
+	}
+
+	all := BoundsOfAll(Circle{Center: Pt(5, 5), Radius: 1}, Rectangle{Min: Pt(-1, 0), Width: 1, Height: 1})
+	if want := (BBox{Pt(-1, 0), Pt(6, 6)}); all != want {
+		t.Errorf("BoundsOfAll = %v; want %v", all, want)
+	}
+}
@@ -0,0 +1,259 @@
+package geometry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownShape is wrapped by decode errors for unregistered type names
+var ErrUnknownShape = errors.New("unknown shape type")
+
+// UnknownShapeError reports a discriminator that no shape registered
+type UnknownShapeError struct {
+	Type  string
+	Known []string
+}
+
+func (e *UnknownShapeError) Error() string {
+	return fmt.Sprintf("geometry: unknown shape type %q (known: %s)", e.Type, strings.Join(e.Known, ", "))
+}
+
+func (e *UnknownShapeError) Unwrap() error {
+	return ErrUnknownShape
+}
+
+// Registry maps discriminator names such as "circle" to shape types so that
+// shapes can be encoded and decoded polymorphically as JSON:
+//
+//	{"type":"circle","radius":2}
+type Registry struct {
+	mu      sync.RWMutex
+	decoder map[string]func([]byte) (Shape, error)
+	names   map[reflect.Type]string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		decoder: make(map[string]func([]byte) (Shape, error)),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// DefaultRegistry holds the built-in shapes and anything added with Register
+var DefaultRegistry = NewRegistry()
+
+func init() {
+	Register[Circle]("circle")
+	Register[Rectangle]("rectangle")
+	Register[Triangle]("triangle")
+	Register[*Polygon]("polygon")
+}
+
+// Register adds T to the default registry under name
+func Register[T Shape](name string) {
+	RegisterIn[T](DefaultRegistry, name)
+}
+
+// RegisterIn adds T to r under name. Registering the same name or type
+// twice panics, since that is always a programming error.
+func RegisterIn[T Shape](r *Registry, name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t := reflect.TypeFor[T]()
+	if _, dup := r.decoder[name]; dup {
+		panic(fmt.Sprintf("geometry: shape name %q registered twice", name))
+	}
+	if existing, dup := r.names[t]; dup {
+		panic(fmt.Sprintf("geometry: %v already registered as %q", t, existing))
+	}
+
+	r.names[t] = name
+	r.decoder[name] = func(data []byte) (Shape, error) {
+		var v T
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+// Names returns the registered discriminators in sorted order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.decoder))
+	for name := range r.decoder {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// NameOf returns the discriminator for s. A value and a pointer to it share
+// one name, so Polygon and *Polygon both encode as "polygon".
+func (r *Registry) NameOf(s Shape) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t := reflect.TypeOf(s)
+	if name, ok := r.names[t]; ok {
+		return name, true
+	}
+	if t.Kind() == reflect.Pointer {
+		name, ok := r.names[t.Elem()]
+		return name, ok
+	}
+	name, ok := r.names[reflect.PointerTo(t)]
+	return name, ok
+}
+
+// Marshal encodes s as a JSON object with a leading "type" field. A nil
+// shape encodes as null, which Unmarshal decodes back to nil. A shape whose
+// own JSON already has a "type" field is an error, since the discriminator
+// would be duplicated.
+func (r *Registry) Marshal(s Shape) ([]byte, error) {
+	if s == nil {
+		return []byte("null"), nil
+	}
+	name, ok := r.NameOf(s)
+	if !ok {
+		return nil, fmt.Errorf("geometry: cannot encode unregistered shape %T", s)
+	}
+
+	body, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) < 2 || body[0] != '{' {
+		return nil, fmt.Errorf("geometry: shape %T must encode as a JSON object", s)
+	}
+	if hasTypeField(body) {
+		return nil, fmt.Errorf(`geometry: shape %T has its own "type" field`, s)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"type":`)
+	quoted, _ := json.Marshal(name)
+	buf.Write(quoted)
+	if len(body) > 2 {
+		buf.WriteByte(',')
+	}
+	buf.Write(body[1:])
+	return buf.Bytes(), nil
+}
+
+// hasTypeField reports whether a JSON object has a key that decodes into
+// "type". encoding/json matches keys case-insensitively, so "Type" counts.
+func hasTypeField(object []byte) bool {
+	var header struct {
+		Type *json.RawMessage `json:"type"`
+	}
+	return json.Unmarshal(object, &header) == nil && header.Type != nil
+}
+
+// Unmarshal decodes one object produced by Marshal; null decodes to a nil
+// Shape
+func (r *Registry) Unmarshal(data []byte) (Shape, error) {
+	if string(bytes.TrimSpace(data)) == "null" {
+		return nil, nil
+	}
+	var header struct {
+		Type *string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("geometry: invalid shape JSON: %w", err)
+	}
+	if header.Type == nil {
+		return nil, errors.New(`geometry: shape JSON is missing the "type" field`)
+	}
+
+	r.mu.RLock()
+	decode, ok := r.decoder[*header.Type]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, &UnknownShapeError{Type: *header.Type, Known: r.Names()}
+	}
+
+	s, err := decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("geometry: decoding %s: %w", *header.Type, err)
+	}
+	return s, nil
+}
+
+// MarshalShape encodes s using the default registry
+func MarshalShape(s Shape) ([]byte, error) {
+	return DefaultRegistry.Marshal(s)
+}
+
+// UnmarshalShape decodes a shape using the default registry
+func UnmarshalShape(data []byte) (Shape, error) {
+	return DefaultRegistry.Unmarshal(data)
+}
+
+// Shapes is a slice of shapes that round-trips through JSON using the
+// default registry
+type Shapes []Shape
+
+func (s Shapes) MarshalJSON() ([]byte, error) {
+	items := make([]json.RawMessage, len(s))
+	for i, shape := range s {
+		data, err := MarshalShape(shape)
+		if err != nil {
+			return nil, fmt.Errorf("shape %d: %w", i, err)
+		}
+		items[i] = data
+	}
+	return json.Marshal(items)
+}
+
+func (s *Shapes) UnmarshalJSON(data []byte) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("geometry: shapes must be a JSON array: %w", err)
+	}
+
+	shapes := make(Shapes, len(items))
+	for i, item := range items {
+		shape, err := UnmarshalShape(item)
+		if err != nil {
+			return fmt.Errorf("shape %d: %w", i, err)
+		}
+		shapes[i] = shape
+	}
+	*s = shapes
+	return nil
+}
+
+// Scene is a named collection of shapes, the format of scene files
+type Scene struct {
+	Name   string `json:"name"`
+	Shapes Shapes `json:"shapes"`
+}
+
+// ReadScene decodes a scene, rejecting unknown top-level fields
+func ReadScene(r io.Reader) (*Scene, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var scene Scene
+	if err := dec.Decode(&scene); err != nil {
+		return nil, fmt.Errorf("reading scene: %w", err)
+	}
+	return &scene, nil
+}
+
+// WriteScene encodes a scene as indented JSON
+func WriteScene(w io.Writer, scene *Scene) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(scene)
+}
@@ -0,0 +1,171 @@
+package geometry
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarshalShape(t *testing.T) {
+	testCases := []struct {
+		shape Shape
+		want  string
+	}{
+		{Circle{Radius: 2}, `{"type":"circle","radius":2}`},
+		{Rectangle{Width: 3, Height: 4}, `{"type":"rectangle","width":3,"height":4}`},
+		{NewPolygon(Pt(0, 0), Pt(1, 0), Pt(0, 1)), `{"type":"polygon","points":[{"x":0,"y":0},{"x":1,"y":0},{"x":0,"y":1}]}`},
+		// Polygon values share the *Polygon registration
+		{Polygon{Points: []Point{}}, `{"type":"polygon","points":[]}`},
+	}
+
+	for _, tc := range testCases {
+		got, err := MarshalShape(tc.shape)
+		if err != nil {
+			t.Errorf("MarshalShape(%T) error: %v", tc.shape, err)
+			continue
+		}
+		if string(got) != tc.want {
+			t.Errorf("MarshalShape(%T) = %s; want %s", tc.shape, got, tc.want)
+		}
+	}
+}
+
+func TestShapesRoundTrip(t *testing.T) {
+	original := Shapes{
+		Circle{Center: Pt(1, 2), Radius: 3},
+		Rectangle{Min: Pt(-1, -1), Width: 2, Height: 5},
+		Triangle{A: Pt(0, 0), B: Pt(4, 0), C: Pt(0, 3)},
+		NewPolygon(Pt(0, 0), Pt(2, 0), Pt(2, 2), Pt(0, 2)),
+		nil,
+	}
+
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var decoded Shapes
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, original) {
+		t.Errorf("round trip mismatch:\n got %#v\nwant %#v", decoded, original)
+	}
+}
+
+func TestUnmarshalShapeErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"unknown type", `[{"type":"circle","radius":1},{"type":"hexagon"}]`, `shape 1: geometry: unknown shape type "hexagon"`},
+		{"missing type", `[{"radius":1}]`, `missing the "type" field`},
+		{"bad field", `[{"type":"circle","radius":"big"}]`, "decoding circle"},
+		{"not an array", `{"type":"circle"}`, "must be a JSON array"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var shapes Shapes
+			err := json.Unmarshal([]byte(tc.input), &shapes)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("error = %v; want it to contain %q", err, tc.want)
+			}
+		})
+	}
+
+	_, err := UnmarshalShape([]byte(`{"type":"hexagon"}`))
+	var unknown *UnknownShapeError
+	if !errors.As(err, &unknown) || unknown.Type != "hexagon" || !errors.Is(err, ErrUnknownShape) {
+		t.Errorf("UnmarshalShape error = %v; want *UnknownShapeError wrapping ErrUnknownShape", err)
+	}
+}
+
+// Tagged carries its own "type" field, which would clash with the
+// discriminator
+type Tagged struct {
+	Kind string `json:"Type"`
+}
+
+func (Tagged) Area() float64      { return 0 }
+func (Tagged) Perimeter() float64 { return 0 }
+
+func TestMarshalRejectsTypeField(t *testing.T) {
+	r := NewRegistry()
+	RegisterIn[Tagged](r, "tagged")
+	if data, err := r.Marshal(Tagged{Kind: "x"}); err == nil {
+		t.Errorf("Marshal = %s; want an error for the duplicate type field", data)
+	}
+}
+
+func TestNilShape(t *testing.T) {
+	data, err := MarshalShape(nil)
+	if err != nil || string(data) != "null" {
+		t.Fatalf("MarshalShape(nil) = %s, %v; want null", data, err)
+	}
+	shape, err := UnmarshalShape(data)
+	if err != nil || shape != nil {
+		t.Errorf("UnmarshalShape(null) = %v, %v; want nil, nil", shape, err)
+	}
+}
+
+// Square is registered only in a private registry by the test below
+type Square struct {
+	Side float64 `json:"side"`
+}
+
+func (s Square) Area() float64      { return s.Side * s.Side }
+func (s Square) Perimeter() float64 { return 4 * s.Side }
+
+func TestCustomRegistry(t *testing.T) {
+	r := NewRegistry()
+	RegisterIn[Square](r, "square")
+
+	data, err := r.Marshal(Square{Side: 2})
+	if err != nil || string(data) != `{"type":"square","side":2}` {
+		t.Fatalf("Marshal = %s, %v", data, err)
+	}
+	shape, err := r.Unmarshal(data)
+	if err != nil || shape != (Square{Side: 2}) {
+		t.Errorf("Unmarshal = %#v, %v", shape, err)
+	}
+
+	if _, err := MarshalShape(Square{Side: 1}); err == nil {
+		t.Error("the default registry should not know about Square")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a name twice should panic")
+		}
+	}()
+	RegisterIn[Circle](r, "square")
+}
+
+func TestReadScene(t *testing.T) {
+	input := `{
+		"name": "house",
+		"shapes": [
+			{"type": "rectangle", "width": 4, "height": 3},
+			{"type": "triangle", "a": {"x": 0, "y": 3}, "b": {"x": 4, "y": 3}, "c": {"x": 2, "y": 5}}
+		]
+	}`
+
+	scene, err := ReadScene(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadScene: %v", err)
+	}
+	if scene.Name != "house" || len(scene.Shapes) != 2 {
+		t.Fatalf("scene = %+v", scene)
+	}
+	if area := TotalArea(scene.Shapes...); area != 16 {
+		t.Errorf("TotalArea = %.2f; want 16", area)
+	}
+
+	if _, err := ReadScene(strings.NewReader(`{"name":"x","colour":"red"}`)); err == nil {
+		t.Error("unknown scene fields should be rejected")
+	}
+}
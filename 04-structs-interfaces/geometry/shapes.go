@@ -0,0 +1,259 @@
+package geometry
+
+import "math"
+
+// Circle is defined by its center and radius
+type Circle struct {
+	Center Point   `json:"center,omitzero"`
+	Radius float64 `json:"radius"`
+}
+
+func (c Circle) Area() float64 {
+	return math.Pi * c.Radius * c.Radius
+}
+
+func (c Circle) Perimeter() float64 {
+	return 2 * math.Pi * c.Radius
+}
+
+func (c Circle) Bounds() BBox {
+	r := Point{c.Radius, c.Radius}
+	return BBox{Min: c.Center.Sub(r), Max: c.Center.Add(r)}
+}
+
+func (c Circle) Centroid() Point {
+	return c.Center
+}
+
+func (c Circle) Contains(p Point) bool {
+	return c.Center.Dist(p) <= c.Radius+Epsilon
+}
+
+func (c *Circle) Translate(dx, dy float64) {
+	c.Center = c.Center.Add(Point{dx, dy})
+}
+
+// Scale resizes the circle about its center
+func (c *Circle) Scale(factor float64) {
+	c.Radius *= math.Abs(factor)
+}
+
+// Rectangle is an axis-aligned rectangle anchored at its lower-left corner.
+// The zero Min keeps Rectangle{Width: w, Height: h} literals working.
+type Rectangle struct {
+	Min    Point   `json:"min,omitzero"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+func (r Rectangle) Area() float64 {
+	return r.Width * r.Height
+}
+
+func (r Rectangle) Perimeter() float64 {
+	return 2 * (r.Width + r.Height)
+}
+
+func (r Rectangle) Bounds() BBox {
+	return BBox{Min: r.Min, Max: r.Min.Add(Point{r.Width, r.Height})}
+}
+
+func (r Rectangle) Centroid() Point {
+	return r.Bounds().Center()
+}
+
+func (r Rectangle) Contains(p Point) bool {
+	return r.Bounds().Contains(p)
+}
+
+// Vertices returns the corners counter-clockwise from Min
+func (r Rectangle) Vertices() []Point {
+	return []Point{
+		r.Min,
+		r.Min.Add(Point{r.Width, 0}),
+		r.Min.Add(Point{r.Width, r.Height}),
+		r.Min.Add(Point{0, r.Height}),
+	}
+}
+
+func (r *Rectangle) Translate(dx, dy float64) {
+	r.Min = r.Min.Add(Point{dx, dy})
+}
+
+// Scale resizes the rectangle about its centroid, so Min moves as well as
+// the size. A negative factor acts like its absolute value, as a half turn
+// leaves an axis-aligned rectangle unchanged.
+func (r *Rectangle) Scale(factor float64) {
+	center := r.Centroid()
+	r.Width *= math.Abs(factor)
+	r.Height *= math.Abs(factor)
+	r.Min = center.Sub(Point{r.Width / 2, r.Height / 2})
+}
+
+// Triangle is defined by its three vertices
+type Triangle struct {
+	A Point `json:"a"`
+	B Point `json:"b"`
+	C Point `json:"c"`
+}
+
+func (t Triangle) Area() float64 {
+	return math.Abs(t.B.Sub(t.A).Cross(t.C.Sub(t.A))) / 2
+}
+
+func (t Triangle) Perimeter() float64 {
+	return t.A.Dist(t.B) + t.B.Dist(t.C) + t.C.Dist(t.A)
+}
+
+func (t Triangle) Bounds() BBox {
+	return BoundsOf(t.A, t.B, t.C)
+}
+
+func (t Triangle) Centroid() Point {
+	return t.A.Add(t.B).Add(t.C).Mul(1.0 / 3)
+}
+
+func (t Triangle) Contains(p Point) bool {
+	return polygonContains(t.Vertices(), p)
+}
+
+func (t Triangle) Vertices() []Point {
+	return []Point{t.A, t.B, t.C}
+}
+
+func (t *Triangle) Translate(dx, dy float64) {
+	d := Point{dx, dy}
+	t.A, t.B, t.C = t.A.Add(d), t.B.Add(d), t.C.Add(d)
+}
+
+// Scale resizes the triangle about its centroid
+func (t *Triangle) Scale(factor float64) {
+	c := t.Centroid()
+	t.A, t.B, t.C = t.A.scaleAbout(c, factor), t.B.scaleAbout(c, factor), t.C.scaleAbout(c, factor)
+}
+
+// Polygon is a simple (non self-intersecting) polygon given by its vertices
+// in order; the last vertex connects back to the first.
+type Polygon struct {
+	Points []Point `json:"points"`
+}
+
+// NewPolygon copies the vertices into a new polygon
+func NewPolygon(points ...Point) *Polygon {
+	return &Polygon{Points: append([]Point(nil), points...)}
+}
+
+// signedArea is positive for counter-clockwise vertex order
+func (p Polygon) signedArea() float64 {
+	sum := 0.0
+	edges(p.Points, func(a, b Point) bool {
+		sum += a.Cross(b)
+		return true
+	})
+	return sum / 2
+}
+
+func (p Polygon) Area() float64 {
+	return math.Abs(p.signedArea())
+}
+
+func (p Polygon) Perimeter() float64 {
+	total := 0.0
+	edges(p.Points, func(a, b Point) bool {
+		total += a.Dist(b)
+		return true
+	})
+	return total
+}
+
+func (p Polygon) Bounds() BBox {
+	return BoundsOf(p.Points...)
+}
+
+// Centroid returns the center of mass, falling back to the vertex average
+// for degenerate (zero-area) polygons
+func (p Polygon) Centroid() Point {
+	if len(p.Points) == 0 {
+		return Point{}
+	}
+
+	area := p.signedArea()
+	if math.Abs(area) <= Epsilon {
+		sum := Point{}
+		for _, v := range p.Points {
+			sum = sum.Add(v)
+		}
+		return sum.Mul(1 / float64(len(p.Points)))
+	}
+
+	var cx, cy float64
+	edges(p.Points, func(a, b Point) bool {
+		cross := a.Cross(b)
+		cx += (a.X + b.X) * cross
+		cy += (a.Y + b.Y) * cross
+		return true
+	})
+	return Point{cx / (6 * area), cy / (6 * area)}
+}
+
+func (p Polygon) Contains(pt Point) bool {
+	return polygonContains(p.Points, pt)
+}
+
+func (p Polygon) Vertices() []Point {
+	return p.Points
+}
+
+func (p *Polygon) Translate(dx, dy float64) {
+	d := Point{dx, dy}
+	for i := range p.Points {
+		p.Points[i] = p.Points[i].Add(d)
+	}
+}
+
+// Scale resizes the polygon about its centroid
+func (p *Polygon) Scale(factor float64) {
+	c := p.Centroid()
+	for i := range p.Points {
+		p.Points[i] = p.Points[i].scaleAbout(c, factor)
+	}
+}
+
+// polygonContains uses ray casting; points on an edge count as inside
+func polygonContains(vertices []Point, p Point) bool {
+	if len(vertices) < 3 {
+		return false
+	}
+
+	inside := false
+	onEdge := false
+	edges(vertices, func(a, b Point) bool {
+		if onSegment(p, a, b) {
+			onEdge = true
+			return false
+		}
+		if (a.Y > p.Y) != (b.Y > p.Y) {
+			x := a.X + (p.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y)
+			if p.X < x {
+				inside = !inside
+			}
+		}
+		return true
+	})
+	return onEdge || inside
+}
+
+// Compile-time checks that every shape satisfies the interfaces
+var (
+	_ Figure      = Circle{}
+	_ Figure      = Rectangle{}
+	_ Figure      = Triangle{}
+	_ Figure      = Polygon{}
+	_ Transformer = (*Circle)(nil)
+	_ Transformer = (*Rectangle)(nil)
+	_ Transformer = (*Triangle)(nil)
+	_ Transformer = (*Polygon)(nil)
+	_ Polygonal   = Rectangle{}
+	_ Polygonal   = Triangle{}
+	_ Polygonal   = Polygon{}
+)
@@ -1,8 +1,15 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
-	"math"
+	"io"
+	"reflect"
+	"regexp"
+
+	"grok-study-plan/04-structs-interfaces/geometry"
+	"grok-study-plan/04-structs-interfaces/ringbuffer"
+	"grok-study-plan/06-error-handling/validate"
 )
 
 // Basic struct definition
@@ -14,29 +21,41 @@ type Person struct {
 
 // Struct with tags (for JSON, etc.)
 type Employee struct {
-	ID       int     `json:"id"`
-	Name     string  `json:"name"`
-	Position string  `json:"position"`
-	Salary   float64 `json:"salary"`
+	ID       int     `json:"id" validate:"min=1"`
+	Name     string  `json:"name" validate:"required"`
+	Position string  `json:"position" validate:"oneof=Developer Manager Designer"`
+	Salary   float64 `json:"salary" validate:"min=0"`
 }
 
 // Struct with embedded fields
 type Address struct {
 	Street  string
-	City    string
-	Country string
+	City    string `validate:"required"`
+	Country string `validate:"len=3"`
 }
 
 type Contact struct {
-	Email string
-	Phone string
+	Email string `validate:"required,email"`
+	Phone string `validate:"phone"`
 }
 
 type User struct {
-	Name    string
-	Age     int
-	Address // Embedded struct
-	Contact // Embedded struct
+	Name    string `validate:"required,max=50"`
+	Age     int    `validate:"min=0,max=150"`
+	Address        // Embedded struct
+	Contact        // Embedded struct
+}
+
+var phonePattern = regexp.MustCompile(`^\d{3}-\d{4}$`)
+
+// Custom validation rule used by Contact.Phone
+func init() {
+	validate.RegisterRule("phone", func(v reflect.Value, _ string) error {
+		if s := v.String(); s != "" && !phonePattern.MatchString(s) {
+			return fmt.Errorf("%q is not a phone number like 555-0123", s)
+		}
+		return nil
+	})
 }
 
 // Method with value receiver
@@ -55,50 +74,16 @@ func (p Person) MoveTo(newCity string) Person {
 	return p
 }
 
-// Interface definition
-type Shape interface {
-	Area() float64
-	Perimeter() float64
-}
-
-// Circle implements Shape
-type Circle struct {
-	Radius float64
-}
-
-func (c Circle) Area() float64 {
-	return math.Pi * c.Radius * c.Radius
-}
-
-func (c Circle) Perimeter() float64 {
-	return 2 * math.Pi * c.Radius
-}
-
-// Rectangle implements Shape
-type Rectangle struct {
-	Width, Height float64
-}
-
-func (r Rectangle) Area() float64 {
-	return r.Width * r.Height
-}
-
-func (r Rectangle) Perimeter() float64 {
-	return 2 * (r.Width + r.Height)
-}
-
-// Triangle implements Shape
-type Triangle struct {
-	Base, Height, Side1, Side2 float64
-}
-
-func (t Triangle) Area() float64 {
-	return 0.5 * t.Base * t.Height
-}
-
-func (t Triangle) Perimeter() float64 {
-	return t.Base + t.Side1 + t.Side2
-}
+// Shapes live in the shared geometry package; the aliases keep the
+// short names used throughout this example.
+type (
+	Shape     = geometry.Shape
+	Point     = geometry.Point
+	Circle    = geometry.Circle
+	Rectangle = geometry.Rectangle
+	Triangle  = geometry.Triangle
+	Polygon   = geometry.Polygon
+)
 
 // Empty interface - can hold any type
 type Any interface{}
@@ -123,25 +108,12 @@ type ReadWriter interface {
 	Writer
 }
 
-// Simple implementation
-type Buffer struct {
-	data []byte
-}
-
-func (b *Buffer) Write(data []byte) (int, error) {
-	b.data = append(b.data, data...)
-	return len(data), nil
-}
+// Buffer is a fixed-capacity ring buffer from the ringbuffer package.
+// It satisfies ReadWriter above as well as io.Reader, io.Writer,
+// io.ByteReader, io.WriterTo and io.ReaderFrom, and returns io.EOF when empty.
+type Buffer = ringbuffer.Buffer
 
-func (b *Buffer) Read(data []byte) (int, error) {
-	if len(b.data) == 0 {
-		return 0, fmt.Errorf("buffer is empty")
-	}
-
-	n := copy(data, b.data)
-	b.data = b.data[n:]
-	return n, nil
-}
+var _ ReadWriter = (*Buffer)(nil)
 
 // Type assertion function
 func getArea(shape Any) float64 {
@@ -151,18 +123,19 @@ func getArea(shape Any) float64 {
 	return 0
 }
 
-// Type switch
+// describeShape looks the shape's name up in the geometry registry instead
+// of a type switch, so newly registered shapes need no changes here
 func describeShape(shape Any) {
-	switch s := shape.(type) {
-	case Circle:
-		fmt.Printf("Circle with radius %.2f\n", s.Radius)
-	case Rectangle:
-		fmt.Printf("Rectangle %.2fx%.2f\n", s.Width, s.Height)
-	case Triangle:
-		fmt.Printf("Triangle with base %.2f and height %.2f\n", s.Base, s.Height)
-	default:
+	s, ok := shape.(Shape)
+	if !ok {
 		fmt.Println("Unknown shape")
+		return
+	}
+	name, ok := geometry.DefaultRegistry.NameOf(s)
+	if !ok {
+		name = fmt.Sprintf("%T", s)
 	}
+	fmt.Printf("%s with area %.2f and perimeter %.2f\n", name, s.Area(), s.Perimeter())
 }
 
 // Interface{} usage (pre-generics)
@@ -215,11 +188,26 @@ func main() {
 	fmt.Println("User city:", user.City)   // Promoted field
 	fmt.Println("User email:", user.Email) // Promoted field
 
+	// Validation driven by the struct tags, including embedded structs
+	fmt.Println("Valid user:", validate.Struct(user) == nil)
+	user.City = ""
+	user.Email = "charlie.example.com"
+	user.Phone = "5550123"
+	if err := validate.Struct(user); err != nil {
+		fmt.Println("Invalid user:")
+		for _, e := range err.(validate.ValidationErrors) {
+			fmt.Printf("  %s: %s\n", e.Field, e.Message)
+		}
+	}
+	employee.Position = "Intern"
+	fmt.Println("Employee check:", validate.Struct(employee))
+
 	// Interface usage
 	shapes := []Shape{
 		Circle{Radius: 5},
 		Rectangle{Width: 10, Height: 5},
-		Triangle{Base: 6, Height: 4, Side1: 5, Side2: 5},
+		Triangle{A: Point{X: 0, Y: 0}, B: Point{X: 6, Y: 0}, C: Point{X: 3, Y: 4}},
+		geometry.NewPolygon(Point{X: 0, Y: 0}, Point{X: 4, Y: 0}, Point{X: 4, Y: 3}, Point{X: 2, Y: 5}, Point{X: 0, Y: 3}),
 	}
 
 	fmt.Println("\nShape calculations:")
@@ -228,6 +216,22 @@ func main() {
 			shape, shape.Area(), shape.Perimeter())
 	}
 
+	// Geometry: containment, intersection, transforms
+	fmt.Println("\nGeometry:")
+	house := shapes[3].(*Polygon)
+	fmt.Printf("House centroid: %v, bounds: %v-%v\n",
+		house.Centroid(), house.Bounds().Min, house.Bounds().Max)
+	fmt.Printf("House contains (2, 4)? %t, (4, 5)? %t\n",
+		house.Contains(Point{X: 2, Y: 4}), house.Contains(Point{X: 4, Y: 5}))
+
+	sun := &Circle{Center: Point{X: 8, Y: 6}, Radius: 1}
+	fmt.Printf("Sun intersects house? %t\n", geometry.Intersects(sun, house))
+	sun.Translate(-4, -2)
+	fmt.Printf("After moving sun to %v: intersects? %t\n", sun.Center, geometry.Intersects(sun, house))
+
+	house.Scale(2)
+	fmt.Printf("House scaled x2: area=%.2f, centroid still %v\n", house.Area(), house.Centroid())
+
 	// Empty interface
 	fmt.Println("\nEmpty interface examples:")
 	printType(42)
@@ -236,7 +240,7 @@ func main() {
 	printType(shapes[0])
 
 	// ReadWriter interface
-	buffer := &Buffer{}
+	buffer := ringbuffer.New(16, ringbuffer.Reject)
 	data := []byte("Hello, World!")
 
 	n, err := buffer.Write(data)
@@ -254,6 +258,15 @@ func main() {
 		fmt.Printf("Read %d bytes: %s\n", n, string(readData))
 	}
 
+	// An empty buffer reports io.EOF, like any other io.Reader
+	if _, err = buffer.Read(readData); err == io.EOF {
+		fmt.Println("Buffer drained: io.EOF")
+	}
+
+	// A full buffer rejects the bytes that do not fit
+	n, err = buffer.Write([]byte("This sentence is longer than 16 bytes"))
+	fmt.Printf("Wrote %d bytes to a %d-byte buffer: %v\n", n, buffer.Cap(), err)
+
 	// Type assertion
 	circle := Circle{Radius: 3}
 	area := getArea(circle)
@@ -263,9 +276,23 @@ func main() {
 	fmt.Println("\nShape descriptions:")
 	describeShape(circle)
 	describeShape(Rectangle{Width: 4, Height: 6})
-	describeShape(Triangle{Base: 3, Height: 4, Side1: 5, Side2: 5})
+	describeShape(Triangle{A: Point{X: 0, Y: 0}, B: Point{X: 3, Y: 0}, C: Point{X: 0, Y: 4}})
 	describeShape("not a shape")
 
+	// Polymorphic JSON: a "type" field selects the registered shape
+	fmt.Println("\nShapes as JSON:")
+	scene := geometry.Shapes{Circle{Radius: 2}, Rectangle{Width: 4, Height: 6}}
+	encoded, _ := json.Marshal(scene)
+	fmt.Println(string(encoded))
+
+	var decoded geometry.Shapes
+	if err := json.Unmarshal(encoded, &decoded); err == nil {
+		fmt.Printf("Decoded %d shapes, total area %.2f\n", len(decoded), geometry.TotalArea(decoded...))
+	}
+	if err := json.Unmarshal([]byte(`[{"type":"hexagon","side":1}]`), &decoded); err != nil {
+		fmt.Println("Decode error:", err)
+	}
+
 	// Interface slice
 	fmt.Println("\nInterface slice:")
 	mixedSlice := []interface{}{42, "hello", person, circle}
@@ -0,0 +1,312 @@
+// Package ringbuffer implements a fixed-capacity byte ring buffer that
+// satisfies io.Reader, io.Writer, io.ByteReader, io.ByteWriter,
+// io.WriterTo, io.ReaderFrom and io.Closer.
+package ringbuffer
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+var (
+	// ErrFull is returned by writes in Reject mode when there is no room
+	ErrFull = errors.New("ringbuffer: buffer is full")
+	// ErrClosed is returned by writes after Close
+	ErrClosed = errors.New("ringbuffer: write to closed buffer")
+)
+
+// Mode decides what a write does when the buffer is full
+type Mode int
+
+const (
+	// Reject writes what fits and returns ErrFull for the rest
+	Reject Mode = iota
+	// Overwrite discards the oldest unread bytes to make room
+	Overwrite
+	// Block waits for a reader to free space. Reads on an empty buffer
+	// also wait, until data arrives or the buffer is closed, which makes
+	// the buffer behave like a bounded io.Pipe.
+	Block
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Reject:
+		return "reject"
+	case Overwrite:
+		return "overwrite"
+	case Block:
+		return "block"
+	}
+	return "unknown"
+}
+
+// chunkSize bounds the temporary buffer used by WriteTo and ReadFrom
+const chunkSize = 32 * 1024
+
+// Buffer is a fixed-capacity FIFO of bytes. It is safe for concurrent use.
+type Buffer struct {
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+
+	data   []byte
+	start  int // index of the oldest unread byte
+	length int // number of unread bytes
+	mode   Mode
+	closed bool
+}
+
+// New returns an empty buffer holding at most capacity bytes
+func New(capacity int, mode Mode) *Buffer {
+	if capacity < 1 {
+		panic("ringbuffer: capacity must be at least 1")
+	}
+	b := &Buffer{data: make([]byte, capacity), mode: mode}
+	b.notEmpty = sync.NewCond(&b.mu)
+	b.notFull = sync.NewCond(&b.mu)
+	return b
+}
+
+// NewBlocking returns a buffer in Block mode for producer/consumer use
+func NewBlocking(capacity int) *Buffer {
+	return New(capacity, Block)
+}
+
+// Len returns the number of unread bytes
+func (b *Buffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.length
+}
+
+// Cap returns the fixed capacity
+func (b *Buffer) Cap() int {
+	return len(b.data)
+}
+
+// Free returns how many bytes can be written without waiting or losing data
+func (b *Buffer) Free() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.data) - b.length
+}
+
+// Mode returns the full-buffer behaviour chosen in New
+func (b *Buffer) Mode() Mode {
+	return b.mode
+}
+
+// Reset discards all unread data and reopens a closed buffer
+func (b *Buffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.start, b.length, b.closed = 0, 0, false
+	b.notFull.Broadcast()
+}
+
+// Close stops further writes. Buffered data can still be read, after which
+// reads return io.EOF. Blocked readers and writers are woken up.
+func (b *Buffer) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.notEmpty.Broadcast()
+	b.notFull.Broadcast()
+	return nil
+}
+
+// readLocked copies up to len(p) unread bytes into p
+func (b *Buffer) readLocked(p []byte) int {
+	n := min(len(p), b.length)
+	first := min(n, len(b.data)-b.start)
+	copy(p, b.data[b.start:b.start+first])
+	copy(p[first:n], b.data[:n-first])
+
+	b.start = (b.start + n) % len(b.data)
+	b.length -= n
+	if b.length == 0 {
+		b.start = 0 // keep data contiguous when possible
+	}
+	return n
+}
+
+// writeLocked copies as much of p as fits into free space
+func (b *Buffer) writeLocked(p []byte) int {
+	n := min(len(p), len(b.data)-b.length)
+	end := (b.start + b.length) % len(b.data)
+	first := min(n, len(b.data)-end)
+	copy(b.data[end:end+first], p[:first])
+	copy(b.data[:n-first], p[first:n])
+
+	b.length += n
+	return n
+}
+
+// discardLocked drops the n oldest bytes
+func (b *Buffer) discardLocked(n int) {
+	b.start = (b.start + n) % len(b.data)
+	b.length -= n
+}
+
+// waitReadable blocks in Block mode until there is data or the buffer is
+// closed. It reports whether data is available.
+func (b *Buffer) waitReadable() bool {
+	for b.length == 0 && b.mode == Block && !b.closed {
+		b.notEmpty.Wait()
+	}
+	return b.length > 0
+}
+
+// Read reads up to len(p) bytes. It returns io.EOF when the buffer is
+// empty, except in Block mode where it waits until data arrives or the
+// buffer is closed.
+func (b *Buffer) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.waitReadable() {
+		return 0, io.EOF
+	}
+	n := b.readLocked(p)
+	b.notFull.Broadcast()
+	return n, nil
+}
+
+// ReadByte reads a single byte, with the same empty-buffer rules as Read
+func (b *Buffer) ReadByte() (byte, error) {
+	var one [1]byte
+	if _, err := b.Read(one[:]); err != nil {
+		return 0, err
+	}
+	return one[0], nil
+}
+
+// Write appends p. What happens when p does not fit depends on the Mode.
+func (b *Buffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return 0, ErrClosed
+	}
+
+	switch b.mode {
+	case Overwrite:
+		// Only the newest Cap() bytes of p can survive
+		if len(p) > len(b.data) {
+			b.start, b.length = 0, 0
+			b.writeLocked(p[len(p)-len(b.data):])
+		} else {
+			if overflow := len(p) - (len(b.data) - b.length); overflow > 0 {
+				b.discardLocked(overflow)
+			}
+			b.writeLocked(p)
+		}
+		b.notEmpty.Broadcast()
+		return len(p), nil
+
+	case Block:
+		written := 0
+		for written < len(p) {
+			for b.length == len(b.data) && !b.closed {
+				b.notFull.Wait()
+			}
+			if b.closed {
+				return written, ErrClosed
+			}
+			written += b.writeLocked(p[written:])
+			b.notEmpty.Broadcast()
+		}
+		return written, nil
+
+	default:
+		n := b.writeLocked(p)
+		if n > 0 {
+			b.notEmpty.Broadcast()
+		}
+		if n < len(p) {
+			return n, ErrFull
+		}
+		return n, nil
+	}
+}
+
+// WriteByte appends a single byte
+func (b *Buffer) WriteByte(c byte) error {
+	_, err := b.Write([]byte{c})
+	return err
+}
+
+// WriteTo drains the buffer into w. In Block mode it keeps going until the
+// buffer is closed and empty, like io.Copy from a pipe. Bytes that w fails
+// to accept are lost.
+func (b *Buffer) WriteTo(w io.Writer) (int64, error) {
+	chunk := make([]byte, min(chunkSize, len(b.data)))
+	var total int64
+	for {
+		n, err := b.Read(chunk)
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+
+		m, err := w.Write(chunk[:n])
+		total += int64(m)
+		if err != nil {
+			return total, err
+		}
+		if m < n {
+			return total, io.ErrShortWrite
+		}
+	}
+}
+
+// ReadFrom writes everything read from r into the buffer until r returns
+// io.EOF. In Reject mode it stops with ErrFull once the buffer fills up,
+// without consuming more from r than it can store.
+func (b *Buffer) ReadFrom(r io.Reader) (int64, error) {
+	chunk := make([]byte, min(chunkSize, len(b.data)))
+	var total int64
+	for {
+		want := chunk
+		if b.mode == Reject {
+			free := b.Free()
+			if free == 0 {
+				return total, ErrFull
+			}
+			want = chunk[:min(free, len(chunk))]
+		}
+
+		n, readErr := r.Read(want)
+		if n > 0 {
+			m, err := b.Write(want[:n])
+			total += int64(m)
+			if err != nil {
+				return total, err
+			}
+		}
+		if readErr == io.EOF {
+			return total, nil
+		}
+		if readErr != nil {
+			return total, readErr
+		}
+	}
+}
+
+// Compile-time interface checks
+var (
+	_ io.ReadWriteCloser = (*Buffer)(nil)
+	_ io.ByteReader      = (*Buffer)(nil)
+	_ io.ByteWriter      = (*Buffer)(nil)
+	_ io.WriterTo        = (*Buffer)(nil)
+	_ io.ReaderFrom      = (*Buffer)(nil)
+)
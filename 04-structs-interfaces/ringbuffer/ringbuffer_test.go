@@ -0,0 +1,287 @@
+package ringbuffer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestReaderContract(t *testing.T) {
+	content := []byte("The quick brown fox jumps over the lazy dog")
+
+	for _, mode := range []Mode{Reject, Overwrite} {
+		t.Run(mode.String(), func(t *testing.T) {
+			b := New(len(content), mode)
+			if _, err := b.Write(content); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			if err := iotest.TestReader(b, content); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestReaderContractWrapped(t *testing.T) {
+	// Force the data to wrap around the end of the backing array
+	b := New(16, Reject)
+	b.Write([]byte("0123456789"))
+	b.Read(make([]byte, 8))
+	b.Write([]byte("abcdefgh"))
+	content := []byte("89abcdefgh")
+
+	if b.start == 0 {
+		t.Fatal("test setup should leave the buffer wrapped")
+	}
+	if err := iotest.TestReader(b, content); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEmptyReadReturnsEOF(t *testing.T) {
+	b := New(4, Reject)
+	if n, err := b.Read(make([]byte, 4)); n != 0 || err != io.EOF {
+		t.Errorf("Read on empty = %d, %v; want 0, io.EOF", n, err)
+	}
+	if _, err := b.ReadByte(); err != io.EOF {
+		t.Errorf("ReadByte on empty = %v; want io.EOF", err)
+	}
+	if n, err := b.Read(nil); n != 0 || err != nil {
+		t.Errorf("Read(nil) = %d, %v; want 0, nil", n, err)
+	}
+}
+
+func TestRejectMode(t *testing.T) {
+	b := New(5, Reject)
+
+	n, err := b.Write([]byte("hello world"))
+	if n != 5 || !errors.Is(err, ErrFull) {
+		t.Errorf("Write = %d, %v; want 5, ErrFull", n, err)
+	}
+	if err := b.WriteByte('!'); !errors.Is(err, ErrFull) {
+		t.Errorf("WriteByte on full = %v; want ErrFull", err)
+	}
+
+	got, _ := io.ReadAll(b)
+	if string(got) != "hello" {
+		t.Errorf("ReadAll = %q; want %q", got, "hello")
+	}
+}
+
+func TestOverwriteMode(t *testing.T) {
+	testCases := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{"fits", []string{"abc"}, "abc"},
+		{"drops oldest", []string{"abcd", "ef"}, "cdef"},
+		{"single large write", []string{"abcdefghij"}, "ghij"},
+		{"many small writes", []string{"a", "b", "c", "d", "e", "f"}, "cdef"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := New(4, Overwrite)
+			for _, w := range tc.writes {
+				if n, err := b.Write([]byte(w)); n != len(w) || err != nil {
+					t.Fatalf("Write(%q) = %d, %v", w, n, err)
+				}
+			}
+			got, _ := io.ReadAll(b)
+			if string(got) != tc.want {
+				t.Errorf("contents = %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestByteReadWrite(t *testing.T) {
+	b := New(3, Reject)
+	for _, c := range []byte("xyz") {
+		if err := b.WriteByte(c); err != nil {
+			t.Fatalf("WriteByte(%q): %v", c, err)
+		}
+	}
+
+	var got []byte
+	for {
+		c, err := b.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		got = append(got, c)
+	}
+	if string(got) != "xyz" {
+		t.Errorf("bytes = %q; want %q", got, "xyz")
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	b := New(32, Reject)
+	b.Write([]byte("stream me"))
+
+	var out bytes.Buffer
+	n, err := b.WriteTo(&out)
+	if err != nil || n != 9 || out.String() != "stream me" {
+		t.Errorf("WriteTo = %d, %v, %q", n, err, out.String())
+	}
+	if b.Len() != 0 {
+		t.Errorf("Len after WriteTo = %d; want 0", b.Len())
+	}
+
+	// Short writes by the destination are reported
+	b.Write([]byte("data"))
+	if _, err := b.WriteTo(shortWriter{}); !errors.Is(err, io.ErrShortWrite) {
+		t.Errorf("WriteTo short writer = %v; want io.ErrShortWrite", err)
+	}
+}
+
+// shortWriter accepts one byte less than it is given, without an error
+type shortWriter struct{}
+
+func (shortWriter) Write(p []byte) (int, error) {
+	return max(len(p)-1, 0), nil
+}
+
+func TestReadFrom(t *testing.T) {
+	content := "abcdefghijklmnopqrstuvwxyz"
+
+	readers := map[string]func() io.Reader{
+		"plain":    func() io.Reader { return strings.NewReader(content) },
+		"one byte": func() io.Reader { return iotest.OneByteReader(strings.NewReader(content)) },
+		"half":     func() io.Reader { return iotest.HalfReader(strings.NewReader(content)) },
+		"data+err": func() io.Reader { return iotest.DataErrReader(strings.NewReader(content)) },
+	}
+
+	for name, newReader := range readers {
+		t.Run(name, func(t *testing.T) {
+			b := New(64, Reject)
+			n, err := b.ReadFrom(newReader())
+			if err != nil || n != int64(len(content)) {
+				t.Fatalf("ReadFrom = %d, %v; want %d, nil", n, err, len(content))
+			}
+			if err := iotest.TestReader(b, []byte(content)); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestReadFromErrors(t *testing.T) {
+	boom := errors.New("boom")
+	b := New(8, Reject)
+	if _, err := b.ReadFrom(iotest.ErrReader(boom)); !errors.Is(err, boom) {
+		t.Errorf("ReadFrom(ErrReader) = %v; want boom", err)
+	}
+
+	// A full Reject buffer stops without consuming the rest of the source
+	src := strings.NewReader("0123456789")
+	n, err := b.ReadFrom(src)
+	if n != 8 || !errors.Is(err, ErrFull) {
+		t.Errorf("ReadFrom into small buffer = %d, %v; want 8, ErrFull", n, err)
+	}
+	if src.Len() != 2 {
+		t.Errorf("source has %d bytes left; want 2", src.Len())
+	}
+}
+
+func TestCloseAndReset(t *testing.T) {
+	b := New(8, Reject)
+	b.Write([]byte("ab"))
+	b.Close()
+
+	if _, err := b.Write([]byte("c")); !errors.Is(err, ErrClosed) {
+		t.Errorf("Write after Close = %v; want ErrClosed", err)
+	}
+	if got, _ := io.ReadAll(b); string(got) != "ab" {
+		t.Errorf("buffered data after Close = %q; want %q", got, "ab")
+	}
+
+	b.Reset()
+	if _, err := b.Write([]byte("c")); err != nil {
+		t.Errorf("Write after Reset = %v; want nil", err)
+	}
+}
+
+func TestBlockingProducerConsumer(t *testing.T) {
+	// Much more data than capacity forces both sides to wait on each other
+	var want bytes.Buffer
+	for i := 0; i < 1000; i++ {
+		want.WriteString("line of producer data\n")
+	}
+
+	b := NewBlocking(16)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer b.Close()
+		src := iotest.HalfReader(bytes.NewReader(want.Bytes()))
+		if _, err := b.ReadFrom(src); err != nil {
+			t.Errorf("producer: %v", err)
+		}
+	}()
+
+	var got bytes.Buffer
+	if _, err := b.WriteTo(&got); err != nil {
+		t.Errorf("consumer: %v", err)
+	}
+	wg.Wait()
+
+	if !bytes.Equal(got.Bytes(), want.Bytes()) {
+		t.Errorf("consumer received %d bytes; want %d", got.Len(), want.Len())
+	}
+}
+
+func TestBlockingReadWaitsForData(t *testing.T) {
+	b := NewBlocking(4)
+	result := make(chan string, 1)
+	go func() {
+		p := make([]byte, 4)
+		n, _ := b.Read(p)
+		result <- string(p[:n])
+	}()
+
+	select {
+	case <-result:
+		t.Fatal("Read returned before any data was written")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	b.Write([]byte("hi"))
+	select {
+	case got := <-result:
+		if got != "hi" {
+			t.Errorf("Read = %q; want %q", got, "hi")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Read did not wake up after Write")
+	}
+}
+
+func TestBlockingCloseWakesWriter(t *testing.T) {
+	b := NewBlocking(2)
+	errc := make(chan error, 1)
+	go func() {
+		_, err := b.Write([]byte("too much"))
+		errc <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	b.Close()
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("blocked Write = %v; want ErrClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close did not wake the blocked writer")
+	}
+}
@@ -0,0 +1,301 @@
+// Command allocreport measures the value-vs-pointer functions that
+// 05-pointers-memory calls, which live in its examples package. Each one
+// runs under testing.AllocsPerRun and runtime.MemStats, and the results are
+// printed as a table of allocations, bytes and time per call. The examples
+// package is then compiled with go build -gcflags=-m and the compiler's
+// escape-analysis decisions are listed under the function they belong to.
+//
+// Usage:
+//
+//	go run ./05-pointers-memory/allocreport [-runs N] [-escapes=false] [-v]
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"text/tabwriter"
+	"time"
+
+	"grok-study-plan/05-pointers-memory/examples"
+)
+
+// example is one measured case; fn names the function in the examples
+// package whose escape decisions belong to it
+type example struct {
+	name string
+	fn   string
+	run  func()
+}
+
+var cases = []example{
+	{"pass struct by value", "ModifyValue", func() {
+		p := examples.Person{Name: "Alice", Age: 25}
+		sinkPersonValue = examples.ModifyValue(p)
+	}},
+	{"pass struct by pointer", "ModifyPointer", func() {
+		p := examples.Person{Name: "Alice", Age: 25}
+		examples.ModifyPointer(&p)
+	}},
+	{"return pointer to local", "NewPerson", func() {
+		sinkPerson = examples.NewPerson("Bob", 30)
+	}},
+	{"new(Person)", "NewZeroPerson", func() {
+		sinkPerson = examples.NewZeroPerson()
+	}},
+	{"make([]int, 3, 10)", "MakeSlice", func() {
+		sinkSlice = examples.MakeSlice(3, 10)
+	}},
+	{"make(map[string]int, 2)", "MakeMap", func() {
+		sinkMap = examples.MakeMap(2)
+	}},
+	{"pass slice", "ModifySlice", func() {
+		s := []int{1, 2, 3, 4, 5}
+		examples.ModifySlice(s)
+	}},
+	{"pass map", "ModifyMap", func() {
+		examples.ModifyMap(sharedMap)
+	}},
+	{"SetAddress on new Employee", "Employee.SetAddress", func() {
+		var e examples.Employee
+		e.SetAddress("123 Main St", "Anytown")
+	}},
+}
+
+// Package-level sinks keep results alive so the work is not optimized away
+var (
+	sinkPerson      *examples.Person
+	sinkPersonValue examples.Person
+	sinkSlice       []int
+	sinkMap         map[string]int
+)
+
+// sharedMap already holds "modified", so ModifyMap never grows it
+var sharedMap = map[string]int{"alice": 100, "modified": 0}
+
+// result holds the per-call cost of one example
+type result struct {
+	allocs float64
+	bytes  float64
+	ns     float64
+}
+
+func measure(run func(), runs int) result {
+	allocs := testing.AllocsPerRun(runs, run)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for range runs {
+		run()
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	return result{
+		allocs: allocs,
+		bytes:  float64(after.TotalAlloc-before.TotalAlloc) / float64(runs),
+		ns:     float64(elapsed.Nanoseconds()) / float64(runs),
+	}
+}
+
+// examplesPath is the import path of the package under analysis
+var examplesPath = reflect.TypeFor[examples.Person]().PkgPath()
+
+// packageDir asks the go command where the examples package lives. It runs
+// in this command's source directory, so it works from anywhere inside the
+// module; -dir overrides it when the source has moved.
+func packageDir() (string, error) {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		return "", errors.New("cannot determine source location")
+	}
+	cmd := exec.Command("go", "list", "-f", "{{.Dir}}", examplesPath)
+	cmd.Dir = filepath.Dir(file)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("go list %s: %w; pass -dir", examplesPath, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// decisionPattern matches compiler diagnostics such as
+// ./examples.go:38:2: moved to heap: p
+var decisionPattern = regexp.MustCompile(`^(.+\.go):(\d+):\d+: (.+)$`)
+
+// escapeDecisions compiles the package in dir with go build -gcflags=-m and
+// groups the escape analysis lines by enclosing function
+func escapeDecisions(dir string, verbose bool) (map[string][]string, error) {
+	cmd := exec.Command("go", "build", "-gcflags=-m", ".")
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("go build -gcflags=-m: %w\n%s", err, stderr.String())
+	}
+
+	spans, err := functionSpans(dir)
+	if err != nil {
+		return nil, err
+	}
+	return parseDecisions(&stderr, spans, verbose)
+}
+
+// parseDecisions reads the compiler's -m output and files each decision
+// under the function whose lines contain it. Inlining notes are kept only
+// when verbose is set, and lines outside any known function are dropped.
+func parseDecisions(r io.Reader, spans fileSpans, verbose bool) (map[string][]string, error) {
+	decisions := make(map[string][]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		m := decisionPattern.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		msg := m[3]
+		if !verbose && (strings.HasPrefix(msg, "can inline") || strings.HasPrefix(msg, "inlining call")) {
+			continue
+		}
+		line, _ := strconv.Atoi(m[2])
+		file := filepath.Base(m[1])
+		fn := spans.lookup(file, line)
+		if fn == "" {
+			continue
+		}
+		entry := fmt.Sprintf("%s:%d: %s", file, line, msg)
+		if !slices.Contains(decisions[fn], entry) {
+			decisions[fn] = append(decisions[fn], entry)
+		}
+	}
+	return decisions, scanner.Err()
+}
+
+// span is the line range of one function declaration
+type span struct {
+	name       string
+	start, end int
+}
+
+type fileSpans map[string][]span
+
+func (fs fileSpans) lookup(file string, line int) string {
+	for _, s := range fs[file] {
+		if line >= s.start && line <= s.end {
+			return s.name
+		}
+	}
+	return ""
+}
+
+// functionSpans parses the Go files in dir and records where each function
+// starts and ends. Methods are named Type.Method.
+func functionSpans(dir string) (fileSpans, error) {
+	fset := token.NewFileSet()
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	spans := make(fileSpans)
+	for _, path := range matches {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		base := filepath.Base(path)
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			spans[base] = append(spans[base], span{
+				name:  funcName(fd),
+				start: fset.Position(fd.Pos()).Line,
+				end:   fset.Position(fd.End()).Line,
+			})
+		}
+	}
+	return spans, nil
+}
+
+func funcName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return fd.Name.Name
+	}
+	recv := fd.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	if ident, ok := recv.(*ast.Ident); ok {
+		return ident.Name + "." + fd.Name.Name
+	}
+	return fd.Name.Name
+}
+
+func main() {
+	runs := flag.Int("runs", 100000, "calls per example")
+	escapes := flag.Bool("escapes", true, "run go build -gcflags=-m and list escape decisions")
+	verbose := flag.Bool("v", false, "include inlining decisions")
+	dir := flag.String("dir", "", "source directory of the examples package (default: located with go list)")
+	flag.Parse()
+
+	fmt.Printf("Allocation report (%d calls per example, %s)\n\n", *runs, runtime.Version())
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Example\tFunction\tallocs/op\tB/op\tns/op")
+	for _, ex := range cases {
+		r := measure(ex.run, *runs)
+		fmt.Fprintf(tw, "%s\t%s\t%.0f\t%.0f\t%.1f\n", ex.name, ex.fn, r.allocs, r.bytes, r.ns)
+	}
+	tw.Flush()
+
+	if !*escapes {
+		return
+	}
+
+	fmt.Printf("\nEscape analysis of %s (go build -gcflags=-m):\n", examplesPath)
+	src := *dir
+	if src == "" {
+		var err error
+		if src, err = packageDir(); err != nil {
+			fmt.Fprintln(os.Stderr, "allocreport:", err)
+			os.Exit(1)
+		}
+	}
+	decisions, err := escapeDecisions(src, *verbose)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "allocreport:", err)
+		os.Exit(1)
+	}
+
+	for _, ex := range cases {
+		fmt.Printf("\n%s\n", ex.fn)
+		if len(decisions[ex.fn]) == 0 {
+			fmt.Println("  (nothing escapes)")
+			continue
+		}
+		for _, d := range decisions[ex.fn] {
+			fmt.Println("  " + d)
+		}
+	}
+}
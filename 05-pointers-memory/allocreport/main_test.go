@@ -0,0 +1,88 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// transcript is real go build -gcflags=-m output for the examples package,
+// with the header line and an inlining note the report should filter
+const transcript = `# grok-study-plan/05-pointers-memory/examples
+./examples.go:20:6: can inline ModifyValue
+./examples.go:20:18: leaking param: p to result ~r0 level=0
+./examples.go:38:16: leaking param: name
+./examples.go:39:2: moved to heap: p
+./examples.go:46:18: s does not escape
+./examples.go:46:18: s does not escape
+./examples.go:73:7: e does not escape
+./examples.go:75:15: &Address{} escapes to heap
+./other.go:5:1: leaking param: x
+not a diagnostic line
+`
+
+var spans = fileSpans{
+	"examples.go": {
+		{"ModifyValue", 20, 24},
+		{"NewPerson", 38, 41},
+		{"ModifySlice", 46, 50},
+		{"Employee.SetAddress", 73, 79},
+	},
+}
+
+func TestParseDecisions(t *testing.T) {
+	got, err := parseDecisions(strings.NewReader(transcript), spans, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"ModifyValue": {"examples.go:20: leaking param: p to result ~r0 level=0"},
+		"NewPerson": {
+			"examples.go:38: leaking param: name",
+			"examples.go:39: moved to heap: p",
+		},
+		// The duplicate line is reported once
+		"ModifySlice": {"examples.go:46: s does not escape"},
+		"Employee.SetAddress": {
+			"examples.go:73: e does not escape",
+			"examples.go:75: &Address{} escapes to heap",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseDecisions =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestParseDecisionsVerbose(t *testing.T) {
+	got, err := parseDecisions(strings.NewReader(transcript), spans, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first := got["ModifyValue"][0]; first != "examples.go:20: can inline ModifyValue" {
+		t.Errorf("first ModifyValue decision = %q; want the inlining note", first)
+	}
+}
+
+func TestFunctionSpansCoverEveryExample(t *testing.T) {
+	spans, err := functionSpans("../examples")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make(map[string]bool)
+	for _, fileSpans := range spans {
+		for _, s := range fileSpans {
+			if s.start > s.end {
+				t.Errorf("%s spans lines %d to %d", s.name, s.start, s.end)
+			}
+			names[s.name] = true
+		}
+	}
+	// Every measured function must exist, or its escape decisions would
+	// silently go missing from the report
+	for _, ex := range cases {
+		if !names[ex.fn] {
+			t.Errorf("example %q measures %s, which is not declared in the examples package", ex.name, ex.fn)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Package deepcopy makes true copies of values whose fields hold pointers,
+// slices and maps, where plain assignment would leave both copies sharing
+// the same underlying data.
+//
+// Sharing inside the value is preserved: if two fields point at the same
+// Address, the copy has two fields pointing at one new Address, and cyclic
+// structures come out with the same cycles.
+//
+// Unexported fields cannot be set through reflection, so they are copied
+// the way assignment copies them: shallowly. Types whose unexported state
+// must not be shared should implement Cloner, and DeepCopyStrict reports
+// any unexported field that would end up shared.
+package deepcopy
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Cloner lets a type take over copying of its values. The method must
+// return a value of the receiver's own type, and must not call DeepCopy on
+// that same type or it will recurse forever.
+type Cloner[T any] interface {
+	DeepCopy() T
+}
+
+// UnexportedFieldError reports an unexported field that DeepCopyStrict
+// could only copy shallowly
+type UnexportedFieldError struct {
+	Path string       // field path from the root, such as Manager.secrets
+	Type reflect.Type // the struct holding the field
+}
+
+func (e *UnexportedFieldError) Error() string {
+	return fmt.Sprintf("deepcopy: unexported field %s of %v would be shared; implement Cloner", e.Path, e.Type)
+}
+
+// DeepCopy returns a copy of v that shares no pointers, slices or maps
+// with it, apart from unexported fields, channels and funcs
+func DeepCopy[T any](v T) T {
+	out, _ := copyRoot(v, false)
+	return out
+}
+
+// DeepCopyStrict is DeepCopy, but returns an *UnexportedFieldError instead
+// of silently sharing the contents of an unexported field
+func DeepCopyStrict[T any](v T) (T, error) {
+	return copyRoot(v, true)
+}
+
+func copyRoot[T any](v T, strict bool) (T, error) {
+	c := &copier{strict: strict, copies: make(map[ref]reflect.Value)}
+
+	// Going through pointers keeps an interface-typed T intact, nil included
+	out := new(T)
+	c.copyInto(reflect.ValueOf(out).Elem(), reflect.ValueOf(&v).Elem(), "")
+	if c.err != nil {
+		var zero T
+		return zero, c.err
+	}
+	return *out, nil
+}
+
+// ref identifies data that may be referenced from several places. The type
+// is part of the key because a struct and its first field share an address,
+// and the length because slices of one array can differ in length.
+type ref struct {
+	addr uintptr
+	typ  reflect.Type
+	len  int
+}
+
+type copier struct {
+	strict bool
+	err    error
+	copies map[ref]reflect.Value // original -> its copy, for sharing and cycles
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// clone calls a DeepCopy method returning src's own type, if there is one
+func clone(src reflect.Value) (reflect.Value, bool) {
+	if !src.CanInterface() {
+		return reflect.Value{}, false
+	}
+	if src.Kind() == reflect.Pointer && src.IsNil() {
+		return reflect.Value{}, false
+	}
+	m := src.MethodByName("DeepCopy")
+	if !m.IsValid() {
+		return reflect.Value{}, false
+	}
+	mt := m.Type()
+	if mt.NumIn() != 0 || mt.NumOut() != 1 || mt.Out(0) != src.Type() {
+		return reflect.Value{}, false
+	}
+	return m.Call(nil)[0], true
+}
+
+// copyInto fills dst, a zero or shallow-copied value of src's type, with a
+// deep copy of src
+func (c *copier) copyInto(dst, src reflect.Value, path string) {
+	// Pointers check for a Cloner after the shared-reference lookup below
+	if src.Kind() != reflect.Pointer {
+		if cloned, ok := clone(src); ok {
+			dst.Set(cloned)
+			return
+		}
+	}
+
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		key := ref{addr: src.Pointer(), typ: src.Type()}
+		if existing, ok := c.copies[key]; ok {
+			dst.Set(existing)
+			return
+		}
+		if cloned, ok := clone(src); ok {
+			c.copies[key] = cloned
+			dst.Set(cloned)
+			return
+		}
+		ptr := reflect.New(src.Type().Elem())
+		c.copies[key] = ptr // registered before recursing so cycles resolve
+		c.copyInto(ptr.Elem(), src.Elem(), path)
+		dst.Set(ptr)
+
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		elem := src.Elem()
+		tmp := reflect.New(elem.Type()).Elem()
+		c.copyInto(tmp, elem, path)
+		dst.Set(tmp)
+
+	case reflect.Struct:
+		c.copyStruct(dst, src, path)
+
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		key := ref{addr: src.Pointer(), typ: src.Type(), len: src.Len()}
+		if existing, ok := c.copies[key]; ok {
+			dst.Set(existing)
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		c.copies[key] = s
+		for i := 0; i < src.Len(); i++ {
+			c.copyInto(s.Index(i), src.Index(i), fmt.Sprintf("%s[%d]", path, i))
+		}
+		dst.Set(s)
+
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			c.copyInto(dst.Index(i), src.Index(i), fmt.Sprintf("%s[%d]", path, i))
+		}
+
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		key := ref{addr: src.Pointer(), typ: src.Type()}
+		if existing, ok := c.copies[key]; ok {
+			dst.Set(existing)
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		c.copies[key] = m
+		iter := src.MapRange()
+		for iter.Next() {
+			// Keys are comparable values and are kept as they are
+			val := reflect.New(src.Type().Elem()).Elem()
+			c.copyInto(val, iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key()))
+			m.SetMapIndex(iter.Key(), val)
+		}
+		dst.Set(m)
+
+	default:
+		// Scalars are copied by value; channels, funcs and unsafe pointers
+		// have no meaningful deep copy and stay shared
+		if dst.CanSet() {
+			dst.Set(src)
+		}
+	}
+}
+
+func (c *copier) copyStruct(dst, src reflect.Value, path string) {
+	// Assignment copies every field, unexported ones included. It is skipped
+	// for an unexported embedded struct, which the enclosing struct's
+	// assignment already copied.
+	if src.CanInterface() && dst.CanSet() {
+		dst.Set(src)
+	}
+
+	t := src.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fieldPath := joinPath(path, f.Name)
+		switch {
+		case f.IsExported():
+			c.copyInto(dst.Field(i), src.Field(i), fieldPath)
+		case f.Anonymous && f.Type.Kind() == reflect.Struct:
+			// Exported fields promoted from an unexported embedded struct
+			// can still be set
+			c.copyStruct(dst.Field(i), src.Field(i), fieldPath)
+		case c.strict && c.err == nil && !src.Field(i).IsZero() && holdsReferences(f.Type):
+			c.err = &UnexportedFieldError{Path: fieldPath, Type: t}
+		}
+	}
+}
+
+// holdsReferences reports whether values of t can share data when assigned
+func holdsReferences(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface,
+		reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return true
+	case reflect.Array:
+		return holdsReferences(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if holdsReferences(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
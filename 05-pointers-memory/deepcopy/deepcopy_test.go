@@ -0,0 +1,183 @@
+package deepcopy
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type Address struct {
+	Street string
+	City   string
+}
+
+type Employee struct {
+	Name    string
+	Home    *Address
+	Work    *Address
+	Skills  []string
+	Scores  map[string][]int
+	Manager *Employee
+	Reports []*Employee
+}
+
+func TestDeepCopyIndependent(t *testing.T) {
+	orig := Employee{
+		Name:   "Ann",
+		Home:   &Address{Street: "1 Main St", City: "Boston"},
+		Skills: []string{"go", "sql"},
+		Scores: map[string][]int{"q1": {90, 85}},
+	}
+
+	cp := DeepCopy(orig)
+	if !reflect.DeepEqual(cp, orig) {
+		t.Fatalf("DeepCopy = %+v; want %+v", cp, orig)
+	}
+
+	cp.Home.City = "NYC"
+	cp.Skills[0] = "rust"
+	cp.Scores["q1"][0] = 0
+	cp.Scores["q2"] = nil
+
+	if orig.Home.City != "Boston" || orig.Skills[0] != "go" || orig.Scores["q1"][0] != 90 || len(orig.Scores) != 1 {
+		t.Errorf("modifying the copy changed the original: %+v", orig)
+	}
+}
+
+func TestDeepCopySharing(t *testing.T) {
+	addr := &Address{City: "Boston"}
+	team := []*Employee{
+		{Name: "Ann", Home: addr, Work: addr},
+		{Name: "Bob", Home: addr},
+	}
+	team[1].Manager = team[0]
+	team[0].Reports = []*Employee{team[1]}
+
+	cp := DeepCopy(team)
+
+	if cp[0].Home == addr {
+		t.Fatal("copy still points at the original Address")
+	}
+	if cp[0].Home != cp[0].Work || cp[0].Home != cp[1].Home {
+		t.Error("fields sharing one Address should share one copy")
+	}
+	if cp[1].Manager != cp[0] || cp[0].Reports[0] != cp[1] {
+		t.Error("the manager/report cycle should point at the copies")
+	}
+}
+
+func TestDeepCopyCycle(t *testing.T) {
+	e := &Employee{Name: "Self"}
+	e.Manager = e
+
+	cp := DeepCopy(e)
+	if cp == e || cp.Manager != cp {
+		t.Errorf("self cycle not preserved: cp=%p cp.Manager=%p orig=%p", cp, cp.Manager, e)
+	}
+}
+
+func TestDeepCopyKinds(t *testing.T) {
+	type inner struct {
+		Values []int
+	}
+	type wrapper struct {
+		Any   any
+		Array [2]*Address
+		inner // unexported embedded: its exported fields are still copied
+		Fn    func() int
+	}
+
+	addr := &Address{City: "Boston"}
+	orig := wrapper{
+		Any:   []int{1, 2},
+		Array: [2]*Address{addr, addr},
+		inner: inner{Values: []int{3}},
+		Fn:    func() int { return 1 },
+	}
+
+	cp := DeepCopy(orig)
+	cp.Any.([]int)[0] = 99
+	cp.Values[0] = 99
+	cp.Array[0].City = "NYC"
+
+	if orig.Any.([]int)[0] != 1 || orig.Values[0] != 3 || addr.City != "Boston" {
+		t.Errorf("copy shares data with original: %+v", orig)
+	}
+	if cp.Array[0] != cp.Array[1] {
+		t.Error("array elements sharing a pointer should share the copy")
+	}
+	if cp.Fn() != 1 {
+		t.Error("funcs should be kept")
+	}
+
+	var nothing any
+	if got := DeepCopy(nothing); got != nil {
+		t.Errorf("DeepCopy(nil any) = %v; want nil", got)
+	}
+	if got := DeepCopy[*Employee](nil); got != nil {
+		t.Errorf("DeepCopy(nil pointer) = %v; want nil", got)
+	}
+	if got := DeepCopy(42); got != 42 {
+		t.Errorf("DeepCopy(42) = %v", got)
+	}
+}
+
+// Secret hides its data in an unexported field
+type Secret struct {
+	Label string
+	data  []byte
+}
+
+// Vault copies its unexported field itself
+type Vault struct {
+	Label  string
+	items  []string
+	copies *int
+}
+
+func (v *Vault) DeepCopy() *Vault {
+	*v.copies++
+	return &Vault{Label: v.Label, items: append([]string(nil), v.items...), copies: v.copies}
+}
+
+var _ Cloner[*Vault] = (*Vault)(nil)
+
+func TestUnexportedFields(t *testing.T) {
+	orig := Secret{Label: "key", data: []byte{1, 2}}
+
+	cp := DeepCopy(orig)
+	if &cp.data[0] != &orig.data[0] {
+		t.Error("unexported fields are copied shallowly by DeepCopy")
+	}
+
+	_, err := DeepCopyStrict(orig)
+	var unexported *UnexportedFieldError
+	if !errors.As(err, &unexported) || unexported.Path != "data" {
+		t.Errorf("DeepCopyStrict error = %v; want *UnexportedFieldError for data", err)
+	}
+
+	if _, err := DeepCopyStrict(Secret{Label: "empty"}); err != nil {
+		t.Errorf("a nil unexported field shares nothing, got %v", err)
+	}
+}
+
+func TestCloner(t *testing.T) {
+	count := 0
+	v := &Vault{Label: "v", items: []string{"a"}, copies: &count}
+	holder := struct {
+		A, B *Vault
+	}{v, v}
+
+	cp, err := DeepCopyStrict(holder)
+	if err != nil {
+		t.Fatalf("DeepCopyStrict with Cloner: %v", err)
+	}
+	cp.A.items[0] = "changed"
+
+	if v.items[0] != "a" {
+		t.Error("Cloner copy shares items with the original")
+	}
+	if cp.A != cp.B || count != 1 {
+		t.Errorf("shared Cloner pointer should be cloned once, got %d clones", count)
+	}
+}
@@ -0,0 +1,115 @@
+// Package examples holds the value-versus-pointer and new-versus-make
+// functions that the 05 program demonstrates. They do not print, so the
+// allocreport command can measure exactly the code main calls and show the
+// compiler's escape analysis for it; main prints the results itself.
+//
+// Each function is marked noinline so its escape decisions stay attached
+// to it instead of moving into whichever caller inlines it.
+package examples
+
+// Person struct for demonstrating pointers
+type Person struct {
+	Name string
+	Age  int
+}
+
+// ModifyValue changes its own copy of p and returns that copy; the
+// caller's Person is untouched
+//
+//go:noinline
+func ModifyValue(p Person) Person {
+	p.Name = "Modified"
+	p.Age = 99
+	return p
+}
+
+// ModifyPointer changes the caller's Person through the pointer
+//
+//go:noinline
+func ModifyPointer(p *Person) {
+	p.Name = "Modified"
+	p.Age = 99
+}
+
+// NewPerson returns a pointer to a local variable, which therefore moves
+// to the heap
+//
+//go:noinline
+func NewPerson(name string, age int) *Person {
+	p := Person{Name: name, Age: age}
+	return &p
+}
+
+// NewZeroPerson allocates a zeroed Person with new; like NewPerson's &p,
+// the pointer outlives the call, so the Person is on the heap
+//
+//go:noinline
+func NewZeroPerson() *Person {
+	return new(Person)
+}
+
+// MakeSlice builds a slice with make. The backing array is returned to the
+// caller, so it moves to the heap, and n is only known at run time.
+//
+//go:noinline
+func MakeSlice(n, capacity int) []int {
+	return make([]int, n, capacity)
+}
+
+// MakeMap builds a map with make, sized for hint entries
+//
+//go:noinline
+func MakeMap(hint int) map[string]int {
+	return make(map[string]int, hint)
+}
+
+// ModifySlice writes through the slice header to the caller's array
+//
+//go:noinline
+func ModifySlice(s []int) {
+	if len(s) > 0 {
+		s[0] = 999
+	}
+}
+
+// ModifyMap adds to the caller's map
+//
+//go:noinline
+func ModifyMap(m map[string]int) {
+	m["modified"] = 999
+}
+
+// Address is held by pointer in Employee
+type Address struct {
+	Street string
+	City   string
+}
+
+type Employee struct {
+	Name    string
+	Address *Address // Pointer to embedded struct
+}
+
+// SetAddress allocates the Address on first use
+//
+//go:noinline
+func (e *Employee) SetAddress(street, city string) {
+	if e.Address == nil {
+		e.Address = &Address{}
+	}
+	e.Address.Street = street
+	e.Address.City = city
+}
+
+// Speaker is satisfied by Dog
+type Speaker interface {
+	Speak() string
+}
+
+type Dog struct {
+	Name string
+}
+
+func (d Dog) Speak() string {
+	return "Woof!"
+}
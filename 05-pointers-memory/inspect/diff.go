@@ -0,0 +1,239 @@
+package inspect
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// missing stands in for a map entry or list element present on one side only
+const missing = "<missing>"
+
+// Change is one difference found by Diff
+type Change struct {
+	Path string // field path such as Address.City, Tags[2] or Scores["bob"]
+	Old  string // compact form of the value in a
+	New  string // compact form of the value in b
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Path, c.Old, c.New)
+}
+
+// Changes lists differences in the order the fields are declared
+type Changes []Change
+
+// String returns one change per line
+func (cs Changes) String() string {
+	lines := make([]string, len(cs))
+	for i, c := range cs {
+		lines[i] = c.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Diff compares a and b field by field, following pointers, and returns
+// every leaf that differs:
+//
+//	Address.City: "Boston" -> "NYC"
+//
+// Two pointers are equal when the values they point to are equal, so an
+// Employee and its deep copy have no changes. Nil and empty slices or maps
+// are treated as equal, and non-nil funcs are never reported since Go
+// cannot compare them. Cycles are followed only once.
+func Diff(a, b any) Changes {
+	d := differ{visited: make(map[visit]bool)}
+	d.diff("", reflect.ValueOf(a), reflect.ValueOf(b))
+	return d.changes
+}
+
+// visit marks a pair of pointers already being compared
+type visit struct {
+	a, b uintptr
+	typ  reflect.Type
+}
+
+type differ struct {
+	changes Changes
+	visited map[visit]bool
+}
+
+func (d *differ) add(path, old, new string) {
+	d.changes = append(d.changes, Change{Path: displayPath(path), Old: old, New: new})
+}
+
+func (d *differ) changed(path string, a, b reflect.Value) {
+	d.add(path, compactValue(a), compactValue(b))
+}
+
+// seen reports whether this pair of addresses was compared before, so
+// cyclic structures terminate
+func (d *differ) seen(a, b reflect.Value) bool {
+	key := visit{a.Pointer(), b.Pointer(), a.Type()}
+	if d.visited[key] {
+		return true
+	}
+	d.visited[key] = true
+	return false
+}
+
+func (d *differ) diff(path string, a, b reflect.Value) {
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			d.changed(path, a, b)
+		}
+		return
+	}
+	if a.Type() != b.Type() {
+		d.changed(path, a, b)
+		return
+	}
+
+	// time.Time holds a location pointer and monotonic reading that differ
+	// between equal instants, so compare it as a whole
+	if a.Type() == timeType && a.CanInterface() {
+		if !a.Interface().(time.Time).Equal(b.Interface().(time.Time)) {
+			d.changed(path, a, b)
+		}
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				d.changed(path, a, b)
+			}
+			return
+		}
+		if a.Pointer() == b.Pointer() || d.seen(a, b) {
+			return
+		}
+		d.diff(path, a.Elem(), b.Elem())
+
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				d.changed(path, a, b)
+			}
+			return
+		}
+		d.diff(path, a.Elem(), b.Elem())
+
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			d.diff(fieldPath(path, a.Type().Field(i).Name), a.Field(i), b.Field(i))
+		}
+
+	case reflect.Slice, reflect.Array:
+		if a.Kind() == reflect.Slice && a.Len() > 0 && a.Pointer() == b.Pointer() && a.Len() == b.Len() {
+			return
+		}
+		for i := 0; i < max(a.Len(), b.Len()); i++ {
+			elem := indexPath(path, i)
+			switch {
+			case i >= a.Len():
+				d.add(elem, missing, compactValue(b.Index(i)))
+			case i >= b.Len():
+				d.add(elem, compactValue(a.Index(i)), missing)
+			default:
+				d.diff(elem, a.Index(i), b.Index(i))
+			}
+		}
+
+	case reflect.Map:
+		if a.Len() == 0 && b.Len() == 0 {
+			return
+		}
+		if a.Pointer() == b.Pointer() || d.seen(a, b) {
+			return
+		}
+		d.diffMaps(path, a, b)
+
+	case reflect.Func:
+		if a.IsNil() != b.IsNil() {
+			d.changed(path, a, b)
+		}
+
+	case reflect.Chan, reflect.UnsafePointer:
+		if a.Pointer() != b.Pointer() {
+			d.changed(path, a, b)
+		}
+
+	default:
+		if !scalarEqual(a, b) {
+			d.changed(path, a, b)
+		}
+	}
+}
+
+func (d *differ) diffMaps(path string, a, b reflect.Value) {
+	// Merge the keys of both maps; sortedKeys needs a map, so build one
+	union := reflect.MakeMapWithSize(reflect.MapOf(a.Type().Key(), reflect.TypeFor[bool]()), a.Len())
+	for _, m := range []reflect.Value{a, b} {
+		iter := m.MapRange()
+		for iter.Next() {
+			union.SetMapIndex(iter.Key(), reflect.ValueOf(true))
+		}
+	}
+
+	for _, k := range sortedKeys(union) {
+		av, bv := a.MapIndex(k), b.MapIndex(k)
+		entry := keyPath(path, k)
+		switch {
+		case !av.IsValid():
+			d.add(entry, missing, compactValue(bv))
+		case !bv.IsValid():
+			d.add(entry, compactValue(av), missing)
+		default:
+			d.diff(entry, av, bv)
+		}
+	}
+}
+
+// scalarEqual compares basic kinds. Unlike ==, two NaNs count as equal,
+// since a diff reporting NaN -> NaN would only confuse.
+func scalarEqual(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		x, y := a.Float(), b.Float()
+		return x == y || (math.IsNaN(x) && math.IsNaN(y))
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex()
+	case reflect.String:
+		return a.String() == b.String()
+	}
+	return compactValue(a) == compactValue(b)
+}
+
+// TB is the part of testing.TB that Equal uses, so this package does not
+// have to import testing
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Equal reports a test failure listing every field where got differs from
+// want, and returns whether they matched:
+//
+//	if !inspect.Equal(t, got, want) { ... }
+//
+//	mismatch (want -> got):
+//	  Address.City: "Boston" -> "NYC"
+func Equal(t TB, got, want any) bool {
+	t.Helper()
+	changes := Diff(want, got)
+	if len(changes) == 0 {
+		return true
+	}
+	t.Errorf("mismatch (want -> got):\n  %s", strings.ReplaceAll(changes.String(), "\n", "\n  "))
+	return false
+}
@@ -0,0 +1,218 @@
+package inspect
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Address struct {
+	Street string
+	City   string
+}
+
+type Contact struct {
+	Email string
+}
+
+type Employee struct {
+	Name    string
+	Home    *Address
+	Work    *Address
+	Contact // embedded
+	Skills  []string
+	Scores  map[string]int
+	manager *Employee
+}
+
+type node struct {
+	Value int
+	Next  *node
+}
+
+func TestSprint(t *testing.T) {
+	addr := &Address{Street: "1 Main St", City: "Boston"}
+	e := Employee{
+		Name:    "Ann",
+		Home:    addr,
+		Work:    addr,
+		Contact: Contact{Email: "ann@example.com"},
+		Skills:  []string{"go", "sql"},
+		Scores:  map[string]int{"b": 2, "a": 1},
+	}
+
+	want := `inspect.Employee{
+  Name: "Ann",
+  Home: &inspect.Address{
+    Street: "1 Main St",
+    City: "Boston",
+  },
+  Work: <same as Home>,
+  Contact: inspect.Contact{
+    Email: "ann@example.com",
+  },
+  Skills: []string{"go", "sql"},
+  Scores: map[string]int{
+    "a": 1,
+    "b": 2,
+  },
+  manager: (*inspect.Employee)(nil),
+}`
+	if got := Sprint(e); got != want {
+		t.Errorf("Sprint =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSprintCycles(t *testing.T) {
+	a := &node{Value: 1}
+	b := &node{Value: 2, Next: a}
+	a.Next = b
+
+	want := "&inspect.node{Value: 1, Next: &inspect.node{Value: 2, Next: <cycle to (root)>}}"
+	if got := Compact(a); got != want {
+		t.Errorf("Compact(cycle) = %s; want %s", got, want)
+	}
+
+	// A manager cycle through an unexported field
+	boss := &Employee{Name: "Boss"}
+	boss.manager = boss
+	if got := Sprint(boss); !strings.Contains(got, "manager: <cycle to (root)>") {
+		t.Errorf("Sprint(self-managed) =\n%s\nwant a cycle marker", got)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	testCases := []struct {
+		value any
+		want  string
+	}{
+		{nil, "nil"},
+		{42, "42"},
+		{"hi", `"hi"`},
+		{1.5, "1.5"},
+		{time.Second, "1s"},
+		{[]int(nil), "[]int(nil)"},
+		{[]int{}, "[]int{}"},
+		{[2]bool{true, false}, "[2]bool{true, false}"},
+		{map[int]string{2: "b", 10: "j", 1: "a"}, `map[int]string{1: "a", 2: "b", 10: "j"}`},
+		{&Address{City: "NYC"}, `&inspect.Address{Street: "", City: "NYC"}`},
+		{struct{}{}, "struct {}{}"},
+		{[]any{1, "x", nil}, `[]interface {}{1, "x", nil}`},
+		{(func())(nil), "(func())(nil)"},
+	}
+
+	for _, tc := range testCases {
+		if got := Compact(tc.value); got != tc.want {
+			t.Errorf("Compact(%#v) = %s; want %s", tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	base := func() Employee {
+		return Employee{
+			Name:    "Ann",
+			Home:    &Address{Street: "1 Main St", City: "Boston"},
+			Contact: Contact{Email: "ann@example.com"},
+			Skills:  []string{"go", "sql"},
+			Scores:  map[string]int{"q1": 90, "q2": 85},
+		}
+	}
+
+	a, b := base(), base()
+	if changes := Diff(a, b); len(changes) != 0 {
+		t.Errorf("Diff(equal copies) = %v; want none", changes)
+	}
+
+	b.Home.City = "NYC"
+	b.Work = &Address{City: "Cambridge"}
+	b.Email = "ann@corp.example"
+	b.Skills = append(b.Skills, "k8s")
+	b.Scores["q2"] = 88
+	delete(b.Scores, "q1")
+	b.Scores["q3"] = 70
+
+	want := []string{
+		`Home.City: "Boston" -> "NYC"`,
+		`Work: (*inspect.Address)(nil) -> &inspect.Address{Street: "", City: "Cambridge"}`,
+		`Contact.Email: "ann@example.com" -> "ann@corp.example"`,
+		`Skills[2]: <missing> -> "k8s"`,
+		`Scores["q1"]: 90 -> <missing>`,
+		`Scores["q2"]: 85 -> 88`,
+		`Scores["q3"]: <missing> -> 70`,
+	}
+	if got := Diff(a, b).String(); got != strings.Join(want, "\n") {
+		t.Errorf("Diff =\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+}
+
+func TestDiffEdgeCases(t *testing.T) {
+	testCases := []struct {
+		name string
+		a, b any
+		want string
+	}{
+		{"equal scalars", 1, 1, ""},
+		{"root scalar", 1, 2, "(root): 1 -> 2"},
+		{"type change", 1, "1", `(root): 1 -> "1"`},
+		{"nil vs value", nil, 3, "(root): nil -> 3"},
+		{"nil vs empty slice", []int(nil), []int{}, ""},
+		{"nil vs empty map", map[string]int(nil), map[string]int{}, ""},
+		{"NaN", math.NaN(), math.NaN(), ""},
+		{"interface elems", []any{1, "a"}, []any{1, 2.5}, `[1]: "a" -> 2.5`},
+		{"same instant", time.Unix(0, 0), time.Unix(0, 0).UTC(), ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Diff(tc.a, tc.b).String(); got != tc.want {
+				t.Errorf("Diff = %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDiffCycles(t *testing.T) {
+	build := func(last int) *node {
+		a := &node{Value: 1}
+		b := &node{Value: last, Next: a}
+		a.Next = b
+		return a
+	}
+
+	if changes := Diff(build(2), build(2)); len(changes) != 0 {
+		t.Errorf("Diff(equal cycles) = %v; want none", changes)
+	}
+	if got := Diff(build(2), build(3)).String(); got != "Next.Value: 2 -> 3" {
+		t.Errorf("Diff(cycles) = %q", got)
+	}
+}
+
+// recorder captures Equal's failure message
+type recorder struct {
+	msgs []string
+}
+
+func (r *recorder) Helper() {}
+func (r *recorder) Errorf(format string, args ...any) {
+	r.msgs = append(r.msgs, fmt.Sprintf(format, args...))
+}
+
+func TestEqual(t *testing.T) {
+	var r recorder
+	want := Address{Street: "1 Main St", City: "Boston"}
+	got := Address{Street: "1 Main St", City: "NYC"}
+
+	if !Equal(&r, want, want) || len(r.msgs) != 0 {
+		t.Errorf("Equal(same) failed: %v", r.msgs)
+	}
+	if Equal(&r, got, want) {
+		t.Error("Equal(different) = true")
+	}
+	wantMsg := "mismatch (want -> got):\n  City: \"Boston\" -> \"NYC\""
+	if len(r.msgs) != 1 || r.msgs[0] != wantMsg {
+		t.Errorf("Equal message = %q; want %q", r.msgs, wantMsg)
+	}
+}
@@ -0,0 +1,356 @@
+// Package inspect pretty-prints arbitrary values as indented trees and
+// compares two values field by field. Unlike %+v it follows pointers,
+// so an *Address prints as its contents rather than as 0xc000010030.
+//
+// Pointers and maps that were already printed are not expanded again:
+// a back-reference into the value being printed shows as <cycle to PATH>
+// and a second reference to the same data shows as <same as PATH>.
+package inspect
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const indent = "  "
+
+// rootPath names the top-level value in cycle markers and diffs
+const rootPath = "(root)"
+
+// Sprint formats v as an indented tree:
+//
+//	main.Employee{
+//	  Name: "David",
+//	  Address: &main.Address{
+//	    Street: "123 Main St",
+//	    City: "Anytown",
+//	  },
+//	}
+func Sprint(v any) string {
+	p := newPrinter(false)
+	p.value(reflect.ValueOf(v), "", 0)
+	return p.buf.String()
+}
+
+// Fprint writes the Sprint form of v followed by a newline
+func Fprint(w io.Writer, v any) error {
+	_, err := io.WriteString(w, Sprint(v)+"\n")
+	return err
+}
+
+// Compact formats v on a single line, with the same pointer handling as Sprint
+func Compact(v any) string {
+	p := newPrinter(true)
+	p.value(reflect.ValueOf(v), "", 0)
+	return p.buf.String()
+}
+
+type printer struct {
+	buf     strings.Builder
+	compact bool
+	seen    map[ref]string // first path each pointer or map was printed at
+	active  map[ref]bool   // pointers and maps currently being printed
+}
+
+// ref identifies shared data. The type is part of the key because a struct
+// and its first field live at the same address.
+type ref struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+func newPrinter(compact bool) *printer {
+	return &printer{
+		compact: compact,
+		seen:    make(map[ref]string),
+		active:  make(map[ref]bool),
+	}
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return rootPath
+	}
+	return path
+}
+
+func fieldPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func indexPath(prefix string, i int) string {
+	return prefix + "[" + strconv.Itoa(i) + "]"
+}
+
+func keyPath(prefix string, key reflect.Value) string {
+	return prefix + "[" + compactValue(key) + "]"
+}
+
+// compactValue is Compact for a reflect.Value, which also works on values
+// read from unexported fields
+func compactValue(v reflect.Value) string {
+	p := newPrinter(true)
+	p.value(v, "", 0)
+	return p.buf.String()
+}
+
+// item starts the i-th element of a composite at the given depth: on its
+// own indented line, or after a comma in compact mode
+func (p *printer) item(i, depth int) {
+	if p.compact {
+		if i > 0 {
+			p.buf.WriteString(", ")
+		}
+		return
+	}
+	p.buf.WriteByte('\n')
+	p.buf.WriteString(strings.Repeat(indent, depth+1))
+}
+
+// endItem terminates an element; multi-line output keeps trailing commas
+func (p *printer) endItem() {
+	if !p.compact {
+		p.buf.WriteByte(',')
+	}
+}
+
+func (p *printer) closing(depth int) {
+	if !p.compact {
+		p.buf.WriteByte('\n')
+		p.buf.WriteString(strings.Repeat(indent, depth))
+	}
+	p.buf.WriteByte('}')
+}
+
+// enter records a pointer or map being printed at path. It returns false,
+// after writing a marker, if the same data has been printed before.
+func (p *printer) enter(r ref, path string) bool {
+	// Distinct zero-size values may share an address
+	if r.typ.Kind() == reflect.Pointer && r.typ.Elem().Size() == 0 {
+		return true
+	}
+	if first, ok := p.seen[r]; ok {
+		if p.active[r] {
+			fmt.Fprintf(&p.buf, "<cycle to %s>", displayPath(first))
+		} else {
+			fmt.Fprintf(&p.buf, "<same as %s>", displayPath(first))
+		}
+		return false
+	}
+	p.seen[r] = path
+	p.active[r] = true
+	return true
+}
+
+func (p *printer) value(v reflect.Value, path string, depth int) {
+	if !v.IsValid() {
+		p.buf.WriteString("nil")
+		return
+	}
+	if s, ok := stringer(v); ok {
+		p.buf.WriteString(s)
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			fmt.Fprintf(&p.buf, "(%s)(nil)", v.Type())
+			return
+		}
+		r := ref{v.Pointer(), v.Type()}
+		if !p.enter(r, path) {
+			return
+		}
+		p.buf.WriteByte('&')
+		p.value(v.Elem(), path, depth)
+		p.active[r] = false
+
+	case reflect.Interface:
+		if v.IsNil() {
+			p.buf.WriteString("nil")
+			return
+		}
+		p.value(v.Elem(), path, depth)
+
+	case reflect.Struct:
+		p.structValue(v, path, depth)
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			fmt.Fprintf(&p.buf, "%s(nil)", v.Type())
+			return
+		}
+		p.list(v, path, depth)
+
+	case reflect.Map:
+		if v.IsNil() {
+			fmt.Fprintf(&p.buf, "%s(nil)", v.Type())
+			return
+		}
+		r := ref{v.Pointer(), v.Type()}
+		if !p.enter(r, path) {
+			return
+		}
+		p.mapValue(v, path, depth)
+		p.active[r] = false
+
+	default:
+		p.buf.WriteString(scalar(v))
+	}
+}
+
+func (p *printer) structValue(v reflect.Value, path string, depth int) {
+	t := v.Type()
+	fmt.Fprintf(&p.buf, "%s{", t)
+	if t.NumField() == 0 {
+		p.buf.WriteByte('}')
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		p.item(i, depth)
+		fmt.Fprintf(&p.buf, "%s: ", f.Name)
+		p.value(v.Field(i), fieldPath(path, f.Name), depth+1)
+		p.endItem()
+	}
+	p.closing(depth)
+}
+
+func (p *printer) list(v reflect.Value, path string, depth int) {
+	fmt.Fprintf(&p.buf, "%s{", v.Type())
+	if v.Len() == 0 {
+		p.buf.WriteByte('}')
+		return
+	}
+
+	// Lists of plain values read better on one line: []int{1, 2, 3}
+	if isScalar(v.Type().Elem()) {
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				p.buf.WriteString(", ")
+			}
+			p.value(v.Index(i), indexPath(path, i), depth)
+		}
+		p.buf.WriteByte('}')
+		return
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		p.item(i, depth)
+		p.value(v.Index(i), indexPath(path, i), depth+1)
+		p.endItem()
+	}
+	p.closing(depth)
+}
+
+func (p *printer) mapValue(v reflect.Value, path string, depth int) {
+	fmt.Fprintf(&p.buf, "%s{", v.Type())
+	keys := sortedKeys(v)
+	if len(keys) == 0 {
+		p.buf.WriteByte('}')
+		return
+	}
+	for i, k := range keys {
+		p.item(i, depth)
+		p.buf.WriteString(compactValue(k))
+		p.buf.WriteString(": ")
+		p.value(v.MapIndex(k), keyPath(path, k), depth+1)
+		p.endItem()
+	}
+	p.closing(depth)
+}
+
+// sortedKeys orders map keys by their printed form so output is stable
+func sortedKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	slices.SortFunc(keys, func(a, b reflect.Value) int {
+		if a.CanInt() && b.CanInt() {
+			return cmp.Compare(a.Int(), b.Int())
+		}
+		if a.CanUint() && b.CanUint() {
+			return cmp.Compare(a.Uint(), b.Uint())
+		}
+		if a.CanFloat() && b.CanFloat() {
+			return cmp.Compare(a.Float(), b.Float())
+		}
+		return strings.Compare(compactValue(a), compactValue(b))
+	})
+	return keys
+}
+
+func isScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	}
+	return false
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// stringer uses String for time.Time and for named scalars such as
+// time.Duration or enums, where the raw number means little. Structs with
+// a String method are still expanded so their fields can be seen.
+func stringer(v reflect.Value) (string, bool) {
+	if !v.CanInterface() {
+		return "", false
+	}
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).String(), true
+	}
+	if !isScalar(v.Type()) {
+		return "", false
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String(), true
+	}
+	return "", false
+}
+
+// scalar formats values that have no inner structure. It works on
+// unexported fields, which cannot be converted back to interfaces.
+func scalar(v reflect.Value) string {
+	if s, ok := stringer(v); ok {
+		return s
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case reflect.Complex64, reflect.Complex128:
+		return fmt.Sprint(v.Complex())
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if v.IsNil() {
+			return fmt.Sprintf("(%s)(nil)", v.Type())
+		}
+		return fmt.Sprintf("<%s>", v.Type())
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return "nil"
+		}
+		return scalar(v.Elem())
+	}
+	return fmt.Sprintf("<%s>", v.Type())
+}
@@ -1,46 +1,23 @@
 package main
 
-import "fmt"
-
-// Person struct for demonstrating pointers
-type Person struct {
-	Name string
-	Age  int
-}
-
-// Function that takes a value (copy)
-func modifyValue(p Person) {
-	p.Name = "Modified"
-	p.Age = 99
-	fmt.Printf("Inside modifyValue: %+v\n", p)
-}
-
-// Function that takes a pointer
-func modifyPointer(p *Person) {
-	p.Name = "Modified"
-	p.Age = 99
-	fmt.Printf("Inside modifyPointer: %+v\n", p)
-}
-
-// Function that returns a pointer
-func newPerson(name string, age int) *Person {
-	p := Person{Name: name, Age: age}
-	return &p // Return pointer to local variable
-}
-
-// Slice demonstration
-func modifySlice(s []int) {
-	if len(s) > 0 {
-		s[0] = 999
-	}
-	fmt.Printf("Inside modifySlice: %v\n", s)
-}
-
-// Map demonstration
-func modifyMap(m map[string]int) {
-	m["modified"] = 999
-	fmt.Printf("Inside modifyMap: %v\n", m)
-}
+import (
+	"fmt"
+
+	"grok-study-plan/05-pointers-memory/deepcopy"
+	"grok-study-plan/05-pointers-memory/examples"
+	"grok-study-plan/05-pointers-memory/inspect"
+	"grok-study-plan/05-pointers-memory/pool"
+)
+
+// The types and the value-versus-pointer functions live in the examples
+// package, which the allocreport command measures
+type (
+	Person   = examples.Person
+	Address  = examples.Address
+	Employee = examples.Employee
+	Speaker  = examples.Speaker
+	Dog      = examples.Dog
+)
 
 // Demonstrating pointer arithmetic (not allowed in Go)
 func demonstrateNoPointerArithmetic() {
@@ -73,7 +50,7 @@ func doublePointer(pp **Person) {
 // Memory allocation with new()
 func demonstrateNew() {
 	// new() allocates zeroed memory and returns pointer
-	p := new(Person)
+	p := examples.NewZeroPerson()
 	fmt.Printf("new(Person): %+v\n", *p)
 
 	// Equivalent to:
@@ -84,7 +61,7 @@ func demonstrateNew() {
 // Memory allocation with make() vs new()
 func demonstrateMakeVsNew() {
 	// make() is for slices, maps, channels
-	slice := make([]int, 3, 10)
+	slice := examples.MakeSlice(3, 10)
 	fmt.Printf("make([]int, 3, 10): %v, len=%d, cap=%d\n", slice, len(slice), cap(slice))
 
 	// new() is for any type
@@ -92,28 +69,12 @@ func demonstrateMakeVsNew() {
 	fmt.Printf("new([]int): %v\n", *slicePtr) // nil slice
 
 	// To initialize:
-	*slicePtr = make([]int, 3)
+	*slicePtr = examples.MakeSlice(3, 3)
 	fmt.Printf("After make: %v\n", *slicePtr)
-}
 
-// Struct embedding and pointers
-type Address struct {
-	Street string
-	City   string
-}
-
-type Employee struct {
-	Name    string
-	Address *Address // Pointer to embedded struct
-}
-
-// Method with pointer receiver
-func (e *Employee) SetAddress(street, city string) {
-	if e.Address == nil {
-		e.Address = &Address{}
-	}
-	e.Address.Street = street
-	e.Address.City = city
+	m := examples.MakeMap(2)
+	m["a"] = 1
+	fmt.Printf("make(map[string]int, 2): %v, len=%d\n", m, len(m))
 }
 
 // Demonstrating reference types
@@ -155,19 +116,6 @@ func valueTypes() {
 	fmt.Printf("y: %d\n", y)
 }
 
-// Pointer to interface
-type Speaker interface {
-	Speak() string
-}
-
-type Dog struct {
-	Name string
-}
-
-func (d Dog) Speak() string {
-	return "Woof!"
-}
-
 func makeSpeak(s Speaker) {
 	fmt.Printf("%s says: %s\n", s.(Dog).Name, s.Speak())
 }
@@ -177,29 +125,30 @@ func main() {
 	person := Person{Name: "Alice", Age: 25}
 	fmt.Printf("Original person: %+v\n", person)
 
-	// Pass by value
-	modifyValue(person)
-	fmt.Printf("After modifyValue: %+v\n", person)
+	// Pass by value: ModifyValue changes and returns its own copy
+	modified := examples.ModifyValue(person)
+	fmt.Printf("ModifyValue's copy: %+v\n", modified)
+	fmt.Printf("After ModifyValue: %+v\n", person)
 
 	// Pass by reference
-	modifyPointer(&person)
-	fmt.Printf("After modifyPointer: %+v\n", person)
+	examples.ModifyPointer(&person)
+	fmt.Printf("After ModifyPointer: %+v\n", person)
 
 	// Returning pointers
-	newP := newPerson("Bob", 30)
+	newP := examples.NewPerson("Bob", 30)
 	fmt.Printf("New person: %+v\n", *newP)
 
 	// Slice modification (slices are reference types)
 	numbers := []int{1, 2, 3, 4, 5}
 	fmt.Printf("Original slice: %v\n", numbers)
-	modifySlice(numbers)
-	fmt.Printf("After modifySlice: %v\n", numbers)
+	examples.ModifySlice(numbers)
+	fmt.Printf("After ModifySlice: %v\n", numbers)
 
 	// Map modification (maps are reference types)
 	scores := map[string]int{"alice": 100, "bob": 95}
 	fmt.Printf("Original map: %v\n", scores)
-	modifyMap(scores)
-	fmt.Printf("After modifyMap: %v\n", scores)
+	examples.ModifyMap(scores)
+	fmt.Printf("After ModifyMap: %v\n", scores)
 
 	// Nil pointer handling
 	var nilPerson *Person
@@ -225,6 +174,46 @@ func main() {
 	fmt.Printf("Employee: %+v\n", emp)
 	fmt.Printf("Address: %+v\n", *emp.Address)
 
+	// %+v shows emp.Address as a hex address; inspect follows the pointer
+	fmt.Println("inspect.Sprint(emp):")
+	fmt.Println(inspect.Sprint(emp))
+
+	// A field-by-field diff after moving a copy to a new address
+	moved := emp
+	moved.Name = "Erin"
+	moved.Address = &Address{Street: emp.Address.Street, City: "NYC"}
+	fmt.Println("inspect.Diff(emp, moved):")
+	fmt.Println(inspect.Diff(emp, moved))
+
+	// Assignment copies the pointer, so both copies share one Address
+	shallow := emp
+	shallow.Address.City = "Shallowville"
+	fmt.Println("emp city after changing shallow copy:", emp.Address.City)
+
+	// DeepCopy follows the pointer and copies the Address too
+	deep := deepcopy.DeepCopy(emp)
+	deep.Address.City = "Deepville"
+	fmt.Println("emp city after changing deep copy:", emp.Address.City)
+
+	// Shared pointers stay shared within the copy
+	office := &Address{Street: "1 Office Park", City: "Anytown"}
+	team := []Employee{{Name: "Eve", Address: office}, {Name: "Frank", Address: office}}
+	teamCopy := deepcopy.DeepCopy(team)
+	fmt.Println("copies share one Address:", teamCopy[0].Address == teamCopy[1].Address)
+	fmt.Println("copy shares the original's Address:", teamCopy[0].Address == office)
+
+	// NewPerson allocates every call; a pool reuses Persons that were returned
+	people := pool.New(pool.Options[Person]{
+		Reset: func(p *Person) { *p = Person{} },
+	})
+	for i, name := range []string{"Gina", "Hank", "Iris"} {
+		p := people.Get()
+		p.Name, p.Age = name, 20+i
+		fmt.Printf("Pooled person: %+v\n", *p)
+		people.Put(p)
+	}
+	fmt.Println("Pool stats:", people.Stats())
+
 	// Reference vs value types
 	fmt.Println("\n--- Reference Types ---")
 	referenceTypes()
@@ -0,0 +1,218 @@
+// Package pool wraps sync.Pool in a typed API that resets objects when they
+// are returned, counts how often the pool is actually hit, and can report
+// objects that were never returned.
+//
+//	people := pool.New(pool.Options[Person]{
+//	    Reset: func(p *Person) { *p = Person{} },
+//	})
+//	p := people.Get()
+//	defer people.Put(p)
+package pool
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"weak"
+)
+
+// Options configures a Pool. Every field is optional.
+type Options[T any] struct {
+	// New creates an object when the pool is empty; the default is new(T)
+	New func() *T
+
+	// Reset clears an object as it is returned, so the next Get sees no
+	// leftover state. Reset may keep allocations (such as a slice's backing
+	// array or a pointed-to struct) for reuse.
+	Reset func(*T)
+
+	// DetectLeaks tracks every object handed out by Get and reports the ones
+	// that are garbage collected without having been returned with Put. It
+	// costs an extra allocation and a stack lookup per Get, so it is meant
+	// for tests and debugging.
+	DetectLeaks bool
+
+	// OnLeak is called for each leaked object when DetectLeaks is set. It
+	// runs on the runtime's cleanup goroutine and must not block.
+	OnLeak func(Leak)
+}
+
+// Leak describes an object that was garbage collected while checked out
+type Leak struct {
+	Type string // the pooled type, such as main.Person
+	Site string // file:line of the Get call that took the object
+}
+
+func (l Leak) String() string {
+	return fmt.Sprintf("%s from %s was never returned to the pool", l.Type, l.Site)
+}
+
+// Stats is a snapshot of a pool's counters
+type Stats struct {
+	Gets   uint64 // calls to Get
+	Puts   uint64 // calls to Put with a non-nil object
+	Hits   uint64 // Gets served by a pooled object
+	Misses uint64 // Gets that found the pool empty
+	News   uint64 // objects created, by misses or Prefill
+	Leaks  uint64 // objects collected without being Put (DetectLeaks only)
+}
+
+// Outstanding is the number of objects currently checked out
+func (s Stats) Outstanding() int64 {
+	return int64(s.Gets) - int64(s.Puts)
+}
+
+// HitRate is the fraction of Gets served without allocating
+func (s Stats) HitRate() float64 {
+	if s.Gets == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Gets)
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("gets=%d puts=%d hits=%d misses=%d news=%d leaks=%d hit-rate=%.0f%%",
+		s.Gets, s.Puts, s.Hits, s.Misses, s.News, s.Leaks, 100*s.HitRate())
+}
+
+// Pool is a typed, instrumented sync.Pool. It is safe for concurrent use.
+// Like sync.Pool, it may drop idle objects at any garbage collection.
+type Pool[T any] struct {
+	pool   sync.Pool
+	newFn  func() *T
+	reset  func(*T)
+	onLeak func(Leak)
+	leaks  *leakTracker[T] // nil unless DetectLeaks is set
+
+	gets, puts, hits, misses, news, leaked atomic.Uint64
+}
+
+// New creates a pool configured by opts
+func New[T any](opts Options[T]) *Pool[T] {
+	p := &Pool[T]{
+		newFn:  opts.New,
+		reset:  opts.Reset,
+		onLeak: opts.OnLeak,
+	}
+	if p.newFn == nil {
+		p.newFn = func() *T { return new(T) }
+	}
+	if opts.DetectLeaks {
+		p.leaks = &leakTracker[T]{
+			typeName: fmt.Sprintf("%T", *new(T)),
+			out:      make(map[weak.Pointer[T]]runtime.Cleanup),
+		}
+	}
+	return p
+}
+
+func (p *Pool[T]) create() *T {
+	p.news.Add(1)
+	return p.newFn()
+}
+
+// Get returns a pooled object, or a new one if the pool is empty
+func (p *Pool[T]) Get() *T {
+	p.gets.Add(1)
+
+	x, _ := p.pool.Get().(*T)
+	if x != nil {
+		p.hits.Add(1)
+	} else {
+		p.misses.Add(1)
+		x = p.create()
+	}
+
+	if p.leaks != nil {
+		_, file, line, _ := runtime.Caller(1)
+		p.leaks.track(p, x, fmt.Sprintf("%s:%d", file, line))
+	}
+	return x
+}
+
+// Put resets x and returns it to the pool. x must not be used afterwards.
+// Put(nil) is a no-op.
+func (p *Pool[T]) Put(x *T) {
+	if x == nil {
+		return
+	}
+	p.puts.Add(1)
+	if p.leaks != nil {
+		p.leaks.untrack(x)
+	}
+	if p.reset != nil {
+		p.reset(x)
+	}
+	p.pool.Put(x)
+}
+
+// Prefill adds n new objects to the pool ahead of a burst of Gets
+func (p *Pool[T]) Prefill(n int) {
+	for range n {
+		p.pool.Put(p.create())
+	}
+}
+
+// Stats returns the current counters
+func (p *Pool[T]) Stats() Stats {
+	return Stats{
+		Gets:   p.gets.Load(),
+		Puts:   p.puts.Load(),
+		Hits:   p.hits.Load(),
+		Misses: p.misses.Load(),
+		News:   p.news.Load(),
+		Leaks:  p.leaked.Load(),
+	}
+}
+
+// leakTracker attaches a cleanup to every checked-out object. Put cancels
+// it; if the garbage collector frees the object first, the cleanup runs and
+// the object is reported as leaked. Objects are keyed by weak pointers so
+// the tracker itself does not keep them alive.
+type leakTracker[T any] struct {
+	typeName string
+	mu       sync.Mutex
+	out      map[weak.Pointer[T]]runtime.Cleanup
+}
+
+// leakInfo is passed to the cleanup; it must not refer to the object itself
+type leakInfo[T any] struct {
+	pool *Pool[T]
+	key  weak.Pointer[T]
+	site string
+}
+
+func (lt *leakTracker[T]) track(p *Pool[T], x *T, site string) {
+	key := weak.Make(x)
+	cleanup := runtime.AddCleanup(x, reportLeak[T], leakInfo[T]{pool: p, key: key, site: site})
+
+	lt.mu.Lock()
+	lt.out[key] = cleanup
+	lt.mu.Unlock()
+}
+
+func (lt *leakTracker[T]) untrack(x *T) {
+	key := weak.Make(x)
+
+	lt.mu.Lock()
+	cleanup, ok := lt.out[key]
+	delete(lt.out, key)
+	lt.mu.Unlock()
+
+	if ok {
+		cleanup.Stop()
+	}
+}
+
+func reportLeak[T any](info leakInfo[T]) {
+	lt := info.pool.leaks
+	lt.mu.Lock()
+	delete(lt.out, info.key)
+	lt.mu.Unlock()
+
+	info.pool.leaked.Add(1)
+	if info.pool.onLeak != nil {
+		info.pool.onLeak(Leak{Type: lt.typeName, Site: info.site})
+	}
+}
@@ -0,0 +1,261 @@
+package pool
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type address struct {
+	Street string
+	City   string
+}
+
+type person struct {
+	Name string
+	Age  int
+}
+
+type employee struct {
+	Name    string
+	Address *address
+	Skills  []string
+}
+
+func resetEmployee(e *employee) {
+	e.Name = ""
+	if e.Address != nil {
+		*e.Address = address{} // keep the allocation for the next user
+	}
+	e.Skills = e.Skills[:0]
+}
+
+func TestGetPutStats(t *testing.T) {
+	p := New(Options[person]{})
+
+	a := p.Get()
+	a.Name = "Alice"
+	p.Put(a)
+	p.Put(nil) // ignored
+	b := p.Get()
+	p.Put(b)
+
+	s := p.Stats()
+	if s.Gets != 2 || s.Puts != 2 {
+		t.Errorf("gets=%d puts=%d; want 2 and 2", s.Gets, s.Puts)
+	}
+	if s.Hits+s.Misses != s.Gets {
+		t.Errorf("hits (%d) + misses (%d) != gets (%d)", s.Hits, s.Misses, s.Gets)
+	}
+	if s.News != s.Misses {
+		t.Errorf("news = %d; want one per miss (%d)", s.News, s.Misses)
+	}
+	if s.Outstanding() != 0 {
+		t.Errorf("Outstanding = %d; want 0", s.Outstanding())
+	}
+}
+
+func TestResetHook(t *testing.T) {
+	p := New(Options[employee]{
+		New:   func() *employee { return &employee{Address: &address{}} },
+		Reset: resetEmployee,
+	})
+
+	e := p.Get()
+	e.Name = "Bob"
+	e.Address.City = "Boston"
+	e.Skills = append(e.Skills, "go")
+	addr := e.Address
+	p.Put(e)
+
+	if e.Name != "" || e.Address != addr || e.Address.City != "" || len(e.Skills) != 0 {
+		t.Errorf("Reset left %+v; want cleared fields with the Address kept", e)
+	}
+}
+
+func TestPrefill(t *testing.T) {
+	p := New(Options[person]{})
+	p.Prefill(3)
+
+	s := p.Stats()
+	if s.News != 3 || s.Misses != 0 || s.Gets != 0 {
+		t.Errorf("after Prefill(3): %v", s)
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	p := New(Options[person]{Reset: func(x *person) { *x = person{} }})
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			for i := range 1000 {
+				x := p.Get()
+				if x.Name != "" {
+					t.Errorf("Get returned a dirty object: %+v", x)
+				}
+				x.Name, x.Age = "worker", i
+				p.Put(x)
+			}
+		})
+	}
+	wg.Wait()
+
+	s := p.Stats()
+	if s.Gets != 8000 || s.Puts != 8000 || s.Hits+s.Misses != s.Gets {
+		t.Errorf("stats after concurrent use: %v", s)
+	}
+}
+
+// collectUntil runs the garbage collector until cond holds or time runs out
+func collectUntil(cond func() bool) bool {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		runtime.GC()
+		if cond() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestLeakDetection(t *testing.T) {
+	var mu sync.Mutex
+	var leaks []Leak
+	p := New(Options[person]{
+		DetectLeaks: true,
+		OnLeak: func(l Leak) {
+			mu.Lock()
+			leaks = append(leaks, l)
+			mu.Unlock()
+		},
+	})
+
+	func() {
+		returned := p.Get()
+		p.Put(returned)
+		_ = p.Get() // dropped without Put
+	}()
+
+	if !collectUntil(func() bool { return p.Stats().Leaks >= 1 }) {
+		t.Fatal("leaked object was never reported")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(leaks) != 1 {
+		t.Fatalf("got %d leaks; want 1: %v", len(leaks), leaks)
+	}
+	if leaks[0].Type != "pool.person" || !strings.Contains(leaks[0].Site, "pool_test.go:") {
+		t.Errorf("leak = %+v; want pool.person from pool_test.go", leaks[0])
+	}
+}
+
+func TestNoLeakWhenReturned(t *testing.T) {
+	p := New(Options[person]{DetectLeaks: true})
+	for range 100 {
+		p.Put(p.Get())
+	}
+
+	// Give any (wrongly) pending cleanups a chance to run
+	for range 5 {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := p.Stats().Leaks; n != 0 {
+		t.Errorf("Leaks = %d; want 0 when every object is returned", n)
+	}
+}
+
+// newPerson mirrors 05's newPerson; noinline makes its result escape
+//
+//go:noinline
+func newPerson(name string, age int) *person {
+	return &person{Name: name, Age: age}
+}
+
+//go:noinline
+func newEmployee(name string) *employee {
+	return &employee{Name: name, Address: &address{City: "Anytown"}, Skills: make([]string, 0, 4)}
+}
+
+func BenchmarkPersonUnpooled(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			p := newPerson("Alice", 30)
+			p.Age++
+		}
+	})
+}
+
+func BenchmarkPersonPooled(b *testing.B) {
+	people := New(Options[person]{Reset: func(p *person) { *p = person{} }})
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			p := people.Get()
+			p.Name, p.Age = "Alice", 31
+			people.Put(p)
+		}
+	})
+	b.ReportMetric(people.Stats().HitRate()*100, "hit%")
+}
+
+// BenchmarkPersonSyncPool is the uninstrumented baseline for the overhead
+// of the counters
+func BenchmarkPersonSyncPool(b *testing.B) {
+	people := sync.Pool{New: func() any { return new(person) }}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			p := people.Get().(*person)
+			p.Name, p.Age = "Alice", 31
+			*p = person{}
+			people.Put(p)
+		}
+	})
+}
+
+func BenchmarkEmployeeUnpooled(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			e := newEmployee("Bob")
+			e.Skills = append(e.Skills, "go", "sql")
+		}
+	})
+}
+
+func BenchmarkEmployeePooled(b *testing.B) {
+	employees := New(Options[employee]{
+		New:   func() *employee { return newEmployee("") },
+		Reset: resetEmployee,
+	})
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			e := employees.Get()
+			e.Name, e.Address.City = "Bob", "Anytown"
+			e.Skills = append(e.Skills, "go", "sql")
+			employees.Put(e)
+		}
+	})
+	b.ReportMetric(employees.Stats().HitRate()*100, "hit%")
+}
+
+func BenchmarkPersonPooledLeakDetection(b *testing.B) {
+	people := New(Options[person]{DetectLeaks: true})
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			people.Put(people.Get())
+		}
+	})
+}
@@ -0,0 +1,449 @@
+// Package breaker stops calling an operation that keeps failing, giving the
+// thing behind it time to recover instead of piling more load on it.
+//
+// A Breaker starts closed and lets every call through, counting outcomes
+// over a rolling window. When too many fail it opens and rejects calls with
+// ErrCircuitOpen. After a cool-down it turns half-open and lets a few probe
+// calls through: if they all succeed it closes again, and if any fails it
+// reopens for another cool-down.
+//
+//	b := breaker.New(breaker.Settings{Name: "users-api"})
+//	err := b.Do(func() error { return fetchURL(url) })
+//	if errors.Is(err, breaker.ErrCircuitOpen) {
+//	    // fail fast, serve a cached value, ...
+//	}
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// State is the position of a breaker
+type State int
+
+const (
+	Closed State = iota
+	Open
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// ErrCircuitOpen is matched, with errors.Is, by every rejection
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// OpenError is returned instead of calling the operation. RetryAfter is
+// the time left in the cool-down, or 0 when half-open probes are busy.
+type OpenError struct {
+	Name       string
+	State      State
+	RetryAfter time.Duration
+}
+
+func (e *OpenError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("%v (%v)", ErrCircuitOpen, e.State)
+	}
+	return fmt.Sprintf("%v: %s (%v)", ErrCircuitOpen, e.Name, e.State)
+}
+
+func (e *OpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// Counts are call outcomes
+type Counts struct {
+	Requests  int
+	Successes int
+	Failures  int
+}
+
+// FailureRatio is Failures/Requests, 0 when there were no requests
+func (c Counts) FailureRatio() float64 {
+	if c.Requests == 0 {
+		return 0
+	}
+	return float64(c.Failures) / float64(c.Requests)
+}
+
+func (c *Counts) add(o Counts) {
+	c.Requests += o.Requests
+	c.Successes += o.Successes
+	c.Failures += o.Failures
+}
+
+// Defaults for zero Settings fields
+const (
+	DefaultWindow         = 10 * time.Second
+	DefaultBuckets        = 10
+	DefaultMinRequests    = 5
+	DefaultFailureRatio   = 0.5
+	DefaultCoolDown       = 5 * time.Second
+	DefaultHalfOpenProbes = 1
+	DefaultProbeTimeout   = 10 * time.Second
+)
+
+// Settings configure a Breaker; zero fields take the defaults above
+type Settings struct {
+	Name string
+
+	// Outcomes are counted over the last Window, kept in Buckets slices
+	// that expire one at a time
+	Window  time.Duration
+	Buckets int
+
+	// The breaker opens once the window holds at least MinRequests calls
+	// and at least FailureRatio of them failed. ReadyToTrip, if set,
+	// replaces that rule.
+	MinRequests  int
+	FailureRatio float64
+	ReadyToTrip  func(window Counts) bool
+
+	// CoolDown is how long the breaker stays open before probing
+	CoolDown time.Duration
+
+	// HalfOpenProbes calls are let through while half-open; when all of
+	// them succeed the breaker closes
+	HalfOpenProbes int
+
+	// ProbeTimeout bounds how long half-open probes may take. If the
+	// breaker is still undecided ProbeTimeout after the last probe was let
+	// through, the outstanding probes are abandoned, their results are
+	// ignored, and new probes are let through.
+	ProbeTimeout time.Duration
+
+	// IsFailure decides which errors count against the operation. The
+	// default counts every error except context cancellation, which is
+	// the caller's doing. A half-open probe only counts towards closing
+	// when it returns nil; other errors that aren't failures just free
+	// its slot.
+	IsFailure func(err error) bool
+
+	// OnStateChange is called on every transition, outside the lock
+	OnStateChange func(name string, from, to State)
+
+	// Now replaces time.Now, for tests
+	Now func() time.Time
+}
+
+// Metrics is a snapshot of a breaker
+type Metrics struct {
+	State       State
+	Window      Counts // outcomes in the rolling window
+	Total       Counts // outcomes since New
+	Rejected    int    // calls refused with ErrCircuitOpen
+	Transitions int
+	Since       time.Time // when the current state began
+}
+
+func (m Metrics) String() string {
+	return fmt.Sprintf("state=%v window=%d/%d failed total=%d/%d failed rejected=%d transitions=%d",
+		m.State, m.Window.Failures, m.Window.Requests, m.Total.Failures, m.Total.Requests, m.Rejected, m.Transitions)
+}
+
+// Breaker is a circuit breaker. It is safe for concurrent use.
+type Breaker struct {
+	settings Settings
+	width    time.Duration // of one bucket
+
+	mu          sync.Mutex
+	state       State
+	generation  uint64 // bumped on every transition; stale results are ignored
+	since       time.Time
+	buckets     []Counts
+	head        int       // bucket for the current slice of time
+	headStart   int64     // index of that slice, in bucket widths since the zero time
+	probes      int       // half-open calls let through
+	probeOK     int       // half-open calls that succeeded
+	probeExpiry time.Time // when outstanding probes are abandoned
+	total       Counts
+	rejected    int
+	transitions int
+}
+
+// New returns a closed breaker
+func New(s Settings) *Breaker {
+	if s.Window <= 0 {
+		s.Window = DefaultWindow
+	}
+	if s.Buckets <= 0 {
+		s.Buckets = DefaultBuckets
+	}
+	if s.MinRequests <= 0 {
+		s.MinRequests = DefaultMinRequests
+	}
+	if s.FailureRatio <= 0 {
+		s.FailureRatio = DefaultFailureRatio
+	}
+	if s.CoolDown <= 0 {
+		s.CoolDown = DefaultCoolDown
+	}
+	if s.HalfOpenProbes <= 0 {
+		s.HalfOpenProbes = DefaultHalfOpenProbes
+	}
+	if s.ProbeTimeout <= 0 {
+		s.ProbeTimeout = DefaultProbeTimeout
+	}
+	if s.IsFailure == nil {
+		s.IsFailure = defaultIsFailure
+	}
+	if s.Now == nil {
+		s.Now = time.Now
+	}
+
+	b := &Breaker{
+		settings: s,
+		width:    max(s.Window/time.Duration(s.Buckets), 1),
+		buckets:  make([]Counts, s.Buckets),
+	}
+	b.since = s.Now()
+	b.headStart = b.slice(b.since)
+	return b
+}
+
+func defaultIsFailure(err error) bool {
+	return err != nil && !errors.Is(err, context.Canceled)
+}
+
+// Name returns the name from Settings
+func (b *Breaker) Name() string {
+	return b.settings.Name
+}
+
+// Do calls fn if the breaker allows it and records the outcome. When the
+// breaker is open fn is not called and the error is an *OpenError.
+func (b *Breaker) Do(fn func() error) error {
+	_, err := Execute(b, func() (struct{}, error) {
+		return struct{}{}, fn()
+	})
+	return err
+}
+
+// Execute is Do for operations that return a value
+func Execute[T any](b *Breaker, fn func() (T, error)) (T, error) {
+	done, err := b.Allow()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	// A panic counts as a failure and keeps unwinding
+	failed := true
+	defer func() {
+		if failed {
+			done(errors.New("breaker: operation panicked"))
+		}
+	}()
+
+	result, err := fn()
+	failed = false
+	done(err)
+	return result, err
+}
+
+// Allow is the two-step form of Do, for calls that don't fit in a closure.
+// If the call may proceed, report its outcome by calling done exactly once.
+func (b *Breaker) Allow() (done func(err error), err error) {
+	b.mu.Lock()
+	now := b.settings.Now()
+	change := b.advance(now)
+
+	var openErr *OpenError
+	switch b.state {
+	case Open:
+		openErr = &OpenError{Name: b.settings.Name, State: Open, RetryAfter: b.since.Add(b.settings.CoolDown).Sub(now)}
+	case HalfOpen:
+		if b.probes >= b.settings.HalfOpenProbes {
+			openErr = &OpenError{Name: b.settings.Name, State: HalfOpen}
+		} else {
+			b.probes++
+			b.probeExpiry = now.Add(b.settings.ProbeTimeout)
+		}
+	}
+	if openErr != nil {
+		b.rejected++
+	}
+	generation := b.generation
+	b.mu.Unlock()
+	b.notify(change)
+
+	if openErr != nil {
+		return nil, openErr
+	}
+	var once sync.Once
+	return func(err error) {
+		once.Do(func() { b.record(generation, err) })
+	}, nil
+}
+
+// State returns the current state, moving from open to half-open if the
+// cool-down is over
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	change := b.advance(b.settings.Now())
+	state := b.state
+	b.mu.Unlock()
+	b.notify(change)
+	return state
+}
+
+// Metrics returns a snapshot of the counters
+func (b *Breaker) Metrics() Metrics {
+	b.mu.Lock()
+	change := b.advance(b.settings.Now())
+	m := Metrics{
+		State:       b.state,
+		Window:      b.window(),
+		Total:       b.total,
+		Rejected:    b.rejected,
+		Transitions: b.transitions,
+		Since:       b.since,
+	}
+	b.mu.Unlock()
+	b.notify(change)
+	return m
+}
+
+// Reset closes the breaker and clears the window
+func (b *Breaker) Reset() {
+	b.mu.Lock()
+	change := b.setState(Closed, b.settings.Now())
+	b.mu.Unlock()
+	b.notify(change)
+}
+
+// transition is a state change to report once the lock is released
+type transition struct {
+	from, to State
+}
+
+func (b *Breaker) notify(t *transition) {
+	if t != nil && b.settings.OnStateChange != nil {
+		b.settings.OnStateChange(b.settings.Name, t.from, t.to)
+	}
+}
+
+func (b *Breaker) record(generation uint64, err error) {
+	b.mu.Lock()
+	now := b.settings.Now()
+	change := b.advance(now)
+	if generation != b.generation {
+		// Started before the last transition; its outcome says nothing
+		// about the current state
+		b.mu.Unlock()
+		b.notify(change)
+		return
+	}
+
+	outcome := Counts{Requests: 1, Successes: 1}
+	if b.settings.IsFailure(err) {
+		outcome = Counts{Requests: 1, Failures: 1}
+	}
+	b.total.add(outcome)
+
+	switch b.state {
+	case Closed:
+		b.buckets[b.head].add(outcome)
+		if b.tripped(b.window()) {
+			change = b.setState(Open, now)
+		}
+	case HalfOpen:
+		switch {
+		case outcome.Failures > 0:
+			change = b.setState(Open, now)
+		case err != nil:
+			// Neither a failure nor a success, such as a cancelled
+			// call: free the slot for another probe
+			b.probes--
+		default:
+			if b.probeOK++; b.probeOK >= b.settings.HalfOpenProbes {
+				change = b.setState(Closed, now)
+			}
+		}
+	}
+	b.mu.Unlock()
+	b.notify(change)
+}
+
+func (b *Breaker) tripped(window Counts) bool {
+	if b.settings.ReadyToTrip != nil {
+		return b.settings.ReadyToTrip(window)
+	}
+	return window.Requests >= b.settings.MinRequests && window.FailureRatio() >= b.settings.FailureRatio
+}
+
+// advance expires old buckets, ends a finished cool-down and abandons
+// half-open probes that have run past ProbeTimeout
+func (b *Breaker) advance(now time.Time) *transition {
+	b.rotate(now)
+	switch {
+	case b.state == Open && !now.Before(b.since.Add(b.settings.CoolDown)):
+		return b.setState(HalfOpen, now)
+	case b.state == HalfOpen && b.probes > 0 && !now.Before(b.probeExpiry):
+		// A probe whose done is never called would otherwise hold its
+		// slot forever. Start a new round; late results are stale.
+		b.generation++
+		b.probes, b.probeOK = 0, 0
+	}
+	return nil
+}
+
+func (b *Breaker) slice(t time.Time) int64 {
+	return t.UnixNano() / int64(b.width)
+}
+
+// rotate moves the head to the bucket for now, clearing buckets whose time
+// has passed
+func (b *Breaker) rotate(now time.Time) {
+	current := b.slice(now)
+	steps := current - b.headStart
+	if steps <= 0 {
+		return
+	}
+	if steps > int64(len(b.buckets)) {
+		steps = int64(len(b.buckets))
+	}
+	for range steps {
+		b.head = (b.head + 1) % len(b.buckets)
+		b.buckets[b.head] = Counts{}
+	}
+	b.headStart = current
+}
+
+func (b *Breaker) window() Counts {
+	var c Counts
+	for _, bucket := range b.buckets {
+		c.add(bucket)
+	}
+	return c
+}
+
+func (b *Breaker) setState(to State, now time.Time) *transition {
+	from := b.state
+	b.state = to
+	b.since = now
+	b.generation++
+	b.probes, b.probeOK = 0, 0
+	if to == Closed {
+		clear(b.buckets)
+	}
+	if from == to {
+		return nil
+	}
+	b.transitions++
+	return &transition{from: from, to: to}
+}
@@ -0,0 +1,330 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errBoom = errors.New("boom")
+
+// fakeClock is a manually advanced time source
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type change struct{ from, to State }
+
+func newTestBreaker(s Settings) (*Breaker, *fakeClock, *[]change) {
+	clock := &fakeClock{now: time.Unix(1_000_000, 0)}
+	var changes []change
+	s.Now = clock.Now
+	s.OnStateChange = func(name string, from, to State) {
+		changes = append(changes, change{from, to})
+	}
+	return New(s), clock, &changes
+}
+
+func fail() error    { return errBoom }
+func succeed() error { return nil }
+
+func TestTripsOnFailureRatio(t *testing.T) {
+	b, _, changes := newTestBreaker(Settings{MinRequests: 4, FailureRatio: 0.5})
+
+	// 1 failure in 3 requests: below MinRequests
+	b.Do(succeed)
+	b.Do(fail)
+	b.Do(succeed)
+	if got := b.State(); got != Closed {
+		t.Fatalf("State = %v; want closed", got)
+	}
+
+	// 2 of 4 failed: trips
+	b.Do(fail)
+	if got := b.State(); got != Open {
+		t.Fatalf("State = %v; want open", got)
+	}
+	if len(*changes) != 1 || (*changes)[0] != (change{Closed, Open}) {
+		t.Errorf("changes = %v; want [closed -> open]", *changes)
+	}
+}
+
+func TestOpenRejects(t *testing.T) {
+	b, clock, _ := newTestBreaker(Settings{Name: "api", MinRequests: 1, CoolDown: time.Minute})
+	b.Do(fail)
+	clock.Advance(20 * time.Second)
+
+	called := false
+	err := b.Do(func() error { called = true; return nil })
+	if called {
+		t.Error("operation should not run while open")
+	}
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Do = %v; want ErrCircuitOpen", err)
+	}
+	var openErr *OpenError
+	if !errors.As(err, &openErr) || openErr.Name != "api" || openErr.RetryAfter != 40*time.Second {
+		t.Errorf("OpenError = %+v; want name api, 40s left", openErr)
+	}
+	if m := b.Metrics(); m.Rejected != 1 || m.Total.Requests != 1 {
+		t.Errorf("Metrics = %v; want 1 rejected, 1 request", m)
+	}
+}
+
+func TestHalfOpenRecovers(t *testing.T) {
+	b, clock, changes := newTestBreaker(Settings{MinRequests: 1, CoolDown: time.Second, HalfOpenProbes: 2})
+	b.Do(fail)
+	clock.Advance(time.Second)
+
+	if got := b.State(); got != HalfOpen {
+		t.Fatalf("State = %v; want half-open", got)
+	}
+	b.Do(succeed)
+	if got := b.State(); got != HalfOpen {
+		t.Fatalf("State after 1 of 2 probes = %v; want half-open", got)
+	}
+	b.Do(succeed)
+	if got := b.State(); got != Closed {
+		t.Fatalf("State = %v; want closed", got)
+	}
+
+	want := []change{{Closed, Open}, {Open, HalfOpen}, {HalfOpen, Closed}}
+	if len(*changes) != len(want) {
+		t.Fatalf("changes = %v; want %v", *changes, want)
+	}
+	for i := range want {
+		if (*changes)[i] != want[i] {
+			t.Errorf("change %d = %v; want %v", i, (*changes)[i], want[i])
+		}
+	}
+	if w := b.Metrics().Window; w.Requests != 0 {
+		t.Errorf("closing should clear the window, got %+v", w)
+	}
+}
+
+func TestHalfOpenFailureReopens(t *testing.T) {
+	b, clock, _ := newTestBreaker(Settings{MinRequests: 1, CoolDown: time.Second})
+	b.Do(fail)
+	clock.Advance(time.Second)
+
+	if err := b.Do(fail); !errors.Is(err, errBoom) {
+		t.Errorf("probe error = %v; want errBoom", err)
+	}
+	if got := b.State(); got != Open {
+		t.Errorf("State = %v; want open", got)
+	}
+
+	// The cool-down restarts from the failed probe
+	clock.Advance(time.Second / 2)
+	if got := b.State(); got != Open {
+		t.Errorf("State half-way through the new cool-down = %v; want open", got)
+	}
+}
+
+func TestHalfOpenProbeLimit(t *testing.T) {
+	b, clock, _ := newTestBreaker(Settings{MinRequests: 1, CoolDown: time.Second, HalfOpenProbes: 1})
+	b.Do(fail)
+	clock.Advance(time.Second)
+
+	done, err := b.Allow()
+	if err != nil {
+		t.Fatalf("first probe rejected: %v", err)
+	}
+	if _, err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("second concurrent probe: err = %v; want ErrCircuitOpen", err)
+	}
+	done(nil)
+	if got := b.State(); got != Closed {
+		t.Errorf("State = %v; want closed", got)
+	}
+}
+
+func TestHalfOpenCancelledProbe(t *testing.T) {
+	b, clock, _ := newTestBreaker(Settings{MinRequests: 1, CoolDown: time.Second, HalfOpenProbes: 1})
+	b.Do(fail)
+	clock.Advance(time.Second)
+
+	// A cancelled probe neither closes nor reopens the breaker
+	b.Do(func() error { return context.Canceled })
+	if got := b.State(); got != HalfOpen {
+		t.Fatalf("State after cancelled probe = %v; want half-open", got)
+	}
+	// and its slot is free for the next probe
+	if err := b.Do(succeed); err != nil {
+		t.Fatalf("probe after cancelled probe: %v", err)
+	}
+	if got := b.State(); got != Closed {
+		t.Errorf("State = %v; want closed", got)
+	}
+}
+
+func TestHalfOpenProbeTimeout(t *testing.T) {
+	b, clock, changes := newTestBreaker(Settings{MinRequests: 1, CoolDown: time.Second, ProbeTimeout: 3 * time.Second})
+	b.Do(fail)
+	clock.Advance(time.Second)
+
+	lost, err := b.Allow()
+	if err != nil {
+		t.Fatalf("first probe rejected: %v", err)
+	}
+	clock.Advance(2 * time.Second)
+	if _, err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("probe before the timeout: err = %v; want ErrCircuitOpen", err)
+	}
+
+	// The first probe never reports; after ProbeTimeout its slot is reused
+	clock.Advance(time.Second)
+	done, err := b.Allow()
+	if err != nil {
+		t.Fatalf("probe after the timeout rejected: %v", err)
+	}
+	lost(errBoom) // too late to count
+	if got := b.State(); got != HalfOpen {
+		t.Fatalf("State after abandoned probe reported = %v; want half-open", got)
+	}
+	done(nil)
+	if got := b.State(); got != Closed {
+		t.Errorf("State = %v; want closed", got)
+	}
+	if n := len(*changes); n != 3 {
+		t.Errorf("changes = %v; want closed, open, half-open, closed", *changes)
+	}
+}
+
+func TestRollingWindow(t *testing.T) {
+	b, clock, _ := newTestBreaker(Settings{Window: 10 * time.Second, Buckets: 10, MinRequests: 3, FailureRatio: 0.6})
+
+	b.Do(fail)
+	b.Do(fail)
+	clock.Advance(11 * time.Second) // both failures expire
+	b.Do(fail)
+	b.Do(succeed)
+	b.Do(succeed)
+	if got := b.State(); got != Closed {
+		t.Errorf("State = %v; want closed, old failures should have expired", got)
+	}
+	if w := b.Metrics().Window; w.Requests != 3 || w.Failures != 1 {
+		t.Errorf("Window = %+v; want 3 requests, 1 failure", w)
+	}
+
+	// Buckets expire one at a time
+	clock.Advance(5 * time.Second)
+	b.Do(fail)
+	b.Do(fail)
+	if w := b.Metrics().Window; w.Requests != 5 {
+		t.Errorf("Window = %+v; want 5 requests", w)
+	}
+	if got := b.State(); got != Open {
+		t.Errorf("State = %v; want open, 3 failures in 5 reaches the ratio", got)
+	}
+}
+
+func TestReadyToTrip(t *testing.T) {
+	b, _, _ := newTestBreaker(Settings{ReadyToTrip: func(c Counts) bool { return c.Failures >= 3 }})
+	for range 2 {
+		b.Do(fail)
+	}
+	if got := b.State(); got != Closed {
+		t.Fatalf("State = %v; want closed", got)
+	}
+	b.Do(fail)
+	if got := b.State(); got != Open {
+		t.Errorf("State = %v; want open", got)
+	}
+}
+
+func TestIsFailure(t *testing.T) {
+	b, _, _ := newTestBreaker(Settings{MinRequests: 1})
+	b.Do(func() error { return context.Canceled })
+	if got := b.State(); got != Closed {
+		t.Errorf("cancellation should not count as a failure, State = %v", got)
+	}
+	if m := b.Metrics(); m.Total.Successes != 1 {
+		t.Errorf("Total = %+v; want 1 success", m.Total)
+	}
+}
+
+func TestStaleOutcomeIgnored(t *testing.T) {
+	b, _, _ := newTestBreaker(Settings{MinRequests: 1})
+	slow, err := b.Allow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Do(fail) // opens
+	slow(nil)  // began while closed; must not affect the open breaker
+	if got := b.State(); got != Open {
+		t.Errorf("State = %v; want open", got)
+	}
+}
+
+func TestPanicCountsAsFailure(t *testing.T) {
+	b, _, _ := newTestBreaker(Settings{MinRequests: 1})
+	func() {
+		defer func() { recover() }()
+		b.Do(func() error { panic("boom") })
+	}()
+	if got := b.State(); got != Open {
+		t.Errorf("State = %v; want open", got)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	b, _, _ := newTestBreaker(Settings{})
+	got, err := Execute(b, func() (int, error) { return 42, nil })
+	if got != 42 || err != nil {
+		t.Errorf("Execute = %d, %v; want 42, nil", got, err)
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	b := New(Settings{MinRequests: 10, CoolDown: time.Millisecond})
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Go(func() {
+			for j := range 500 {
+				if (i+j)%3 == 0 {
+					b.Do(fail)
+				} else {
+					b.Do(succeed)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	m := b.Metrics()
+	if m.Total.Requests+m.Rejected != 8*500 {
+		t.Errorf("requests %d + rejected %d != %d", m.Total.Requests, m.Rejected, 8*500)
+	}
+}
+
+func TestStateString(t *testing.T) {
+	testCases := []struct {
+		state State
+		want  string
+	}{
+		{Closed, "closed"},
+		{Open, "open"},
+		{HalfOpen, "half-open"},
+		{State(7), "State(7)"},
+	}
+	for _, tc := range testCases {
+		if got := tc.state.String(); got != tc.want {
+			t.Errorf("String() = %q; want %q", got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Package errcatalog maps errors to the ways a program reports them: a
+// stable code for clients and logs, whether retrying can help, an HTTP
+// status and a process exit code.
+//
+// Sentinel errors are registered with Register and matched with errors.Is;
+// error types are registered with RegisterAs and matched with errors.As, so
+// wrapped errors resolve to the same entry as the originals.
+//
+//	errcatalog.Register(ErrNotFound, errcatalog.Entry{Code: "not_found", HTTPStatus: 404, ExitCode: 3})
+//	errcatalog.RegisterAs[NetworkError](errcatalog.Entry{Code: "network", Retryable: true, HTTPStatus: 502, ExitCode: 5})
+//
+//	entry := errcatalog.Lookup(fmt.Errorf("loading user: %w", ErrNotFound)) // not_found
+package errcatalog
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Entry describes how an error is reported
+type Entry struct {
+	Code       string `json:"code"`      // stable identifier, such as "not_found"
+	Retryable  bool   `json:"retryable"` // whether the same call may succeed later
+	HTTPStatus int    `json:"-"`
+	ExitCode   int    `json:"-"`
+}
+
+// Unknown is returned for errors that match no registration
+var Unknown = Entry{Code: "internal", HTTPStatus: 500, ExitCode: 1}
+
+// OK is returned for a nil error
+var OK = Entry{Code: "ok", HTTPStatus: 200, ExitCode: 0}
+
+// sentinel is one Register call
+type sentinel struct {
+	err   error
+	entry Entry
+}
+
+// typed is one RegisterAs call; match reports the entry if err's tree
+// contains a T
+type typed struct {
+	typ   reflect.Type
+	match func(err error) (Entry, bool)
+}
+
+// Catalog holds registrations. Types are checked before sentinels, since a
+// typed error such as a NetworkError usually says more than the sentinel it
+// wraps; within each group the first registration wins.
+type Catalog struct {
+	mu        sync.RWMutex
+	sentinels []sentinel
+	types     []typed
+	codes     map[string]bool
+}
+
+func New() *Catalog {
+	return &Catalog{codes: make(map[string]bool)}
+}
+
+// Default is the catalog used by the package-level functions
+var Default = New()
+
+// claim reserves a code; codes must be unique so clients can rely on them
+func (c *Catalog) claim(code string) {
+	if code == "" {
+		panic("errcatalog: entry needs a code")
+	}
+	if c.codes[code] {
+		panic(fmt.Sprintf("errcatalog: code %q registered twice", code))
+	}
+	c.codes[code] = true
+}
+
+// Register maps a sentinel error, and anything wrapping it, to entry
+func (c *Catalog) Register(err error, entry Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.claim(entry.Code)
+	c.sentinels = append(c.sentinels, sentinel{err: err, entry: entry})
+}
+
+// RegisterAs maps every error of type T in the default catalog to entry
+func RegisterAs[T error](entry Entry) {
+	RegisterAsIn[T](Default, entry)
+}
+
+// RegisterAsIn maps every error of type T in c to entry
+func RegisterAsIn[T error](c *Catalog, entry Entry) {
+	RegisterAsFuncIn(c, entry.Code, func(T) Entry { return entry })
+}
+
+// RegisterAsFunc maps errors of type T in the default catalog to an entry
+// computed from the matched value, for types whose fields change the
+// answer; for example a NetworkError is only retryable when it timed out.
+// The returned entry's Code is always replaced by code.
+func RegisterAsFunc[T error](code string, fn func(T) Entry) {
+	RegisterAsFuncIn(Default, code, fn)
+}
+
+// RegisterAsFuncIn is RegisterAsFunc for catalog c
+func RegisterAsFuncIn[T error](c *Catalog, code string, fn func(T) Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	typ := reflect.TypeFor[T]()
+	for _, t := range c.types {
+		if t.typ == typ {
+			panic(fmt.Sprintf("errcatalog: %v registered twice", typ))
+		}
+	}
+	c.claim(code)
+	c.types = append(c.types, typed{
+		typ: typ,
+		match: func(err error) (Entry, bool) {
+			var target T
+			if !errors.As(err, &target) {
+				return Entry{}, false
+			}
+			entry := fn(target)
+			entry.Code = code
+			return entry, true
+		},
+	})
+}
+
+// Lookup returns the entry for err: OK for nil, Unknown if nothing matches
+func (c *Catalog) Lookup(err error) Entry {
+	entry, _ := c.Find(err)
+	return entry
+}
+
+// Find is Lookup that also reports whether err matched a registration
+func (c *Catalog) Find(err error) (Entry, bool) {
+	if err == nil {
+		return OK, true
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, t := range c.types {
+		if entry, ok := t.match(err); ok {
+			return entry, true
+		}
+	}
+	for _, s := range c.sentinels {
+		if errors.Is(err, s.err) {
+			return s.entry, true
+		}
+	}
+	return Unknown, false
+}
+
+// Register adds a sentinel to the default catalog
+func Register(err error, entry Entry) {
+	Default.Register(err, entry)
+}
+
+// Lookup resolves err in the default catalog
+func Lookup(err error) Entry {
+	return Default.Lookup(err)
+}
+
+// Code returns the stable code for err from the default catalog
+func Code(err error) string {
+	return Default.Lookup(err).Code
+}
+
+// IsRetryable reports whether the default catalog marks err as retryable
+func IsRetryable(err error) bool {
+	return Default.Lookup(err).Retryable
+}
+
+// HTTPStatus returns the status to respond with for err
+func HTTPStatus(err error) int {
+	return Default.Lookup(err).HTTPStatus
+}
+
+// ExitCode returns the process exit code for err, 0 for nil
+func ExitCode(err error) int {
+	return Default.Lookup(err).ExitCode
+}
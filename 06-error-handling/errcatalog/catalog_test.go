@@ -0,0 +1,161 @@
+package errcatalog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var (
+	errNotFound     = errors.New("not found")
+	errUnauthorized = errors.New("unauthorized")
+)
+
+type netError struct {
+	Timeout bool
+}
+
+func (e netError) Error() string { return fmt.Sprintf("network error (timeout=%v)", e.Timeout) }
+
+type fieldError struct {
+	Field string
+}
+
+func (e *fieldError) Error() string { return "invalid " + e.Field }
+
+func testCatalog() *Catalog {
+	c := New()
+	c.Register(errNotFound, Entry{Code: "not_found", HTTPStatus: 404, ExitCode: 3})
+	c.Register(errUnauthorized, Entry{Code: "unauthorized", HTTPStatus: 401, ExitCode: 4})
+	RegisterAsFuncIn(c, "network", func(e netError) Entry {
+		return Entry{Retryable: e.Timeout, HTTPStatus: 502, ExitCode: 5}
+	})
+	RegisterAsIn[*fieldError](c, Entry{Code: "invalid_field", HTTPStatus: 422, ExitCode: 2})
+	return c
+}
+
+func TestLookup(t *testing.T) {
+	c := testCatalog()
+
+	testCases := []struct {
+		name string
+		err  error
+		want Entry
+	}{
+		{"nil", nil, OK},
+		{"sentinel", errNotFound, Entry{Code: "not_found", HTTPStatus: 404, ExitCode: 3}},
+		{"wrapped sentinel", fmt.Errorf("user 42: %w", errUnauthorized), Entry{Code: "unauthorized", HTTPStatus: 401, ExitCode: 4}},
+		{"type with timeout", fmt.Errorf("fetch: %w", netError{Timeout: true}), Entry{Code: "network", Retryable: true, HTTPStatus: 502, ExitCode: 5}},
+		{"type without timeout", netError{}, Entry{Code: "network", HTTPStatus: 502, ExitCode: 5}},
+		{"pointer type", &fieldError{Field: "age"}, Entry{Code: "invalid_field", HTTPStatus: 422, ExitCode: 2}},
+		{"joined", errors.Join(errors.New("other"), &fieldError{}), Entry{Code: "invalid_field", HTTPStatus: 422, ExitCode: 2}},
+		// Types win over the sentinels they carry
+		{"type before sentinel", errors.Join(errNotFound, netError{}), Entry{Code: "network", HTTPStatus: 502, ExitCode: 5}},
+		{"unknown", errors.New("boom"), Unknown},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := c.Lookup(tc.err); got != tc.want {
+				t.Errorf("Lookup = %+v; want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDuplicateRegistration(t *testing.T) {
+	testCases := map[string]func(c *Catalog){
+		"code":  func(c *Catalog) { c.Register(errors.New("x"), Entry{Code: "not_found"}) },
+		"type":  func(c *Catalog) { RegisterAsIn[netError](c, Entry{Code: "network2"}) },
+		"empty": func(c *Catalog) { c.Register(errors.New("x"), Entry{}) },
+	}
+	for name, register := range testCases {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a panic")
+				}
+			}()
+			register(testCatalog())
+		})
+	}
+}
+
+func decodeBody(t *testing.T, rec *httptest.ResponseRecorder) ErrorDetail {
+	t.Helper()
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	var body ErrorBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding %q: %v", rec.Body.String(), err)
+	}
+	return body.Error
+}
+
+func TestHandler(t *testing.T) {
+	c := testCatalog()
+	h := c.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		switch r.URL.Path {
+		case "/missing":
+			return fmt.Errorf("user %s: %w", r.URL.Query().Get("id"), errNotFound)
+		case "/flaky":
+			return netError{Timeout: true}
+		case "/secret":
+			return errors.New("open /etc/db.conf: permission denied")
+		}
+		w.Write([]byte("ok"))
+		return nil
+	})
+
+	testCases := []struct {
+		path   string
+		status int
+		want   ErrorDetail
+	}{
+		{"/missing?id=42", 404, ErrorDetail{Code: "not_found", Message: "user 42: not found"}},
+		{"/flaky", 502, ErrorDetail{Code: "network", Message: "Bad Gateway", Retryable: true}},
+		{"/secret", 500, ErrorDetail{Code: "internal", Message: "Internal Server Error"}},
+	}
+	for _, tc := range testCases {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", tc.path, nil))
+		if rec.Code != tc.status {
+			t.Errorf("%s: status = %d; want %d", tc.path, rec.Code, tc.status)
+		}
+		if got := decodeBody(t, rec); got != tc.want {
+			t.Errorf("%s: body = %+v; want %+v", tc.path, got, tc.want)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != 200 || rec.Body.String() != "ok" {
+		t.Errorf("success: %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestMiddlewareRecoversPanics(t *testing.T) {
+	c := testCatalog()
+	h := c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/typed" {
+			panic(&fieldError{Field: "name"})
+		}
+		panic("nil map write")
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if got := decodeBody(t, rec); rec.Code != 500 || got.Code != "internal" {
+		t.Errorf("string panic: %d %+v", rec.Code, got)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/typed", nil))
+	if got := decodeBody(t, rec); rec.Code != 422 || got.Code != "invalid_field" {
+		t.Errorf("error panic: %d %+v", rec.Code, got)
+	}
+}
@@ -0,0 +1,94 @@
+package errcatalog
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"grok-study-plan/06-error-handling/recovery"
+)
+
+// ErrorBody is the JSON written for every failed request:
+//
+//	{"error":{"code":"not_found","message":"user 42: not found","retryable":false}}
+type ErrorBody struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail is the "error" object of ErrorBody
+type ErrorDetail struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"retryable"`
+}
+
+// WriteError responds with the status and JSON body for err. Messages of
+// server errors (5xx) are replaced by the status text, so internal details
+// such as file paths or SQL never reach clients; log the error instead.
+func (c *Catalog) WriteError(w http.ResponseWriter, err error) {
+	entry := c.Lookup(err)
+	status := entry.HTTPStatus
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	message := err.Error()
+	if status >= 500 {
+		message = http.StatusText(status)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ErrorBody{Error: ErrorDetail{
+		Code:      entry.Code,
+		Message:   message,
+		Retryable: entry.Retryable,
+	}})
+}
+
+// HandlerFunc is an http.HandlerFunc that can fail. Returning an error
+// before writing anything sends the catalog's JSON error response.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Handler adapts fn to an http.Handler that reports errors through c
+func (c *Catalog) Handler(fn HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := fn(w, r); err != nil {
+			c.WriteError(w, err)
+		}
+	})
+}
+
+// Middleware wraps next so that a panic becomes a JSON error body instead
+// of a dropped connection. The panic is wrapped in a *recovery.PanicError,
+// so a panic with a registered error still maps to that error's entry.
+func (c *Catalog) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v) // the server treats this one specially
+			}
+			c.WriteError(w, recovery.New(v))
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// WriteError responds with the default catalog's JSON body for err
+func WriteError(w http.ResponseWriter, err error) {
+	Default.WriteError(w, err)
+}
+
+// Handler adapts fn using the default catalog
+func Handler(fn HandlerFunc) http.Handler {
+	return Default.Handler(fn)
+}
+
+// Middleware recovers panics in next using the default catalog
+func Middleware(next http.Handler) http.Handler {
+	return Default.Middleware(next)
+}
@@ -0,0 +1,289 @@
+// Package errtrace wraps errors with the place they were wrapped and
+// key/value attributes, so the context gathered on the way up a call chain
+// survives until the error is logged.
+//
+//	if err := fetchURL(url); err != nil {
+//	    return errtrace.Wrap(err, "loading profile", "user_id", id)
+//	}
+//
+// Each *Error implements slog.LogValuer, so
+//
+//	logger.Error("request failed", "err", err)
+//
+// logs every wrap point with its location and attributes, plus the
+// underlying cause with its own fields, instead of one flattened string.
+package errtrace
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// maxStack bounds the frames captured by WrapStack
+const maxStack = 64
+
+// Error is one wrap point in a chain
+type Error struct {
+	msg   string // added at this point; may be empty
+	err   error  // the wrapped error; nil for New
+	attrs []slog.Attr
+	pc    uintptr   // the caller of New, Wrap, With or WrapStack
+	stack []uintptr // only set by WrapStack
+}
+
+// New returns an error with a message and attributes, recording its caller.
+// args are alternating keys and values, or slog.Attr values, as for
+// slog.Logger.Info.
+func New(msg string, args ...any) error {
+	return newError(msg, nil, args, false)
+}
+
+// Wrap annotates err with a message and attributes, recording its caller.
+// It returns nil if err is nil, so it can wrap a call's result directly.
+func Wrap(err error, msg string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return newError(msg, err, args, false)
+}
+
+// With is Wrap without a message, for adding attributes only
+func With(err error, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return newError("", err, args, false)
+}
+
+// WrapStack is Wrap that also captures the full stack, for errors whose
+// origin is hard to find from the call sites alone
+func WrapStack(err error, msg string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return newError(msg, err, args, true)
+}
+
+func newError(msg string, err error, args []any, withStack bool) *Error {
+	e := &Error{msg: msg, err: err, attrs: argsToAttrs(args)}
+
+	// Skip runtime.Callers, newError and the exported function
+	var pcs [maxStack]uintptr
+	n := 1
+	if withStack {
+		n = maxStack
+	}
+	n = runtime.Callers(3, pcs[:n])
+	if n > 0 {
+		e.pc = pcs[0]
+	}
+	if withStack {
+		e.stack = append([]uintptr(nil), pcs[:n]...)
+	}
+	return e
+}
+
+// argsToAttrs pairs up args the way slog does, keeping a dangling value
+// under "!BADKEY"
+func argsToAttrs(args []any) []slog.Attr {
+	var attrs []slog.Attr
+	for len(args) > 0 {
+		switch key := args[0].(type) {
+		case slog.Attr:
+			attrs = append(attrs, key)
+			args = args[1:]
+		case string:
+			if len(args) == 1 {
+				attrs = append(attrs, slog.String("!BADKEY", key))
+				args = nil
+				break
+			}
+			attrs = append(attrs, slog.Any(key, args[1]))
+			args = args[2:]
+		default:
+			attrs = append(attrs, slog.Any("!BADKEY", key))
+			args = args[1:]
+		}
+	}
+	return attrs
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.err == nil:
+		return e.msg
+	case e.msg == "":
+		return e.err.Error()
+	}
+	return e.msg + ": " + e.err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Message is the text added at this wrap point, without the wrapped error
+func (e *Error) Message() string {
+	return e.msg
+}
+
+// Attrs are the attributes added at this wrap point
+func (e *Error) Attrs() []slog.Attr {
+	return append([]slog.Attr(nil), e.attrs...)
+}
+
+// Caller is where this wrap point was created
+func (e *Error) Caller() runtime.Frame {
+	frame, _ := runtime.CallersFrames([]uintptr{e.pc}).Next()
+	return frame
+}
+
+// Stack is the stack captured by WrapStack, or nil
+func (e *Error) Stack() []runtime.Frame {
+	if e.stack == nil {
+		return nil
+	}
+	var frames []runtime.Frame
+	it := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := it.Next()
+		frames = append(frames, frame)
+		if !more {
+			return frames
+		}
+	}
+}
+
+// Chain returns every *Error in err's chain, outermost first. Other
+// wrappers in between, such as fmt.Errorf with %w, are passed through.
+func Chain(err error) []*Error {
+	var chain []*Error
+	for err != nil {
+		if e, ok := err.(*Error); ok {
+			chain = append(chain, e)
+		}
+		err = errors.Unwrap(err)
+	}
+	return chain
+}
+
+// Cause returns the innermost error: the first one in the chain that wraps
+// nothing, or that wraps several errors (such as errors.Join)
+func Cause(err error) error {
+	for err != nil {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+	return nil
+}
+
+// Attrs collects the attributes of every wrap point in err's chain,
+// outermost first
+func Attrs(err error) []slog.Attr {
+	var attrs []slog.Attr
+	for _, e := range Chain(err) {
+		attrs = append(attrs, e.attrs...)
+	}
+	return attrs
+}
+
+// StackOf returns the innermost stack captured with WrapStack, or nil
+func StackOf(err error) []runtime.Frame {
+	chain := Chain(err)
+	for i := len(chain) - 1; i >= 0; i-- {
+		if stack := chain[i].Stack(); stack != nil {
+			return stack
+		}
+	}
+	return nil
+}
+
+// LogValue renders the whole chain as a group:
+//
+//	msg=...  chain.0.msg=... chain.0.at=... chain.0.<attrs>  chain.1...  cause.type=... cause.msg=...  stack=[...]
+//
+// The cause is the error wrapped by the innermost wrap point. If it
+// implements slog.LogValuer its own fields appear under cause.fields.
+func (e *Error) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("msg", e.Error())}
+
+	chain := Chain(e)
+	links := make([]slog.Attr, len(chain))
+	for i, link := range chain {
+		group := make([]slog.Attr, 0, len(link.attrs)+2)
+		if link.msg != "" {
+			group = append(group, slog.String("msg", link.msg))
+		}
+		group = append(group, slog.String("at", location(link.Caller())))
+		group = append(group, link.attrs...)
+		links[i] = slog.Attr{Key: strconv.Itoa(i), Value: slog.GroupValue(group...)}
+	}
+	attrs = append(attrs, slog.Attr{Key: "chain", Value: slog.GroupValue(links...)})
+
+	if cause := chain[len(chain)-1].err; cause != nil {
+		group := []slog.Attr{
+			slog.String("type", fmt.Sprintf("%T", cause)),
+			slog.String("msg", cause.Error()),
+		}
+		if lv, ok := cause.(slog.LogValuer); ok {
+			group = append(group, slog.Any("fields", lv.LogValue()))
+		}
+		attrs = append(attrs, slog.Attr{Key: "cause", Value: slog.GroupValue(group...)})
+	}
+
+	if stack := StackOf(e); stack != nil {
+		lines := make([]string, len(stack))
+		for i, frame := range stack {
+			lines[i] = location(frame)
+		}
+		attrs = append(attrs, slog.Any("stack", lines))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// location formats a frame as "pkg.Func file.go:42"
+func location(frame runtime.Frame) string {
+	if frame.Function == "" {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s %s:%d", frame.Function, filepath.Base(frame.File), frame.Line)
+}
+
+// Format supports %+v, which prints one wrap point per line with its
+// location and attributes, followed by the captured stack if any
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		io.WriteString(s, e.Error())
+		for _, link := range Chain(e) {
+			var b strings.Builder
+			fmt.Fprintf(&b, "\n  at %s", location(link.Caller()))
+			if link.msg != "" {
+				fmt.Fprintf(&b, " (%s)", link.msg)
+			}
+			for _, a := range link.attrs {
+				fmt.Fprintf(&b, " %s", a)
+			}
+			io.WriteString(s, b.String())
+		}
+		if stack := StackOf(e); stack != nil {
+			io.WriteString(s, "\nstack:")
+			for _, frame := range stack {
+				fmt.Fprintf(s, "\n  %s", location(frame))
+			}
+		}
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		io.WriteString(s, e.Error())
+	}
+}
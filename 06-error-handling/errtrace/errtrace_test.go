@@ -0,0 +1,185 @@
+package errtrace
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fieldError is a cause with its own structured fields
+type fieldError struct {
+	Field string
+}
+
+func (e fieldError) Error() string { return "bad field " + e.Field }
+
+func (e fieldError) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("field", e.Field))
+}
+
+// line returns the caller's line number
+func line() int {
+	_, _, n, _ := runtime.Caller(1)
+	return n
+}
+
+// loadConfig also returns the line it wrapped on
+func loadConfig() (int, error) {
+	return line(), Wrap(fs.ErrNotExist, "loading config", "path", "/etc/app.toml")
+}
+
+func TestWrap(t *testing.T) {
+	wantLine, err := loadConfig()
+
+	if got := err.Error(); got != "loading config: file does not exist" {
+		t.Errorf("Error() = %q", got)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Error("errors.Is should see through the wrapper")
+	}
+
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatal("errors.As(*Error) failed")
+	}
+	if frame := e.Caller(); frame.Line != wantLine || !strings.HasSuffix(frame.Function, ".loadConfig") {
+		t.Errorf("Caller() = %s:%d; want loadConfig line %d", frame.Function, frame.Line, wantLine)
+	}
+	if attrs := e.Attrs(); len(attrs) != 1 || attrs[0].Key != "path" || attrs[0].Value.String() != "/etc/app.toml" {
+		t.Errorf("Attrs() = %v", attrs)
+	}
+	if e.Stack() != nil {
+		t.Error("Wrap should not capture a stack")
+	}
+}
+
+func TestNil(t *testing.T) {
+	if Wrap(nil, "x") != nil || With(nil, "k", 1) != nil || WrapStack(nil, "x") != nil {
+		t.Error("wrapping nil should return nil")
+	}
+}
+
+func TestNew(t *testing.T) {
+	err := New("quota exceeded", "limit", 10)
+	if err.Error() != "quota exceeded" || Cause(err) != err {
+		t.Errorf("New = %v, cause %v", err, Cause(err))
+	}
+}
+
+func TestChainAndAttrs(t *testing.T) {
+	root := fieldError{Field: "email"}
+	err := Wrap(
+		fmt.Errorf("handler: %w",
+			With(Wrap(root, "validating", "user_id", 42), "tenant", "acme")),
+		"request failed", slog.String("route", "/users"))
+
+	chain := Chain(err)
+	if len(chain) != 3 {
+		t.Fatalf("Chain() has %d links; want 3", len(chain))
+	}
+	wantMsgs := []string{"request failed", "", "validating"}
+	for i, want := range wantMsgs {
+		if got := chain[i].Message(); got != want {
+			t.Errorf("chain[%d].Message() = %q; want %q", i, got, want)
+		}
+	}
+
+	var keys []string
+	for _, a := range Attrs(err) {
+		keys = append(keys, a.Key)
+	}
+	if got := strings.Join(keys, ","); got != "route,tenant,user_id" {
+		t.Errorf("Attrs keys = %s; want route,tenant,user_id", got)
+	}
+	if Cause(err) != error(root) {
+		t.Errorf("Cause() = %v; want %v", Cause(err), root)
+	}
+	if got := err.Error(); got != "request failed: handler: validating: bad field email" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestBadKey(t *testing.T) {
+	attrs := argsToAttrs([]any{"a", 1, 2, "dangling"})
+	want := []string{"a=1", "!BADKEY=2", "!BADKEY=dangling"}
+	if len(attrs) != len(want) {
+		t.Fatalf("attrs = %v; want %v", attrs, want)
+	}
+	for i := range want {
+		if got := attrs[i].String(); got != want[i] {
+			t.Errorf("attrs[%d] = %s; want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestWrapStack(t *testing.T) {
+	err := Wrap(WrapStack(errors.New("disk full"), "writing"), "saving")
+	stack := StackOf(err)
+	if len(stack) == 0 {
+		t.Fatal("StackOf() is empty")
+	}
+	if !strings.HasSuffix(stack[0].Function, ".TestWrapStack") {
+		t.Errorf("stack[0] = %s; want TestWrapStack", stack[0].Function)
+	}
+}
+
+func TestLogValue(t *testing.T) {
+	err := Wrap(WrapStack(fieldError{Field: "email"}, "validating", "user_id", 42), "request failed", "route", "/users")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error("oops", "err", err)
+
+	var record struct {
+		Err struct {
+			Msg   string                    `json:"msg"`
+			Chain map[string]map[string]any `json:"chain"`
+			Cause struct {
+				Type   string         `json:"type"`
+				Msg    string         `json:"msg"`
+				Fields map[string]any `json:"fields"`
+			} `json:"cause"`
+			Stack []string `json:"stack"`
+		} `json:"err"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("invalid JSON %s: %v", buf.Bytes(), err)
+	}
+
+	got := record.Err
+	if got.Msg != "request failed: validating: bad field email" {
+		t.Errorf("msg = %q", got.Msg)
+	}
+	if len(got.Chain) != 2 {
+		t.Fatalf("chain = %v; want 2 links", got.Chain)
+	}
+	if got.Chain["0"]["route"] != "/users" || got.Chain["1"]["user_id"] != float64(42) {
+		t.Errorf("chain attributes lost: %v", got.Chain)
+	}
+	if at, _ := got.Chain["0"]["at"].(string); !strings.Contains(at, "TestLogValue errtrace_test.go:") {
+		t.Errorf("chain.0.at = %q", at)
+	}
+	if got.Cause.Type != "errtrace.fieldError" || got.Cause.Fields["field"] != "email" {
+		t.Errorf("cause = %+v", got.Cause)
+	}
+	if len(got.Stack) == 0 {
+		t.Error("stack missing")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	err := Wrap(errors.New("timeout"), "fetching", "url", "http://example.com")
+	if got := fmt.Sprintf("%v", err); got != "fetching: timeout" {
+		t.Errorf("%%v = %q", got)
+	}
+	verbose := fmt.Sprintf("%+v", err)
+	if !strings.Contains(verbose, "TestFormat errtrace_test.go:") || !strings.Contains(verbose, "url=http://example.com") {
+		t.Errorf("%%+v = %q; want location and attributes", verbose)
+	}
+}
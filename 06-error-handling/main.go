@@ -1,9 +1,23 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"time"
+
+	"grok-study-plan/06-error-handling/breaker"
+	"grok-study-plan/06-error-handling/errcatalog"
+	"grok-study-plan/06-error-handling/errtrace"
+	"grok-study-plan/06-error-handling/multierr"
+	"grok-study-plan/06-error-handling/recovery"
+	"grok-study-plan/06-error-handling/retry"
+	"grok-study-plan/06-error-handling/validate"
 )
 
 // Basic error handling
@@ -14,28 +28,18 @@ func divide(a, b float64) (float64, error) {
 	return a / b, nil
 }
 
-// Custom error type
-type ValidationError struct {
-	Field   string
-	Message string
-}
+// Custom error type, shared with the validate package
+type ValidationError = validate.ValidationError
 
-func (e ValidationError) Error() string {
-	return fmt.Sprintf("validation error on field '%s': %s", e.Field, e.Message)
+// userInput declares its checks as struct tags instead of hand-coded ifs
+type userInput struct {
+	Name string `validate:"required"`
+	Age  int    `validate:"min=0,max=150"`
 }
 
 // Function that returns custom error
 func validateUser(name string, age int) error {
-	if name == "" {
-		return ValidationError{Field: "name", Message: "cannot be empty"}
-	}
-	if age < 0 {
-		return ValidationError{Field: "age", Message: "cannot be negative"}
-	}
-	if age > 150 {
-		return ValidationError{Field: "age", Message: "cannot be greater than 150"}
-	}
-	return nil
+	return validate.Struct(userInput{Name: name, Age: age})
 }
 
 // Error wrapping with fmt.Errorf
@@ -84,6 +88,16 @@ func (e NetworkError) Unwrap() error {
 	return e.Err
 }
 
+// LogValue logs the fields instead of the formatted message
+func (e NetworkError) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("op", e.Op),
+		slog.String("url", e.URL),
+		slog.Bool("timeout", e.Timeout),
+		slog.Any("err", e.Err),
+	)
+}
+
 // Function that returns network error
 func fetchURL(url string) error {
 	// Simulate network timeout
@@ -95,13 +109,45 @@ func fetchURL(url string) error {
 	}
 }
 
+// Each layer adds what it knows; errtrace records where, and slog logs it
+// all as structured fields
+func loadProfile(id string) error {
+	err := fetchURL("http://example.com/users/" + id)
+	return errtrace.Wrap(err, "loading profile", "user_id", id)
+}
+
+func handleProfileRequest(id string) error {
+	return errtrace.Wrap(loadProfile(id), "handling request", "route", "/profile", "method", "GET")
+}
+
 // Sentinel errors (predefined error values)
 var (
 	ErrInvalidInput = errors.New("invalid input")
 	ErrNotFound     = errors.New("not found")
 	ErrUnauthorized = errors.New("unauthorized")
+
+	// Returned instead of calling a service whose circuit breaker is open
+	ErrCircuitOpen = breaker.ErrCircuitOpen
 )
 
+// The error catalog gives each error a stable code, HTTP status and exit
+// code; wrapped errors resolve to the same entry
+func init() {
+	errcatalog.Register(ErrInvalidInput, errcatalog.Entry{Code: "invalid_input", HTTPStatus: http.StatusBadRequest, ExitCode: 2})
+	errcatalog.Register(ErrNotFound, errcatalog.Entry{Code: "not_found", HTTPStatus: http.StatusNotFound, ExitCode: 3})
+	errcatalog.Register(ErrUnauthorized, errcatalog.Entry{Code: "unauthorized", HTTPStatus: http.StatusUnauthorized, ExitCode: 4})
+	errcatalog.Register(ErrCircuitOpen, errcatalog.Entry{Code: "circuit_open", Retryable: true, HTTPStatus: http.StatusServiceUnavailable, ExitCode: 6})
+	errcatalog.RegisterAs[ValidationError](errcatalog.Entry{Code: "validation_failed", HTTPStatus: http.StatusUnprocessableEntity, ExitCode: 2})
+
+	// Only timeouts are worth retrying
+	errcatalog.RegisterAsFunc("network_error", func(e NetworkError) errcatalog.Entry {
+		if e.Timeout {
+			return errcatalog.Entry{Retryable: true, HTTPStatus: http.StatusGatewayTimeout, ExitCode: 5}
+		}
+		return errcatalog.Entry{HTTPStatus: http.StatusBadGateway, ExitCode: 5}
+	})
+}
+
 // Function using sentinel errors
 func findUser(id string) error {
 	if id == "" {
@@ -117,13 +163,23 @@ func findUser(id string) error {
 	return nil
 }
 
-// Error handling with defer and recover (not recommended for normal errors)
+// sentinelHint turns a sentinel error into advice for the caller
+func sentinelHint(err error) string {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return "User not found"
+	case errors.Is(err, ErrUnauthorized):
+		return "Access denied"
+	case errors.Is(err, ErrCircuitOpen):
+		return "Service unavailable, try again later"
+	}
+	return ""
+}
+
+// Error handling with defer and recover (not recommended for normal errors).
+// recovery.Recover keeps the panic value and stack in a *recovery.PanicError.
 func riskyOperation() (result int, err error) {
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("operation panicked: %v", r)
-		}
-	}()
+	defer recovery.Recover(&err)
 
 	// Simulate a panic
 	if true {
@@ -135,25 +191,22 @@ func riskyOperation() (result int, err error) {
 
 // Multiple error handling patterns
 func processMultipleItems(items []string) error {
-	var errs []error
+	// Keep at most 10 failures; each one remembers its index and item
+	errs := multierr.New("multiple validation errors", 10)
 
 	for i, item := range items {
 		if err := validateItem(item); err != nil {
-			errs = append(errs, fmt.Errorf("item %d: %w", i, err))
+			errs.Add(i, item, err)
 		}
 	}
 
-	if len(errs) > 0 {
-		// Return combined error
-		return fmt.Errorf("multiple validation errors: %v", errs)
-	}
-
-	return nil
+	// Return combined error, or nil if every item passed
+	return errs.Err()
 }
 
 func validateItem(item string) error {
 	if len(item) < 3 {
-		return errors.New("item too short")
+		return fmt.Errorf("%w: item too short", ErrInvalidInput)
 	}
 	return nil
 }
@@ -206,8 +259,9 @@ func main() {
 	err = validateUser("", 25)
 	if err != nil {
 		fmt.Printf("Validation error: %v\n", err)
-		// Type assertion to access custom fields
-		if valErr, ok := err.(ValidationError); ok {
+		// validate returns every violation, so use errors.As to reach one
+		var valErr ValidationError
+		if errors.As(err, &valErr) {
 			fmt.Printf("Field: %s, Message: %s\n", valErr.Field, valErr.Message)
 		}
 	}
@@ -217,6 +271,15 @@ func main() {
 		fmt.Printf("Validation error: %v\n", err)
 	}
 
+	// Every violation is reported, not just the first
+	err = validateUser("", 200)
+	var violations validate.ValidationErrors
+	if errors.As(err, &violations) {
+		for _, v := range violations {
+			fmt.Printf("  %s (%s): %s\n", v.Field, v.Rule, v.Message)
+		}
+	}
+
 	fmt.Println("\n=== Error Wrapping ===")
 
 	// Error wrapping
@@ -247,24 +310,103 @@ func main() {
 		if err != nil {
 			fmt.Printf("findUser(%s) error: %v\n", id, err)
 			// Check for specific sentinel errors
-			if errors.Is(err, ErrNotFound) {
-				fmt.Println("  -> User not found")
-			} else if errors.Is(err, ErrUnauthorized) {
-				fmt.Println("  -> Access denied")
+			if hint := sentinelHint(err); hint != "" {
+				fmt.Println("  ->", hint)
 			}
 		}
 	}
 
+	fmt.Println("\n=== Retrying Transient Errors ===")
+
+	// The catalog already knows timeouts are worth retrying and
+	// ErrUnauthorized is not, so it doubles as the retry classifier
+	policy := retry.Policy{
+		MaxAttempts:  4,
+		InitialDelay: 10 * time.Millisecond,
+		Classifier:   errcatalog.IsRetryable,
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			fmt.Printf("  attempt %d: %v (retrying)\n", attempt, err)
+		},
+	}
+	ctx := context.Background()
+
+	// Times out twice, then succeeds
+	attempts := 0
+	err = policy.Do(ctx, func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return fetchURL("http://example.com")
+		}
+		return nil
+	})
+	fmt.Printf("Flaky fetch: err=%v after %d attempts\n", err, attempts)
+
+	// Never recovers; the last error is still reachable
+	err = policy.Do(ctx, func(ctx context.Context) error {
+		return fetchURL("http://down.example.com")
+	})
+	var netErr NetworkError
+	fmt.Printf("Down host: gave up=%t, last error was a timeout=%t\n",
+		errors.Is(err, retry.ErrAttemptsExhausted), errors.As(err, &netErr) && netErr.Timeout)
+
+	// Not retryable: returned after the first attempt
+	attempts = 0
+	err = policy.Do(ctx, func(ctx context.Context) error {
+		attempts++
+		return findUser("private")
+	})
+	fmt.Printf("Unauthorized: err=%v after %d attempt\n", err, attempts)
+
+	fmt.Println("\n=== Circuit Breaker ===")
+
+	// After 3 calls with at least half failing, stop calling for 20ms
+	fetchBreaker := breaker.New(breaker.Settings{
+		Name:        "example.com",
+		MinRequests: 3,
+		CoolDown:    20 * time.Millisecond,
+		OnStateChange: func(name string, from, to breaker.State) {
+			fmt.Printf("  breaker %s: %v -> %v\n", name, from, to)
+		},
+	})
+	for i := 1; i <= 5; i++ {
+		err := fetchBreaker.Do(func() error { return fetchURL("http://example.com") })
+		fmt.Printf("call %d: %v\n", i, err)
+		if hint := sentinelHint(err); hint != "" {
+			fmt.Println("  ->", hint)
+		}
+	}
+
+	// Once the cool-down is over a probe goes through; it succeeds, so the
+	// breaker closes
+	time.Sleep(25 * time.Millisecond)
+	err = fetchBreaker.Do(func() error { return nil })
+	fmt.Printf("probe: err=%v\n", err)
+	fmt.Println("metrics:", fetchBreaker.Metrics())
+
 	fmt.Println("\n=== Panic and Recover (Not Recommended) ===")
 
 	// Panic and recover (use sparingly)
 	resultInt, err := riskyOperation()
 	if err != nil {
 		fmt.Printf("Recovered from panic: %v\n", err)
+		var panicErr *recovery.PanicError
+		if errors.As(err, &panicErr) {
+			fmt.Printf("Panic value: %v (stack captured: %t)\n", panicErr.Value, len(panicErr.Stack) > 0)
+		}
 	} else {
 		fmt.Printf("Result: %d\n", resultInt)
 	}
 
+	// Panicking goroutines report back instead of crashing the process
+	errc := recovery.Go(func() error {
+		var m map[string]int
+		m["boom"] = 1 // assignment to nil map panics
+		return nil
+	})
+	if err := <-errc; recovery.IsPanic(err) {
+		fmt.Printf("Goroutine panicked: %v\n", err)
+	}
+
 	fmt.Println("\n=== Multiple Error Handling ===")
 
 	// Multiple errors
@@ -272,6 +414,17 @@ func main() {
 	err = processMultipleItems(items)
 	if err != nil {
 		fmt.Printf("Multiple errors: %v\n", err)
+		fmt.Printf("%+v\n", err)
+
+		// Each child error is still reachable
+		fmt.Println("Is ErrInvalidInput:", errors.Is(err, ErrInvalidInput))
+		var itemErr *multierr.ItemError
+		if errors.As(err, &itemErr) {
+			fmt.Printf("First failure: index=%d key=%q\n", itemErr.Index, itemErr.Key)
+		}
+
+		data, _ := json.Marshal(err)
+		fmt.Printf("JSON: %s\n", data)
 	}
 
 	fmt.Println("\n=== Error Handling Best Practices ===")
@@ -316,4 +469,55 @@ func main() {
 	if errors.As(err, &valErr) {
 		fmt.Printf("errors.As: Field=%s, Message=%s\n", valErr.Field, valErr.Message)
 	}
+
+	fmt.Println("\n=== Structured Error Logging ===")
+
+	// Without errtrace the fields end up in one string
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{} // keep the output stable
+			}
+			return a
+		},
+	}))
+	logger.Error("request failed", "err", fmt.Errorf("handling request: %w", errors.New("loading profile: timeout")))
+
+	// With it, each wrap point keeps its location and attributes, and the
+	// NetworkError at the bottom keeps its fields
+	err = handleProfileRequest("42")
+	logger.Error("request failed", "err", err)
+	fmt.Printf("%+v\n", err)
+
+	fmt.Println("\n=== Error Catalog ===")
+
+	// Every error resolves to a code, retryability, HTTP status and exit code
+	catalogErrors := []error{
+		findUser(""),
+		fmt.Errorf("loading profile: %w", findUser("notfound")),
+		findUser("private"),
+		fetchURL("http://example.com"),
+		validateUser("", 25),
+		processMultipleItems(items),
+		errors.New("disk full"),
+	}
+	for _, err := range catalogErrors {
+		entry := errcatalog.Lookup(err)
+		fmt.Printf("%-17s status=%d exit=%d retryable=%-5v %v\n",
+			entry.Code, entry.HTTPStatus, entry.ExitCode, entry.Retryable, err)
+	}
+
+	// HTTP handlers return errors; the catalog writes the JSON response
+	handler := errcatalog.Middleware(errcatalog.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		if err := findUser(r.URL.Query().Get("id")); err != nil {
+			return fmt.Errorf("user %q: %w", r.URL.Query().Get("id"), err)
+		}
+		fmt.Fprintln(w, `{"ok":true}`)
+		return nil
+	}))
+	for _, id := range []string{"notfound", "private"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", "/users?id="+id, nil))
+		fmt.Printf("GET /users?id=%s -> %d %s", id, rec.Code, rec.Body.String())
+	}
 }
@@ -0,0 +1,211 @@
+// Package multierr collects the failures from processing a batch of items
+// into one error that still answers errors.Is and errors.As for every
+// failure, remembers which item each one came from, and renders as a line
+// of text, an indented list, or JSON.
+//
+//	errs := multierr.New("validating items", 10)
+//	for i, item := range items {
+//	    if err := validate(item); err != nil {
+//	        errs.Add(i, item, err)
+//	    }
+//	}
+//	return errs.Err()
+package multierr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// ItemError is one failure, tagged with the item it belongs to
+type ItemError struct {
+	Index int    // position of the item in the batch
+	Key   string // optional identifier, such as an ID or the item itself
+	Err   error
+}
+
+func (e *ItemError) Error() string {
+	// Joined errors put one error per line; keep the item on a single line
+	msg := strings.ReplaceAll(e.Err.Error(), "\n", "; ")
+	if e.Key == "" {
+		return fmt.Sprintf("item %d: %s", e.Index, msg)
+	}
+	return fmt.Sprintf("item %d (%q): %s", e.Index, e.Key, msg)
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// itemJSON is the JSON form of an ItemError
+type itemJSON struct {
+	Index int    `json:"index"`
+	Key   string `json:"key,omitempty"`
+	Error string `json:"error"`
+}
+
+func (e *ItemError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(itemJSON{Index: e.Index, Key: e.Key, Error: e.Err.Error()})
+}
+
+// Errors collects item failures, keeping up to a cap set by New. It is safe
+// for concurrent use, so workers can share one collector.
+type Errors struct {
+	mu      sync.Mutex
+	message string
+	limit   int
+	items   []*ItemError
+	dropped int
+}
+
+// New returns an empty collector. message summarises the batch, such as
+// "validating items"; limit caps how many failures are kept, with 0
+// meaning no cap. Failures past the cap are only counted.
+func New(message string, limit int) *Errors {
+	return &Errors{message: message, limit: limit}
+}
+
+// Add records err against an item. A nil err is ignored. It returns false
+// once the cap has been reached, so callers may stop early.
+func (e *Errors) Add(index int, key string, err error) bool {
+	if err == nil {
+		return !e.Full()
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.limit > 0 && len(e.items) >= e.limit {
+		e.dropped++
+		return false
+	}
+	e.items = append(e.items, &ItemError{Index: index, Key: key, Err: err})
+	return e.limit == 0 || len(e.items) < e.limit
+}
+
+// Full reports whether the cap has been reached
+func (e *Errors) Full() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.limit > 0 && len(e.items) >= e.limit
+}
+
+// Len is the number of failures seen, including any past the cap
+func (e *Errors) Len() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.items) + e.dropped
+}
+
+// Items returns a copy of the kept failures in the order they were added
+func (e *Errors) Items() []*ItemError {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]*ItemError(nil), e.items...)
+}
+
+// Dropped is the number of failures discarded because of the cap
+func (e *Errors) Dropped() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.dropped
+}
+
+// Err returns e if any failure was recorded and nil otherwise. Return it
+// instead of e itself so a batch without failures yields a nil error.
+func (e *Errors) Err() error {
+	if e.Len() == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error renders every kept failure on one line:
+//
+//	validating items: item 1 ("ab"): too short; item 4 ("x"): too short (and 2 more)
+func (e *Errors) Error() string {
+	items, dropped := e.snapshot()
+
+	var b strings.Builder
+	if e.message != "" {
+		b.WriteString(e.message)
+		b.WriteString(": ")
+	}
+	for i, item := range items {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		b.WriteString(item.Error())
+	}
+	if dropped > 0 {
+		fmt.Fprintf(&b, " (and %d more)", dropped)
+	}
+	return b.String()
+}
+
+// Unwrap exposes each ItemError, so errors.Is and errors.As search them all
+func (e *Errors) Unwrap() []error {
+	items, _ := e.snapshot()
+	errs := make([]error, len(items))
+	for i, item := range items {
+		errs[i] = item
+	}
+	return errs
+}
+
+// Format supports %+v, which prints one failure per line:
+//
+//	validating items (3 errors):
+//	  item 1 ("ab"): too short
+//	  item 4 ("x"): too short
+//	  ... and 1 more
+func (e *Errors) Format(f fmt.State, verb rune) {
+	if verb != 'v' || !f.Flag('+') {
+		fmt.Fprintf(f, fmt.FormatString(f, verb), e.Error())
+		return
+	}
+
+	items, dropped := e.snapshot()
+	message := e.message
+	if message == "" {
+		message = "errors"
+	}
+	fmt.Fprintf(f, "%s (%d %s):", message, len(items)+dropped, plural(len(items)+dropped))
+	for _, item := range items {
+		io.WriteString(f, "\n  "+item.Error())
+	}
+	if dropped > 0 {
+		fmt.Fprintf(f, "\n  ... and %d more", dropped)
+	}
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return "error"
+	}
+	return "errors"
+}
+
+// MarshalJSON renders the collector as
+//
+//	{"message":"validating items","count":3,"dropped":1,"errors":[{"index":1,"key":"ab","error":"too short"},...]}
+func (e *Errors) MarshalJSON() ([]byte, error) {
+	items, dropped := e.snapshot()
+	if items == nil {
+		items = []*ItemError{}
+	}
+	return json.Marshal(struct {
+		Message string       `json:"message,omitempty"`
+		Count   int          `json:"count"`
+		Dropped int          `json:"dropped,omitempty"`
+		Errors  []*ItemError `json:"errors"`
+	}{e.message, len(items) + dropped, dropped, items})
+}
+
+func (e *Errors) snapshot() ([]*ItemError, int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]*ItemError(nil), e.items...), e.dropped
+}
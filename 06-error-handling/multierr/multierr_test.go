@@ -0,0 +1,133 @@
+package multierr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var errTooShort = errors.New("too short")
+
+func collect(items []string, limit int) *Errors {
+	errs := New("validating items", limit)
+	for i, item := range items {
+		if len(item) < 3 {
+			errs.Add(i, item, fmt.Errorf("check length: %w", errTooShort))
+		}
+	}
+	return errs
+}
+
+func TestErrNil(t *testing.T) {
+	errs := collect([]string{"apple", "banana"}, 0)
+	if err := errs.Err(); err != nil {
+		t.Errorf("Err() = %v; want nil", err)
+	}
+	errs.Add(0, "x", nil)
+	if errs.Len() != 0 {
+		t.Errorf("nil errors should be ignored, Len = %d", errs.Len())
+	}
+}
+
+func TestIsAs(t *testing.T) {
+	err := collect([]string{"apple", "ab", "cherry", "x"}, 0).Err()
+
+	if !errors.Is(err, errTooShort) {
+		t.Error("errors.Is should find the wrapped sentinel")
+	}
+	var item *ItemError
+	if !errors.As(err, &item) || item.Index != 1 || item.Key != "ab" {
+		t.Errorf("errors.As = %+v; want the first failure (index 1, key ab)", item)
+	}
+
+	// Joining with other errors keeps every child reachable
+	joined := errors.Join(fs.ErrNotExist, err)
+	if !errors.Is(joined, errTooShort) || !errors.Is(joined, fs.ErrNotExist) {
+		t.Error("errors.Join lost a child")
+	}
+	var multi *Errors
+	if !errors.As(joined, &multi) || multi.Len() != 2 {
+		t.Errorf("errors.As(*Errors) through Join = %v", multi)
+	}
+}
+
+func TestAddJoinedError(t *testing.T) {
+	errs := New("", 0)
+	errs.Add(3, "", errors.Join(errTooShort, fs.ErrPermission))
+
+	if !errors.Is(errs, fs.ErrPermission) {
+		t.Error("errors inside a joined item error should be found")
+	}
+	if got, want := errs.Error(), "item 3: too short; permission denied"; got != want {
+		t.Errorf("Error() = %q; want %q", got, want)
+	}
+}
+
+func TestLimit(t *testing.T) {
+	errs := New("batch", 2)
+	results := []bool{
+		errs.Add(0, "a", errTooShort),
+		errs.Add(1, "b", errTooShort),
+		errs.Add(2, "c", errTooShort),
+	}
+	if results[0] != true || results[1] != false || results[2] != false {
+		t.Errorf("Add results = %v; want [true false false]", results)
+	}
+	if !errs.Full() || errs.Len() != 3 || errs.Dropped() != 1 || len(errs.Items()) != 2 {
+		t.Errorf("Full=%v Len=%d Dropped=%d Items=%d", errs.Full(), errs.Len(), errs.Dropped(), len(errs.Items()))
+	}
+	if !strings.HasSuffix(errs.Error(), "(and 1 more)") {
+		t.Errorf("Error() = %q; want the dropped count", errs.Error())
+	}
+}
+
+func TestFormats(t *testing.T) {
+	errs := New("validating items", 2)
+	errs.Add(1, "ab", errTooShort)
+	errs.Add(3, "", errTooShort)
+	errs.Add(4, "x", errTooShort)
+
+	testCases := []struct {
+		format string
+		want   string
+	}{
+		{"%v", `validating items: item 1 ("ab"): too short; item 3: too short (and 1 more)`},
+		{"%s", `validating items: item 1 ("ab"): too short; item 3: too short (and 1 more)`},
+		{"%+v", "validating items (3 errors):\n  item 1 (\"ab\"): too short\n  item 3: too short\n  ... and 1 more"},
+	}
+	for _, tc := range testCases {
+		if got := fmt.Sprintf(tc.format, errs); got != tc.want {
+			t.Errorf("Sprintf(%q) =\n%s\nwant\n%s", tc.format, got, tc.want)
+		}
+	}
+
+	data, err := json.Marshal(errs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"message":"validating items","count":3,"dropped":1,"errors":[{"index":1,"key":"ab","error":"too short"},{"index":3,"error":"too short"}]}`
+	if string(data) != want {
+		t.Errorf("JSON =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestConcurrentAdd(t *testing.T) {
+	errs := New("", 50)
+	var wg sync.WaitGroup
+	for w := range 10 {
+		wg.Go(func() {
+			for i := range 10 {
+				errs.Add(w*10+i, "", errTooShort)
+			}
+		})
+	}
+	wg.Wait()
+
+	if errs.Len() != 100 || len(errs.Items()) != 50 || errs.Dropped() != 50 {
+		t.Errorf("Len=%d Items=%d Dropped=%d; want 100, 50, 50", errs.Len(), len(errs.Items()), errs.Dropped())
+	}
+}
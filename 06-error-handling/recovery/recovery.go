@@ -0,0 +1,129 @@
+// Package recovery turns panics into ordinary errors that keep the panic
+// value and the stack trace of where the panic happened.
+package recovery
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"sync"
+)
+
+// PanicError is returned in place of a recovered panic
+type PanicError struct {
+	Value any    // the value passed to panic
+	Stack []byte // goroutine stack captured while recovering
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap exposes the panic value when it is itself an error, so
+// errors.Is(err, io.EOF) works for panic(io.EOF)
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Format prints the stack trace as well when used with %+v
+func (e *PanicError) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		fmt.Fprintf(s, "%s\n%s", e.Error(), e.Stack)
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		io.WriteString(s, e.Error())
+	}
+}
+
+// New wraps a recovered value; it returns nil when v is nil
+func New(v any) *PanicError {
+	if v == nil {
+		return nil
+	}
+	return &PanicError{Value: v, Stack: debug.Stack()}
+}
+
+// Recover converts a panic into an error stored in *errp. It must be
+// deferred directly:
+//
+//	func work() (err error) {
+//	    defer recovery.Recover(&err)
+//	    ...
+//	}
+//
+// If the function already returned an error, the panic is joined to it.
+func Recover(errp *error) {
+	if r := recover(); r != nil {
+		if *errp == nil {
+			*errp = New(r)
+			return
+		}
+		*errp = errors.Join(*errp, New(r))
+	}
+}
+
+// Call runs f and returns its error, or a *PanicError if f panics
+func Call(f func() error) (err error) {
+	defer Recover(&err)
+	return f()
+}
+
+// CallValue is like Call for functions that also return a value
+func CallValue[T any](f func() (T, error)) (result T, err error) {
+	defer Recover(&err)
+	return f()
+}
+
+// IsPanic reports whether err (or anything it wraps) is a recovered panic
+func IsPanic(err error) bool {
+	var pe *PanicError
+	return errors.As(err, &pe)
+}
+
+// Go runs f in a new goroutine. The returned channel receives f's error,
+// or a *PanicError if f panicked, and is then closed. The process never
+// crashes because of a panic inside f.
+func Go(f func() error) <-chan error {
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		errc <- Call(f)
+	}()
+	return errc
+}
+
+// Group runs goroutines and collects their errors, including panics.
+// The zero value is ready to use.
+type Group struct {
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+// Go starts f in its own goroutine
+func (g *Group) Go(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := Call(f); err != nil {
+			g.mu.Lock()
+			g.errs = append(g.errs, err)
+			g.mu.Unlock()
+		}
+	}()
+}
+
+// Wait blocks until every goroutine finishes and returns all of their
+// errors joined together, or nil if none failed
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return errors.Join(g.errs...)
+}
@@ -0,0 +1,238 @@
+package recovery
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRecoverCapturesValueAndStack(t *testing.T) {
+	panicky := func() (err error) {
+		defer Recover(&err)
+		panic("boom")
+	}
+
+	err := panicky()
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		t.Fatalf("err = %T %v; want *PanicError", err, err)
+	}
+	if pe.Value != "boom" {
+		t.Errorf("Value = %v; want boom", pe.Value)
+	}
+	if len(pe.Stack) == 0 {
+		t.Fatal("Stack is empty")
+	}
+	// The stack is taken while unwinding, so it includes the panicking function
+	if !strings.Contains(string(pe.Stack), "TestRecoverCapturesValueAndStack") {
+		t.Errorf("Stack does not mention the panicking function:\n%s", pe.Stack)
+	}
+	if got := err.Error(); got != "panic: boom" {
+		t.Errorf("Error() = %q; want %q", got, "panic: boom")
+	}
+	if got := fmt.Sprintf("%+v", err); !strings.HasPrefix(got, "panic: boom\n") || !strings.Contains(got, "goroutine") {
+		t.Errorf("%%+v = %q; want the message followed by the stack", got)
+	}
+}
+
+func TestRecoverWithoutPanic(t *testing.T) {
+	errWant := errors.New("plain failure")
+	fn := func() (err error) {
+		defer Recover(&err)
+		return errWant
+	}
+	if err := fn(); err != errWant {
+		t.Errorf("err = %v; want the returned error unchanged", err)
+	}
+}
+
+func TestRecoverJoinsExistingError(t *testing.T) {
+	errFirst := errors.New("first")
+	fn := func() (err error) {
+		defer Recover(&err)
+		defer func() { err = errFirst }() // runs before Recover, while panicking
+		panic("second")
+	}
+
+	err := fn()
+	if !errors.Is(err, errFirst) || !IsPanic(err) {
+		t.Errorf("err = %v; want both the returned error and the panic", err)
+	}
+}
+
+func TestErrorsAsThroughWrapping(t *testing.T) {
+	err := Call(func() error { panic(io.ErrUnexpectedEOF) })
+	wrapped := fmt.Errorf("loading config: %w", fmt.Errorf("reading file: %w", err))
+
+	var pe *PanicError
+	if !errors.As(wrapped, &pe) {
+		t.Fatalf("errors.As(%v) found no *PanicError", wrapped)
+	}
+	if pe.Value != io.ErrUnexpectedEOF {
+		t.Errorf("Value = %v; want io.ErrUnexpectedEOF", pe.Value)
+	}
+	// A panic with an error value unwraps to that error
+	if !errors.Is(wrapped, io.ErrUnexpectedEOF) {
+		t.Error("errors.Is(wrapped, io.ErrUnexpectedEOF) = false; want true")
+	}
+	if !IsPanic(wrapped) {
+		t.Error("IsPanic(wrapped) = false; want true")
+	}
+	if IsPanic(errors.New("ordinary")) || IsPanic(nil) {
+		t.Error("IsPanic reports an ordinary error or nil as a panic")
+	}
+}
+
+func TestCall(t *testing.T) {
+	testCases := []struct {
+		name      string
+		fn        func() error
+		wantPanic bool
+		wantErr   bool
+	}{
+		{"success", func() error { return nil }, false, false},
+		{"error", func() error { return errors.New("failed") }, false, true},
+		{"panic string", func() error { panic("bad") }, true, true},
+		{"runtime error", func() error {
+			var m map[string]int
+			m["x"] = 1
+			return nil
+		}, true, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Call(tc.fn)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Call() error = %v; want error %t", err, tc.wantErr)
+			}
+			if IsPanic(err) != tc.wantPanic {
+				t.Errorf("IsPanic(%v) = %t; want %t", err, IsPanic(err), tc.wantPanic)
+			}
+		})
+	}
+}
+
+func TestCallValue(t *testing.T) {
+	got, err := CallValue(func() (int, error) { return 42, nil })
+	if got != 42 || err != nil {
+		t.Errorf("CallValue = %d, %v; want 42, nil", got, err)
+	}
+
+	got, err = CallValue(func() (int, error) {
+		var s []int
+		return s[3], nil
+	})
+	if got != 0 || !IsPanic(err) {
+		t.Errorf("CallValue = %d, %v; want 0 and a panic error", got, err)
+	}
+}
+
+func TestNewNil(t *testing.T) {
+	if pe := New(nil); pe != nil {
+		t.Errorf("New(nil) = %v; want nil", pe)
+	}
+}
+
+// receiveAll drains errc, failing the test if it is not closed in time
+func receiveAll(t *testing.T, errc <-chan error) []error {
+	t.Helper()
+	var errs []error
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case err, ok := <-errc:
+			if !ok {
+				return errs
+			}
+			errs = append(errs, err)
+		case <-timeout:
+			t.Fatal("channel was not closed")
+		}
+	}
+}
+
+func TestGo(t *testing.T) {
+	errFailed := errors.New("failed")
+	testCases := []struct {
+		name  string
+		fn    func() error
+		check func(error) bool
+	}{
+		{"clean return", func() error { return nil }, func(err error) bool { return err == nil }},
+		{"error", func() error { return errFailed }, func(err error) bool { return err == errFailed }},
+		{"panic", func() error { panic("in goroutine") }, IsPanic},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := receiveAll(t, Go(tc.fn))
+			if len(errs) != 1 {
+				t.Fatalf("received %d values; want exactly 1", len(errs))
+			}
+			if !tc.check(errs[0]) {
+				t.Errorf("received %v", errs[0])
+			}
+		})
+	}
+}
+
+func TestGroupSurvivesPanickingMember(t *testing.T) {
+	var g Group
+	var finished atomic.Int32
+	errFailed := errors.New("failed")
+
+	for i := range 20 {
+		g.Go(func() error {
+			switch i % 4 {
+			case 0:
+				panic(fmt.Sprintf("member %d", i))
+			case 1:
+				return errFailed
+			}
+			finished.Add(1)
+			return nil
+		})
+	}
+
+	err := g.Wait()
+	if finished.Load() != 10 {
+		t.Errorf("%d members finished cleanly; want 10", finished.Load())
+	}
+	if !IsPanic(err) || !errors.Is(err, errFailed) {
+		t.Errorf("Wait() = %v; want the panics and the errors", err)
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("Wait() = %T; want a joined error", err)
+	}
+	panics := 0
+	for _, e := range joined.Unwrap() {
+		if IsPanic(e) {
+			panics++
+		}
+	}
+	if got := len(joined.Unwrap()); got != 10 || panics != 5 {
+		t.Errorf("Wait() joined %d errors with %d panics; want 10 with 5", got, panics)
+	}
+}
+
+func TestGroupNoErrors(t *testing.T) {
+	var g Group
+	for range 5 {
+		g.Go(func() error { return nil })
+	}
+	if err := g.Wait(); err != nil {
+		t.Errorf("Wait() = %v; want nil", err)
+	}
+	// The zero Group with nothing started also waits cleanly
+	var empty Group
+	if err := empty.Wait(); err != nil {
+		t.Errorf("empty Wait() = %v; want nil", err)
+	}
+}
@@ -0,0 +1,241 @@
+// Package retry re-runs failing operations with exponential backoff and
+// full jitter, within limits on attempts and elapsed time, until the
+// context is cancelled or a classifier says the error is not worth
+// retrying.
+//
+//	policy := retry.Policy{MaxAttempts: 5, Classifier: errcatalog.IsRetryable}
+//	err := policy.Do(ctx, func(ctx context.Context) error {
+//	    return client.Fetch(ctx, url)
+//	})
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Defaults for zero Policy fields
+const (
+	DefaultMaxAttempts  = 3
+	DefaultInitialDelay = 100 * time.Millisecond
+	DefaultMaxDelay     = 10 * time.Second
+	DefaultMultiplier   = 2.0
+)
+
+// Classifier reports whether an operation that failed with err may succeed
+// if tried again
+type Classifier func(err error) bool
+
+// Policy configures Do. The zero value makes up to DefaultMaxAttempts
+// attempts with delays starting at DefaultInitialDelay.
+type Policy struct {
+	MaxAttempts  int           // total attempts including the first; negative means no limit
+	MaxElapsed   time.Duration // give up rather than sleep past this; 0 means no limit
+	InitialDelay time.Duration // upper bound of the first delay
+	MaxDelay     time.Duration // cap on the upper bound of any delay
+	Multiplier   float64       // growth of the upper bound per attempt
+
+	// Classifier decides which errors are retried; the default is
+	// DefaultClassifier. Errors wrapped with Permanent are never retried.
+	Classifier Classifier
+
+	// OnRetry, if set, is called before each sleep with the attempt that
+	// just failed (starting at 1), its error and the chosen delay
+	OnRetry func(attempt int, err error, delay time.Duration)
+
+	// Clock and Rand replace time and randomness, so tests run instantly
+	// and deterministically. Rand returns a value in [0, 1).
+	Clock Clock
+	Rand  func() float64
+}
+
+// Clock is the source of time for a Policy
+type Clock interface {
+	Now() time.Time
+	// Sleep waits for d, returning early with ctx.Err() if ctx is done
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// SystemClock is the real clock, used when Policy.Clock is nil
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) Sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Reasons for giving up, reachable with errors.Is on an *Error
+var (
+	ErrAttemptsExhausted = errors.New("retry: attempts exhausted")
+	ErrTimeExhausted     = errors.New("retry: time budget exhausted")
+)
+
+// Error is returned when Do gives up on a retryable error. It unwraps to
+// both the reason (ErrAttemptsExhausted, ErrTimeExhausted or the context's
+// error) and the last error from the operation.
+type Error struct {
+	Attempts int
+	Elapsed  time.Duration
+	Reason   error
+	Last     error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v after %d attempt%s in %v: %v",
+		e.Reason, e.Attempts, plural(e.Attempts), e.Elapsed.Round(time.Millisecond), e.Last)
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Reason, e.Last}
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
+
+// permanentError marks an error that must not be retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that Do returns it at once, whatever the classifier
+// says. Permanent(nil) is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was wrapped with Permanent
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// DefaultClassifier retries everything except context cancellation and
+// deadlines, which come from the caller rather than the operation
+func DefaultClassifier(err error) bool {
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// TimeoutClassifier retries only errors with a Timeout() bool method that
+// returns true, such as net.Error timeouts
+func TimeoutClassifier(err error) bool {
+	var t interface{ Timeout() bool }
+	return errors.As(err, &t) && t.Timeout()
+}
+
+func (p Policy) withDefaults() Policy {
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = DefaultMaxAttempts
+	}
+	if p.InitialDelay <= 0 {
+		p.InitialDelay = DefaultInitialDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = DefaultMaxDelay
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = DefaultMultiplier
+	}
+	if p.Classifier == nil {
+		p.Classifier = DefaultClassifier
+	}
+	if p.Clock == nil {
+		p.Clock = SystemClock
+	}
+	if p.Rand == nil {
+		p.Rand = rand.Float64
+	}
+	return p
+}
+
+// Backoff returns the upper bound of the delay after the given failed
+// attempt (starting at 1): InitialDelay * Multiplier^(attempt-1), capped at
+// MaxDelay. With full jitter the actual delay is uniform in [0, Backoff).
+func (p Policy) Backoff(attempt int) time.Duration {
+	p = p.withDefaults()
+	bound := float64(p.InitialDelay) * math.Pow(p.Multiplier, float64(attempt-1))
+	if bound > float64(p.MaxDelay) || math.IsInf(bound, 0) {
+		return p.MaxDelay
+	}
+	return time.Duration(bound)
+}
+
+// Do calls fn until it succeeds, returns an error that is not retryable,
+// or a limit is reached. A non-retryable error is returned as it is; when
+// Do gives up on a retryable one it returns an *Error.
+func (p Policy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	_, err := DoValue(ctx, p, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// DoValue is Do for operations that return a value
+func DoValue[T any](ctx context.Context, p Policy, fn func(ctx context.Context) (T, error)) (T, error) {
+	p = p.withDefaults()
+	start := p.Clock.Now()
+
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			var zero T
+			return zero, &Error{Attempts: attempt - 1, Elapsed: p.Clock.Now().Sub(start), Reason: err, Last: err}
+		}
+
+		result, err := fn(ctx)
+		if err == nil {
+			return result, nil
+		}
+		if IsPermanent(err) || !p.Classifier(err) {
+			return result, err
+		}
+
+		giveUp := func(reason error) (T, error) {
+			var zero T
+			return zero, &Error{Attempts: attempt, Elapsed: p.Clock.Now().Sub(start), Reason: reason, Last: err}
+		}
+
+		if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+			return giveUp(ErrAttemptsExhausted)
+		}
+
+		delay := time.Duration(p.Rand() * float64(p.Backoff(attempt)))
+		if p.MaxElapsed > 0 && p.Clock.Now().Sub(start)+delay > p.MaxElapsed {
+			return giveUp(ErrTimeExhausted)
+		}
+
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, err, delay)
+		}
+		if sleepErr := p.Clock.Sleep(ctx, delay); sleepErr != nil {
+			return giveUp(sleepErr)
+		}
+	}
+}
+
+// Do runs fn with the zero Policy
+func Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return Policy{}.Do(ctx, fn)
+}
@@ -0,0 +1,277 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock advances instantly when slept on and records every sleep
+type fakeClock struct {
+	now    time.Time
+	sleeps []time.Duration
+	// onSleep runs before each sleep completes, for cancelling mid-wait
+	onSleep func()
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	c.sleeps = append(c.sleeps, d)
+	if c.onSleep != nil {
+		c.onSleep()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.now = c.now.Add(d)
+	return nil
+}
+
+// half always picks the middle of the jitter range
+func half() float64 { return 0.5 }
+
+type timeoutError struct{ timeout bool }
+
+func (e timeoutError) Error() string { return "i/o timeout" }
+func (e timeoutError) Timeout() bool { return e.timeout }
+
+var errUnauthorized = errors.New("unauthorized")
+
+// failing returns an operation that fails n times with err, then succeeds
+func failing(n int, err error, calls *int) func(context.Context) error {
+	return func(context.Context) error {
+		*calls++
+		if *calls <= n {
+			return err
+		}
+		return nil
+	}
+}
+
+func TestSucceedsAfterRetries(t *testing.T) {
+	clock := &fakeClock{}
+	var calls int
+	p := Policy{MaxAttempts: 5, InitialDelay: 100 * time.Millisecond, Clock: clock, Rand: half}
+
+	if err := p.Do(context.Background(), failing(3, timeoutError{true}, &calls)); err != nil {
+		t.Fatalf("Do = %v; want nil", err)
+	}
+	if calls != 4 {
+		t.Errorf("calls = %d; want 4", calls)
+	}
+	want := []time.Duration{50 * time.Millisecond, 100 * time.Millisecond, 200 * time.Millisecond}
+	if len(clock.sleeps) != len(want) {
+		t.Fatalf("sleeps = %v; want %v", clock.sleeps, want)
+	}
+	for i := range want {
+		if clock.sleeps[i] != want[i] {
+			t.Errorf("sleep %d = %v; want %v", i, clock.sleeps[i], want[i])
+		}
+	}
+}
+
+func TestAttemptsExhausted(t *testing.T) {
+	clock := &fakeClock{}
+	var calls int
+	cause := timeoutError{true}
+	p := Policy{MaxAttempts: 3, Clock: clock, Rand: half}
+
+	err := p.Do(context.Background(), failing(10, cause, &calls))
+	if calls != 3 {
+		t.Errorf("calls = %d; want 3", calls)
+	}
+	if !errors.Is(err, ErrAttemptsExhausted) {
+		t.Errorf("errors.Is(err, ErrAttemptsExhausted) = false for %v", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("the last error should be reachable from %v", err)
+	}
+	var retryErr *Error
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 3 {
+		t.Errorf("errors.As = %+v; want 3 attempts", retryErr)
+	}
+	if len(clock.sleeps) != 2 {
+		t.Errorf("sleeps = %v; want 2 (none after the last attempt)", clock.sleeps)
+	}
+}
+
+func TestMaxElapsed(t *testing.T) {
+	clock := &fakeClock{}
+	var calls int
+	// Delays are 50ms, 100ms, 200ms, ...; the third would end past 300ms
+	p := Policy{
+		MaxAttempts:  -1,
+		MaxElapsed:   300 * time.Millisecond,
+		InitialDelay: 100 * time.Millisecond,
+		Clock:        clock,
+		Rand:         half,
+	}
+
+	err := p.Do(context.Background(), failing(100, timeoutError{true}, &calls))
+	if !errors.Is(err, ErrTimeExhausted) {
+		t.Fatalf("Do = %v; want ErrTimeExhausted", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d; want 3", calls)
+	}
+	if elapsed := clock.now.Sub(time.Time{}); elapsed > p.MaxElapsed {
+		t.Errorf("slept %v; should stay within %v", elapsed, p.MaxElapsed)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := Policy{InitialDelay: time.Second, MaxDelay: 5 * time.Second, Multiplier: 2}
+	testCases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{1000, 5 * time.Second},
+	}
+	for _, tc := range testCases {
+		if got := p.Backoff(tc.attempt); got != tc.want {
+			t.Errorf("Backoff(%d) = %v; want %v", tc.attempt, got, tc.want)
+		}
+	}
+}
+
+func TestFullJitter(t *testing.T) {
+	testCases := []struct {
+		name string
+		rand float64
+		want time.Duration
+	}{
+		{"low", 0, 0},
+		{"mid", 0.25, 25 * time.Millisecond},
+		{"high", 0.99, 99 * time.Millisecond},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := &fakeClock{}
+			var calls int
+			p := Policy{MaxAttempts: 2, Clock: clock, Rand: func() float64 { return tc.rand }}
+			p.Do(context.Background(), failing(1, errors.New("flaky"), &calls))
+			if len(clock.sleeps) != 1 || clock.sleeps[0] != tc.want {
+				t.Errorf("sleeps = %v; want [%v]", clock.sleeps, tc.want)
+			}
+		})
+	}
+}
+
+func TestClassifier(t *testing.T) {
+	classify := func(err error) bool {
+		return !errors.Is(err, errUnauthorized) && TimeoutClassifier(err)
+	}
+	testCases := []struct {
+		name      string
+		err       error
+		wantCalls int
+	}{
+		{"timeout", timeoutError{true}, 3},
+		{"not a timeout", timeoutError{false}, 1},
+		{"unauthorized", errUnauthorized, 1},
+		{"permanent timeout", Permanent(timeoutError{true}), 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int
+			p := Policy{MaxAttempts: 3, Classifier: classify, Clock: &fakeClock{}, Rand: half}
+			err := p.Do(context.Background(), failing(10, tc.err, &calls))
+			if calls != tc.wantCalls {
+				t.Errorf("calls = %d; want %d", calls, tc.wantCalls)
+			}
+			if !errors.Is(err, tc.err) && !IsPermanent(tc.err) {
+				t.Errorf("Do = %v; want %v", err, tc.err)
+			}
+			if tc.wantCalls == 1 {
+				var retryErr *Error
+				if errors.As(err, &retryErr) {
+					t.Errorf("non-retryable error should be returned as is, got %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultClassifier(t *testing.T) {
+	testCases := []struct {
+		err  error
+		want bool
+	}{
+		{errors.New("flaky"), true},
+		{timeoutError{true}, true},
+		{context.Canceled, false},
+		{context.DeadlineExceeded, false},
+	}
+	for _, tc := range testCases {
+		if got := DefaultClassifier(tc.err); got != tc.want {
+			t.Errorf("DefaultClassifier(%v) = %v; want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestCancelDuringSleep(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clock := &fakeClock{onSleep: cancel}
+	var calls int
+	p := Policy{MaxAttempts: 5, Clock: clock, Rand: half}
+
+	err := p.Do(ctx, failing(10, timeoutError{true}, &calls))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Do = %v; want context.Canceled", err)
+	}
+	if !errors.Is(err, timeoutError{true}) {
+		t.Errorf("the last error should still be reachable from %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d; want 1", calls)
+	}
+}
+
+func TestCancelledBeforeStart(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var calls int
+	err := Policy{Clock: &fakeClock{}}.Do(ctx, failing(0, nil, &calls))
+	if !errors.Is(err, context.Canceled) || calls != 0 {
+		t.Errorf("Do = %v after %d calls; want context.Canceled and no calls", err, calls)
+	}
+}
+
+func TestSystemClockCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := SystemClock.Sleep(ctx, time.Hour); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Sleep = %v; want context.DeadlineExceeded", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Sleep should return as soon as the context is done")
+	}
+}
+
+func TestDoValue(t *testing.T) {
+	var calls int
+	p := Policy{OnRetry: func(attempt int, err error, delay time.Duration) {
+		if attempt != calls {
+			t.Errorf("OnRetry attempt = %d; want %d", attempt, calls)
+		}
+	}, Clock: &fakeClock{}, Rand: half}
+
+	got, err := DoValue(context.Background(), p, func(context.Context) (string, error) {
+		calls++
+		if calls < 3 {
+			return "", errors.New("flaky")
+		}
+		return "ok", nil
+	})
+	if err != nil || got != "ok" {
+		t.Errorf("DoValue = %q, %v; want ok, nil", got, err)
+	}
+}
@@ -0,0 +1,135 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var builtinRules = map[string]Rule{
+	"required": required,
+	"min":      minRule,
+	"max":      maxRule,
+	"len":      lenRule,
+	"email":    email,
+	"oneof":    oneOf,
+	"pattern":  pattern,
+}
+
+func required(v reflect.Value, _ string) error {
+	if !v.IsValid() || v.IsZero() {
+		return errors.New("is required")
+	}
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0 {
+		return errors.New("is required")
+	}
+	return nil
+}
+
+// measure returns the number a size rule compares against: the value for
+// numbers, and the length for strings, slices, arrays and maps
+func measure(v reflect.Value) (value float64, isLength bool, ok bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), false, true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), true, true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), true, true
+	}
+	return 0, false, false
+}
+
+// sizeRule builds min, max and len from a comparison
+func sizeRule(name string, fails func(got, limit float64) bool, verb string) Rule {
+	return func(v reflect.Value, param string) error {
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return fmt.Errorf("%w: %s=%q is not a number", ErrInvalidRule, name, param)
+		}
+		got, isLength, ok := measure(v)
+		if !ok {
+			return fmt.Errorf("%w: %s does not apply to %s", ErrInvalidRule, name, v.Kind())
+		}
+		if !fails(got, limit) {
+			return nil
+		}
+		if isLength {
+			return fmt.Errorf("length must be %s %s", verb, param)
+		}
+		return fmt.Errorf("must be %s %s", verb, param)
+	}
+}
+
+var (
+	minRule = sizeRule("min", func(got, limit float64) bool { return got < limit }, "at least")
+	maxRule = sizeRule("max", func(got, limit float64) bool { return got > limit }, "at most")
+	lenRule = sizeRule("len", func(got, limit float64) bool { return got != limit }, "exactly")
+)
+
+func email(v reflect.Value, _ string) error {
+	if v.Kind() != reflect.String {
+		return fmt.Errorf("%w: email does not apply to %s", ErrInvalidRule, v.Kind())
+	}
+	s := v.String()
+	if s == "" {
+		return nil // combine with "required" to reject empty values
+	}
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return errors.New("must be a valid email address")
+	}
+	return nil
+}
+
+// oneOf takes space-separated choices: oneof=admin user guest
+func oneOf(v reflect.Value, param string) error {
+	choices := strings.Fields(param)
+	if len(choices) == 0 {
+		return fmt.Errorf("%w: oneof needs at least one choice", ErrInvalidRule)
+	}
+	got := fmt.Sprint(v.Interface())
+	for _, c := range choices {
+		if got == c {
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of [%s]", strings.Join(choices, " "))
+}
+
+var patternCache sync.Map // string -> *regexp.Regexp
+
+// pattern matches strings against a regular expression. Tags are split on
+// commas, so the expression itself cannot contain one.
+func pattern(v reflect.Value, param string) error {
+	if v.Kind() != reflect.String {
+		return fmt.Errorf("%w: pattern does not apply to %s", ErrInvalidRule, v.Kind())
+	}
+
+	var re *regexp.Regexp
+	if cached, ok := patternCache.Load(param); ok {
+		re = cached.(*regexp.Regexp)
+	} else {
+		compiled, err := regexp.Compile(param)
+		if err != nil {
+			return fmt.Errorf("%w: pattern %q: %v", ErrInvalidRule, param, err)
+		}
+		patternCache.Store(param, compiled)
+		re = compiled
+	}
+
+	if s := v.String(); s != "" && !re.MatchString(s) {
+		return fmt.Errorf("must match %s", param)
+	}
+	return nil
+}
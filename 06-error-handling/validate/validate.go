@@ -0,0 +1,301 @@
+// Package validate checks struct fields against rules declared in
+// `validate:"..."` struct tags, for example:
+//
+//	type User struct {
+//	    Name  string `validate:"required"`
+//	    Age   int    `validate:"min=0,max=150"`
+//	    Email string `validate:"required,email"`
+//	}
+//
+// Nested, embedded and pointer-to-struct fields are walked recursively, as
+// are slices, arrays and maps of structs. Every violation is reported with
+// a path such as "Address.City" or "Items[2].Name".
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ValidationError describes one field that failed a rule
+type ValidationError struct {
+	Field   string
+	Message string
+	Rule    string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("validation error on field '%s': %s", e.Field, e.Message)
+}
+
+// LogValue keeps the fields apart when the error is logged with slog
+func (e ValidationError) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("field", e.Field),
+		slog.String("rule", e.Rule),
+		slog.String("message", e.Message),
+	)
+}
+
+// ValidationErrors collects every violation found in one value. It unwraps
+// to its elements, so errors.As(err, &ValidationError{}) finds the first.
+type ValidationErrors []ValidationError
+
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (errs ValidationErrors) Unwrap() []error {
+	wrapped := make([]error, len(errs))
+	for i, e := range errs {
+		wrapped[i] = e
+	}
+	return wrapped
+}
+
+// Fields returns the paths of the failing fields in order
+func (errs ValidationErrors) Fields() []string {
+	fields := make([]string, len(errs))
+	for i, e := range errs {
+		fields[i] = e.Field
+	}
+	return fields
+}
+
+// ErrInvalidRule is wrapped by errors caused by a malformed tag rather than
+// by invalid data, such as an unknown rule name or min=abc
+var ErrInvalidRule = errors.New("validate: invalid rule")
+
+// Rule checks a single field value. param is the text after '=' in the tag
+// (empty if there is none). Pointers have already been dereferenced; nil
+// pointers only reach the "required" rule. A failing rule returns an error
+// whose text becomes the violation message; returning an error that wraps
+// ErrInvalidRule aborts validation instead.
+type Rule func(v reflect.Value, param string) error
+
+// Validator holds a set of named rules. The zero value is not usable;
+// create one with New.
+type Validator struct {
+	mu    sync.RWMutex
+	rules map[string]Rule
+	cache sync.Map // reflect.Type -> []fieldSpec
+}
+
+// New returns a validator with the built-in rules registered
+func New() *Validator {
+	v := &Validator{rules: make(map[string]Rule)}
+	for name, rule := range builtinRules {
+		v.rules[name] = rule
+	}
+	return v
+}
+
+// RegisterRule adds or replaces a rule
+func (v *Validator) RegisterRule(name string, rule Rule) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.rules[name] = rule
+}
+
+func (v *Validator) rule(name string) (Rule, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	r, ok := v.rules[name]
+	return r, ok
+}
+
+var defaultValidator = New()
+
+// RegisterRule adds a rule to the package-level validator
+func RegisterRule(name string, rule Rule) {
+	defaultValidator.RegisterRule(name, rule)
+}
+
+// Struct validates s with the package-level validator
+func Struct(s any) error {
+	return defaultValidator.Struct(s)
+}
+
+// tagRule is one "name=param" entry from a tag
+type tagRule struct {
+	name  string
+	param string
+}
+
+type fieldSpec struct {
+	index int
+	name  string
+	rules []tagRule
+}
+
+func parseTag(tag string) []tagRule {
+	var rules []tagRule
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, param, _ := strings.Cut(part, "=")
+		rules = append(rules, tagRule{name: strings.TrimSpace(name), param: strings.TrimSpace(param)})
+	}
+	return rules
+}
+
+// fields returns the exported fields of t with their parsed rules
+func (v *Validator) fields(t reflect.Type) []fieldSpec {
+	if cached, ok := v.cache.Load(t); ok {
+		return cached.([]fieldSpec)
+	}
+
+	var specs []fieldSpec
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("validate")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		specs = append(specs, fieldSpec{
+			index: i,
+			name:  f.Name,
+			rules: parseTag(tag),
+		})
+	}
+
+	v.cache.Store(t, specs)
+	return specs
+}
+
+// Struct validates s, which must be a struct or a pointer to one. It returns
+// nil, a ValidationErrors listing every violation, or an error wrapping
+// ErrInvalidRule if a tag is malformed.
+func (v *Validator) Struct(s any) error {
+	rv := reflect.ValueOf(s)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return fmt.Errorf("%w: validate.Struct called with a nil pointer", ErrInvalidRule)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: validate.Struct expects a struct, got %s", ErrInvalidRule, rv.Kind())
+	}
+
+	w := walker{v: v, visited: make(map[ref]bool)}
+	w.walkStruct(rv, "")
+	if w.err != nil {
+		return w.err
+	}
+	if len(w.errs) == 0 {
+		return nil
+	}
+	return w.errs
+}
+
+// walker carries the state of one Struct call
+type walker struct {
+	v       *Validator
+	errs    ValidationErrors
+	err     error
+	visited map[ref]bool // struct pointers already walked, to stop cycles
+}
+
+// ref identifies a pointer by address and type: a struct and its first
+// field share an address, but both need validating
+type ref struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func (w *walker) walkStruct(rv reflect.Value, prefix string) {
+	for _, spec := range w.v.fields(rv.Type()) {
+		if w.err != nil {
+			return
+		}
+		field := rv.Field(spec.index)
+		path := joinPath(prefix, spec.name)
+
+		w.checkRules(field, path, spec.rules)
+		w.walkValue(field, path)
+	}
+}
+
+// walkValue descends into composite values looking for nested structs
+func (w *walker) walkValue(rv reflect.Value, path string) {
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+			return
+		}
+		r := ref{rv.Pointer(), rv.Type()}
+		if w.visited[r] {
+			return
+		}
+		w.visited[r] = true
+		w.walkStruct(rv.Elem(), path)
+	case reflect.Interface:
+		if !rv.IsNil() {
+			w.walkValue(rv.Elem(), path)
+		}
+	case reflect.Struct:
+		w.walkStruct(rv, path)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			w.walkValue(rv.Index(i), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.Map:
+		iter := rv.MapRange()
+		for iter.Next() {
+			w.walkValue(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key()))
+		}
+	}
+}
+
+func (w *walker) checkRules(field reflect.Value, path string, rules []tagRule) {
+	for _, tr := range rules {
+		rule, ok := w.v.rule(tr.name)
+		if !ok {
+			w.err = fmt.Errorf("%w: unknown rule %q on field %s", ErrInvalidRule, tr.name, path)
+			return
+		}
+
+		value := field
+		if tr.name != "required" {
+			// Optional pointers: nil means "not set", so other rules are skipped
+			for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+				if value.IsNil() {
+					break
+				}
+				value = value.Elem()
+			}
+			if (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) && value.IsNil() {
+				continue
+			}
+		}
+
+		if err := rule(value, tr.param); err != nil {
+			if errors.Is(err, ErrInvalidRule) {
+				w.err = fmt.Errorf("field %s: %w", path, err)
+				return
+			}
+			w.errs = append(w.errs, ValidationError{Field: path, Message: err.Error(), Rule: tr.name})
+			if tr.name == "required" {
+				return // further rules on a missing value only add noise
+			}
+		}
+	}
+}
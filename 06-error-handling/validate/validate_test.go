@@ -0,0 +1,215 @@
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type address struct {
+	City    string `validate:"required"`
+	Country string `validate:"len=3"`
+}
+
+type Contact struct {
+	Email string `validate:"required,email"`
+}
+
+type user struct {
+	Name    string `validate:"required,max=10"`
+	Age     int    `validate:"min=0,max=150"`
+	Role    string `validate:"oneof=admin user"`
+	Home    address
+	Work    *address
+	Contact          // embedded
+	Tags    []string `validate:"min=1"`
+	Friends []*user
+	Nick    *string `validate:"min=2"`
+	skipped string  `validate:"required"`
+	Ignored string  `validate:"-"`
+}
+
+func validUser() user {
+	return user{
+		Name:    "Alice",
+		Age:     30,
+		Role:    "admin",
+		Home:    address{City: "Boston", Country: "USA"},
+		Contact: Contact{Email: "alice@example.com"},
+		Tags:    []string{"a"},
+	}
+}
+
+func TestStructValid(t *testing.T) {
+	u := validUser()
+	if err := Struct(u); err != nil {
+		t.Errorf("Struct(valid) = %v; want nil", err)
+	}
+	if err := Struct(&u); err != nil {
+		t.Errorf("Struct(&valid) = %v; want nil", err)
+	}
+}
+
+func TestStructViolations(t *testing.T) {
+	short := "x"
+	u := validUser()
+	u.Name = "Bartholomew Jr"
+	u.Age = -1
+	u.Role = "root"
+	u.Home.City = ""
+	u.Work = &address{City: "NYC", Country: "US"}
+	u.Email = "not-an-email"
+	u.Tags = nil
+	u.Friends = []*user{{Name: "", Role: "user", Tags: []string{"b"}, Contact: Contact{Email: "b@example.com"}, Home: address{City: "LA", Country: "USA"}}}
+	u.Nick = &short
+
+	err := Struct(u)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Struct error = %v; want ValidationErrors", err)
+	}
+
+	want := []string{
+		"Name", "Age", "Role", "Home.City", "Work.Country",
+		"Contact.Email", "Tags", "Friends[0].Name", "Nick",
+	}
+	if got := errs.Fields(); !slices.Equal(got, want) {
+		t.Errorf("failing fields = %v; want %v", got, want)
+	}
+
+	var first ValidationError
+	if !errors.As(err, &first) || first.Field != "Name" || first.Rule != "max" {
+		t.Errorf("errors.As(ValidationError) = %+v; want the Name/max violation", first)
+	}
+}
+
+func TestRequiredStopsFurtherRules(t *testing.T) {
+	err := Struct(Contact{})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Rule != "required" {
+		t.Errorf("Struct(Contact{}) = %v; want a single required violation", err)
+	}
+}
+
+func TestCycles(t *testing.T) {
+	type node struct {
+		Name string `validate:"required"`
+		Next *node
+	}
+	a := &node{Name: "a"}
+	b := &node{Next: a}
+	a.Next = b
+
+	err := Struct(a)
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || !slices.Equal(errs.Fields(), []string{"Next.Name"}) {
+		t.Errorf("Struct(cycle) = %v; want one Next.Name violation", err)
+	}
+}
+
+func TestPointerToFirstField(t *testing.T) {
+	type inner struct {
+		Code string `validate:"required"`
+	}
+	type outer struct {
+		First inner
+		Name  string `validate:"required"`
+	}
+	type holder struct {
+		Outer *outer
+		Inner *inner
+	}
+	o := &outer{Name: "o"}
+	// &o.First has the same address as o but is a different struct
+	err := Struct(holder{Outer: o, Inner: &o.First})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || !slices.Equal(errs.Fields(), []string{"Outer.First.Code", "Inner.Code"}) {
+		t.Errorf("Struct = %v; want Outer.First.Code and Inner.Code violations", err)
+	}
+}
+
+func TestCustomRule(t *testing.T) {
+	v := New()
+	v.RegisterRule("even", func(rv reflect.Value, _ string) error {
+		if rv.Int()%2 != 0 {
+			return errors.New("must be even")
+		}
+		return nil
+	})
+
+	type pair struct {
+		N int `validate:"even"`
+	}
+	if err := v.Struct(pair{N: 4}); err != nil {
+		t.Errorf("even(4) = %v; want nil", err)
+	}
+	if err := v.Struct(pair{N: 3}); err == nil || !strings.Contains(err.Error(), "must be even") {
+		t.Errorf("even(3) = %v; want a violation", err)
+	}
+
+	// Rules registered on one validator do not leak into the default one
+	if err := Struct(pair{N: 4}); !errors.Is(err, ErrInvalidRule) {
+		t.Errorf("default Struct with unknown rule = %v; want ErrInvalidRule", err)
+	}
+}
+
+func TestInvalidRules(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value any
+	}{
+		{"bad param", struct {
+			N int `validate:"min=abc"`
+		}{}},
+		{"wrong kind", struct {
+			B bool `validate:"max=1"`
+		}{}},
+		{"bad pattern", struct {
+			S string `validate:"pattern=["`
+		}{S: "x"}},
+		{"not a struct", 42},
+		{"nil pointer", (*user)(nil)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Struct(tc.value)
+			if !errors.Is(err, ErrInvalidRule) {
+				t.Errorf("Struct = %v; want ErrInvalidRule", err)
+			}
+			var errs ValidationErrors
+			if errors.As(err, &errs) {
+				t.Errorf("malformed tags should not be reported as violations: %v", errs)
+			}
+		})
+	}
+}
+
+func TestBuiltinRules(t *testing.T) {
+	type sample struct {
+		Code  string  `validate:"pattern=^[A-Z]{3}$"`
+		Score float64 `validate:"max=1.5"`
+		Items []int   `validate:"len=2"`
+		Level uint    `validate:"oneof=1 2 3"`
+		Name  string  `validate:"min=2"`
+	}
+
+	testCases := []struct {
+		value sample
+		want  []string
+	}{
+		{sample{Code: "ABC", Score: 1.5, Items: []int{1, 2}, Level: 2, Name: "Bo"}, nil},
+		{sample{Code: "abc", Score: 1.6, Items: []int{1}, Level: 4, Name: "é"}, []string{"Code", "Score", "Items", "Level", "Name"}},
+	}
+
+	for _, tc := range testCases {
+		err := Struct(tc.value)
+		var errs ValidationErrors
+		errors.As(err, &errs)
+		if got := errs.Fields(); !slices.Equal(got, tc.want) {
+			t.Errorf("Struct(%+v) fields = %v; want %v (err %v)", tc.value, got, tc.want, err)
+		}
+	}
+}
@@ -0,0 +1,465 @@
+package main
+
+import (
+	"bufio"
+	"container/heap"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"grok-study-plan/07-sorting-searching/sorts"
+)
+
+// config controls one sort
+type config struct {
+	csv     bool   // records are CSV rows rather than lines
+	comma   rune   // CSV field separator
+	header  bool   // keep the first record on top and allow column names in keys
+	keys    []key  // sort criteria; none means whole records, byte-wise
+	memory  int64  // approximate bytes of records held before spilling a run
+	fanIn   int    // runs merged at once
+	tempDir string // parent of the run directory; "" for the system default
+}
+
+// stats describes what a sort did
+type stats struct {
+	Records int
+	Runs    int // sorted runs spilled to disk; 0 if everything fit in memory
+	Passes  int // merge passes over the runs
+}
+
+// record is one line or CSV row with its parsed sort keys
+type record struct {
+	fields []string // the CSV fields, or the whole line as one field
+	keys   []keyValue
+}
+
+// recordOverhead approximates the memory a record costs beyond its text
+const recordOverhead = 64
+
+func (r *record) size() int64 {
+	n := int64(recordOverhead + 16*len(r.fields) + 48*len(r.keys))
+	for _, f := range r.fields {
+		n += int64(len(f))
+	}
+	return n
+}
+
+// reader yields records in the configured format
+type reader interface {
+	read() ([]string, error)
+}
+
+type lineReader struct{ s *bufio.Scanner }
+
+func (r lineReader) read() ([]string, error) {
+	if !r.s.Scan() {
+		if err := r.s.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	return []string{r.s.Text()}, nil
+}
+
+type csvReader struct{ r *csv.Reader }
+
+func (r csvReader) read() ([]string, error) { return r.r.Read() }
+
+// writer writes records in the configured format
+type writer interface {
+	write(fields []string) error
+	flush() error
+}
+
+type lineWriter struct{ w *bufio.Writer }
+
+func (w lineWriter) write(fields []string) error {
+	w.w.WriteString(fields[0])
+	return w.w.WriteByte('\n')
+}
+
+func (w lineWriter) flush() error { return w.w.Flush() }
+
+type csvWriter struct{ w *csv.Writer }
+
+func (w csvWriter) write(fields []string) error { return w.w.Write(fields) }
+
+func (w csvWriter) flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (c *config) newReader(r io.Reader) reader {
+	if !c.csv {
+		s := bufio.NewScanner(r)
+		s.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		return lineReader{s}
+	}
+	cr := csv.NewReader(bufio.NewReader(r))
+	cr.Comma = c.comma
+	cr.FieldsPerRecord = -1 // rows may differ in length
+	return csvReader{cr}
+}
+
+func (c *config) newWriter(w io.Writer) writer {
+	if !c.csv {
+		return lineWriter{bufio.NewWriter(w)}
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = c.comma
+	return csvWriter{cw}
+}
+
+// sorter holds the state of one sort
+type sorter struct {
+	cfg   config
+	keys  []key
+	dir   string // run directory, created on the first spill
+	stats stats
+	line  int // input record number, for error messages
+}
+
+// sortStream sorts the records read from the inputs, in order, onto w. Each
+// input is read with its own reader, so a file without a final newline
+// doesn't run into the next one, and with cfg.header every input's first
+// record is a header, written once. Records that don't fit
+// in cfg.memory are sorted in chunks, spilled to temporary run files and
+// merged. The run files are removed before it returns, including when ctx
+// is cancelled.
+func sortStream(ctx context.Context, cfg config, w io.Writer, inputs ...io.Reader) (stats, error) {
+	if cfg.comma == 0 {
+		cfg.comma = ','
+	}
+	if cfg.fanIn < 2 {
+		cfg.fanIn = 16
+	}
+	s := &sorter{cfg: cfg}
+	defer s.cleanup()
+
+	readers := make([]reader, len(inputs))
+	for i, r := range inputs {
+		readers[i] = cfg.newReader(r)
+	}
+	err := s.run(ctx, readers, cfg.newWriter(w))
+	return s.stats, err
+}
+
+func (s *sorter) run(ctx context.Context, ins []reader, out writer) error {
+	header, err := s.readHeaders(ins)
+	if err != nil {
+		return err
+	}
+	if header != nil {
+		if err := out.write(header); err != nil {
+			return err
+		}
+	}
+	if err := s.resolveKeys(header); err != nil {
+		return err
+	}
+
+	var chunk []*record
+	var chunkSize int64
+	var runs []string
+	for _, in := range ins {
+		for {
+			fields, err := in.read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			s.line++
+			if s.line%1024 == 0 {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+			}
+
+			rec, err := s.newRecord(fields)
+			if err != nil {
+				return err
+			}
+			chunk = append(chunk, rec)
+			chunkSize += rec.size()
+			if chunkSize >= s.cfg.memory && s.cfg.memory > 0 {
+				run, err := s.spill(ctx, chunk)
+				if err != nil {
+					return err
+				}
+				runs = append(runs, run)
+				chunk, chunkSize = nil, 0
+			}
+		}
+	}
+	s.stats.Records = s.line
+
+	// Everything fit: no temporary files at all
+	if len(runs) == 0 {
+		s.sortChunk(chunk)
+		for _, rec := range chunk {
+			if err := out.write(rec.fields); err != nil {
+				return err
+			}
+		}
+		return out.flush()
+	}
+
+	if len(chunk) > 0 {
+		run, err := s.spill(ctx, chunk)
+		if err != nil {
+			return err
+		}
+		runs = append(runs, run)
+	}
+	return s.mergeAll(ctx, runs, out)
+}
+
+// readHeaders reads the first record of every input when cfg.header is set.
+// The first input's header is returned; the others must match it, since
+// column names in the keys and the single header written out assume the
+// inputs share one layout. Empty inputs have no header.
+func (s *sorter) readHeaders(ins []reader) ([]string, error) {
+	if !s.cfg.header {
+		return nil, nil
+	}
+	var header []string
+	for i, in := range ins {
+		fields, err := in.read()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			header = fields
+		} else if !slices.Equal(fields, header) {
+			return nil, fmt.Errorf("input %d: header %q differs from the first input's %q", i+1, fields, header)
+		}
+	}
+	return header, nil
+}
+
+// resolveKeys binds the keys to columns, or makes a whole-record key
+func (s *sorter) resolveKeys(header []string) error {
+	s.keys = s.cfg.keys
+	if len(s.keys) == 0 {
+		s.keys = []key{{spec: "record", index: -1}}
+		return nil
+	}
+	for i := range s.keys {
+		if err := s.keys[i].resolve(header); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newRecord parses the sort keys of one record
+func (s *sorter) newRecord(fields []string) (*record, error) {
+	columns := fields
+	if !s.cfg.csv && s.keys[0].index >= 0 {
+		columns = strings.Fields(fields[0])
+	}
+
+	rec := &record{fields: fields, keys: make([]keyValue, len(s.keys))}
+	for i, k := range s.keys {
+		var field string
+		switch {
+		case k.index < 0:
+			field = strings.Join(fields, string(s.cfg.comma))
+		case k.index < len(columns):
+			field = columns[k.index]
+		}
+		v, err := k.parse(field)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", s.line, err)
+		}
+		rec.keys[i] = v
+	}
+	return rec, nil
+}
+
+// sortChunk sorts records by key. Tim is stable, so records with equal
+// keys keep their input order, and the merge preserves that across runs.
+func (s *sorter) sortChunk(chunk []*record) {
+	sorts.TimFunc(chunk, func(a, b *record) int {
+		return compareKeys(s.keys, a.keys, b.keys)
+	})
+}
+
+// spill sorts a chunk and writes it to a new run file
+func (s *sorter) spill(ctx context.Context, chunk []*record) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	s.sortChunk(chunk)
+	return s.writeRun(func(out writer) error {
+		for _, rec := range chunk {
+			if err := out.write(rec.fields); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// writeRun creates a run file and fills it with fill
+func (s *sorter) writeRun(fill func(writer) error) (string, error) {
+	if s.dir == "" {
+		dir, err := os.MkdirTemp(s.cfg.tempDir, "extsort-")
+		if err != nil {
+			return "", err
+		}
+		s.dir = dir
+	}
+	s.stats.Runs++
+	path := filepath.Join(s.dir, fmt.Sprintf("run-%06d", s.stats.Runs))
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	out := s.cfg.newWriter(f)
+	err = fill(out)
+	if err == nil {
+		err = out.flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return path, err
+}
+
+// mergeAll merges runs onto out, in several passes if there are more runs
+// than cfg.fanIn
+func (s *sorter) mergeAll(ctx context.Context, runs []string, out writer) error {
+	for len(runs) > s.cfg.fanIn {
+		s.stats.Passes++
+		var next []string
+		for i := 0; i < len(runs); i += s.cfg.fanIn {
+			group := runs[i:min(i+s.cfg.fanIn, len(runs))]
+			run, err := s.writeRun(func(w writer) error {
+				return s.merge(ctx, group, w)
+			})
+			if err != nil {
+				return err
+			}
+			for _, path := range group {
+				os.Remove(path) // merged; free the disk space early
+			}
+			next = append(next, run)
+		}
+		runs = next
+	}
+	s.stats.Passes++
+	if err := s.merge(ctx, runs, out); err != nil {
+		return err
+	}
+	return out.flush()
+}
+
+// cursor is the next record of one open run
+type cursor struct {
+	rec *record
+	run int // position of the run, which breaks ties to keep the sort stable
+	in  reader
+}
+
+// mergeHeap is a min-heap of cursors ordered by their current record
+type mergeHeap struct {
+	cursors []*cursor
+	keys    []key
+}
+
+func (h *mergeHeap) Len() int { return len(h.cursors) }
+
+func (h *mergeHeap) Less(i, j int) bool {
+	a, b := h.cursors[i], h.cursors[j]
+	if c := compareKeys(h.keys, a.rec.keys, b.rec.keys); c != 0 {
+		return c < 0
+	}
+	return a.run < b.run
+}
+
+func (h *mergeHeap) Swap(i, j int) { h.cursors[i], h.cursors[j] = h.cursors[j], h.cursors[i] }
+func (h *mergeHeap) Push(x any)    { h.cursors = append(h.cursors, x.(*cursor)) }
+
+func (h *mergeHeap) Pop() any {
+	last := h.cursors[len(h.cursors)-1]
+	h.cursors = h.cursors[:len(h.cursors)-1]
+	return last
+}
+
+// merge k-way merges sorted run files onto out
+func (s *sorter) merge(ctx context.Context, runs []string, out writer) error {
+	h := &mergeHeap{keys: s.keys}
+	for i, path := range runs {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		c := &cursor{run: i, in: s.cfg.newReader(f)}
+		if ok, err := s.advance(c); err != nil {
+			return err
+		} else if ok {
+			h.cursors = append(h.cursors, c)
+		}
+	}
+	heap.Init(h)
+
+	for n := 0; h.Len() > 0; n++ {
+		if n%1024 == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		c := h.cursors[0]
+		if err := out.write(c.rec.fields); err != nil {
+			return err
+		}
+		ok, err := s.advance(c)
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+	return nil
+}
+
+// advance reads the next record of c's run, reporting false at its end
+func (s *sorter) advance(c *cursor) (bool, error) {
+	fields, err := c.in.read()
+	if errors.Is(err, io.EOF) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	rec, err := s.newRecord(fields)
+	if err != nil {
+		return false, err
+	}
+	c.rec = rec
+	return true, nil
+}
+
+// cleanup removes the run directory and everything in it
+func (s *sorter) cleanup() {
+	if s.dir != "" {
+		os.RemoveAll(s.dir)
+	}
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func mustKeys(t *testing.T, specs ...string) []key {
+	t.Helper()
+	var keys []key
+	for _, spec := range specs {
+		k, err := parseKey(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// sortString runs sortStream over input with a temp directory that the
+// test checks is left empty
+func sortString(t *testing.T, cfg config, input string) (string, stats) {
+	t.Helper()
+	cfg.tempDir = t.TempDir()
+	var out bytes.Buffer
+	st, err := sortStream(context.Background(), cfg, &out, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("sortStream: %v", err)
+	}
+	assertEmpty(t, cfg.tempDir)
+	return out.String(), st
+}
+
+func assertEmpty(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) > 0 {
+		t.Errorf("temporary files left behind in %s: %v", dir, entries)
+	}
+}
+
+func TestLinesInMemory(t *testing.T) {
+	got, st := sortString(t, config{memory: 1 << 20}, "pear\napple\nfig\napple\n")
+	if want := "apple\napple\nfig\npear\n"; got != want {
+		t.Errorf("got %q; want %q", got, want)
+	}
+	if st.Runs != 0 || st.Records != 4 {
+		t.Errorf("stats = %+v; want 4 records and no runs", st)
+	}
+}
+
+func TestLineFields(t *testing.T) {
+	input := "GET /a 200 31\nPOST /b 500 7\nGET /c 200 120\nGET /d 404 7\n"
+	got, _ := sortString(t, config{memory: 1 << 20, keys: mustKeys(t, "4:int:desc", "2")}, input)
+	want := "GET /c 200 120\nGET /a 200 31\nPOST /b 500 7\nGET /d 404 7\n"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCSVMultipleKeys(t *testing.T) {
+	input := `name,category,price
+Mouse,accessories,25.50
+Laptop,computers,999.99
+"Cable, USB-C",accessories,9.99
+Desktop,computers,999.99
+Keyboard,accessories,75
+Tablet,computers,
+`
+	cfg := config{csv: true, header: true, memory: 1 << 20, keys: mustKeys(t, "category", "price:float:desc", "name:fold")}
+	got, _ := sortString(t, cfg, input)
+	want := `name,category,price
+Keyboard,accessories,75
+Mouse,accessories,25.50
+"Cable, USB-C",accessories,9.99
+Desktop,computers,999.99
+Laptop,computers,999.99
+Tablet,computers,
+`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMultipleInputs(t *testing.T) {
+	testCases := []struct {
+		name   string
+		cfg    config
+		inputs []string
+		want   string
+	}{
+		// The first file has no final newline; its last line must not run
+		// into the second file's first
+		{"unterminated lines", config{}, []string{"b\na", "d\nc\n"}, "a\nb\nc\nd\n"},
+		{"csv headers", config{csv: true, header: true, keys: mustKeys(t, "price:float")},
+			[]string{"name,price\nb,2\na,1\n", "name,price\nc,0.5\n"},
+			"name,price\nc,0.5\na,1\nb,2\n"},
+		{"empty first input", config{csv: true, header: true, keys: mustKeys(t, "price:float")},
+			[]string{"", "name,price\nb,2\na,1\n"},
+			"name,price\na,1\nb,2\n"},
+		{"line headers", config{header: true}, []string{"word\npear\napple", "word\nfig\n"}, "word\napple\nfig\npear\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.cfg.memory = 1 << 20
+			var inputs []io.Reader
+			for _, in := range tc.inputs {
+				inputs = append(inputs, strings.NewReader(in))
+			}
+			var out bytes.Buffer
+			if _, err := sortStream(context.Background(), tc.cfg, &out, inputs...); err != nil {
+				t.Fatalf("sortStream: %v", err)
+			}
+			if got := out.String(); got != tc.want {
+				t.Errorf("got %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMismatchedHeaders(t *testing.T) {
+	cfg := config{csv: true, header: true, keys: mustKeys(t, "price:float")}
+	_, err := sortStream(context.Background(), cfg, io.Discard,
+		strings.NewReader("name,price\na,1\n"), strings.NewReader("name,cost\nb,2\n"))
+	if err == nil || !strings.Contains(err.Error(), "input 2: header") {
+		t.Errorf("error = %v; want a header mismatch for input 2", err)
+	}
+}
+
+func TestOutputMayBeAnInput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(path, []byte("pear\napple\nfig\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	inputs, closeInputs, err := openInputs([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeInputs()
+	out, err := createOutput(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sortStream(context.Background(), config{memory: 1 << 20}, out, inputs...); err != nil {
+		out.abort()
+		t.Fatalf("sortStream: %v", err)
+	}
+	if err := out.commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "apple\nfig\npear\n"; string(got) != want {
+		t.Errorf("%s = %q; want %q", path, got, want)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0o640 {
+		t.Errorf("mode after commit = %v; want 0640 kept", fi.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("directory holds %v; want only the output", entries)
+	}
+}
+
+func TestAbortKeepsDestination(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sorted.txt")
+	if err := os.WriteFile(path, []byte("previous\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := createOutput(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(out, "half-written")
+	out.abort()
+
+	if got, _ := os.ReadFile(path); string(got) != "previous\n" {
+		t.Errorf("%s = %q after abort; want it unchanged", path, got)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("directory holds %v; want the temporary file removed", entries)
+	}
+}
+
+// spilled sorts a large input with a tiny budget so it needs many runs and
+// several merge passes, and checks the result against an in-memory stable
+// sort
+func TestSpillAndMerge(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	type row struct {
+		group int
+		seq   int
+	}
+	rows := make([]row, 5000)
+	var input strings.Builder
+	for i := range rows {
+		rows[i] = row{group: r.IntN(50), seq: i}
+		fmt.Fprintf(&input, "%d,%d\n", rows[i].group, rows[i].seq)
+	}
+
+	// Only the group is a key, so stability decides the order of seq
+	cfg := config{csv: true, memory: 4096, fanIn: 3, keys: mustKeys(t, "1:int")}
+	got, st := sortString(t, cfg, input.String())
+
+	slices.SortStableFunc(rows, func(a, b row) int { return a.group - b.group })
+	var want strings.Builder
+	for _, rw := range rows {
+		fmt.Fprintf(&want, "%d,%d\n", rw.group, rw.seq)
+	}
+	if got != want.String() {
+		t.Error("external sort differs from slices.SortStableFunc")
+	}
+	if st.Runs < 10 || st.Passes < 2 {
+		t.Errorf("stats = %+v; want many runs and more than one merge pass", st)
+	}
+}
+
+// cancellingReader cancels the sort after n lines
+type cancellingReader struct {
+	r      io.Reader
+	n      int
+	cancel context.CancelFunc
+}
+
+func (c *cancellingReader) Read(p []byte) (int, error) {
+	c.n -= bytes.Count(p, []byte{'\n'})
+	if c.n <= 0 {
+		c.cancel()
+	}
+	return c.r.Read(p)
+}
+
+func TestCancelRemovesRuns(t *testing.T) {
+	var input strings.Builder
+	for i := range 200_000 {
+		fmt.Fprintf(&input, "line %d\n", (i*7919)%200_000)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir := t.TempDir()
+	in := &cancellingReader{r: strings.NewReader(input.String()), n: 100_000, cancel: cancel}
+	cfg := config{memory: 64 << 10, tempDir: dir}
+	_, err := sortStream(ctx, cfg, io.Discard, in)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("sortStream = %v; want context.Canceled", err)
+	}
+	assertEmpty(t, dir)
+}
+
+func TestParseKey(t *testing.T) {
+	testCases := []struct {
+		spec    string
+		want    key
+		wantErr bool
+	}{
+		{"name", key{column: "name", typ: typeString}, false},
+		{"3:int:desc", key{column: "3", typ: typeInt, desc: true}, false},
+		{"price:desc:float", key{column: "price", typ: typeFloat, desc: true}, false},
+		{"title:fold", key{column: "title", typ: typeFold}, false},
+		{"", key{}, true},
+		{"x:date", key{}, true},
+	}
+	for _, tc := range testCases {
+		got, err := parseKey(tc.spec)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parseKey(%q) error = %v; wantErr %v", tc.spec, err, tc.wantErr)
+			continue
+		}
+		if err == nil && (got.column != tc.want.column || got.typ != tc.want.typ || got.desc != tc.want.desc) {
+			t.Errorf("parseKey(%q) = %+v; want %+v", tc.spec, got, tc.want)
+		}
+	}
+}
+
+func TestKeyErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		cfg   config
+		input string
+		want  string
+	}{
+		{"name without header", config{keys: mustKeys(t, "price")}, "a\n", "need -header"},
+		{"unknown column", config{csv: true, header: true, keys: mustKeys(t, "cost")}, "price\n1\n", `no column named "cost"`},
+		{"bad int", config{keys: mustKeys(t, "1:int")}, "12\nabc\n", `record 2: column 1: "abc" is not an int`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := sortStream(context.Background(), tc.cfg, io.Discard, strings.NewReader(tc.input))
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("error = %v; want it to mention %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want int64
+	}{
+		{"512", 512},
+		{"64KB", 64 << 10},
+		{"64mb", 64 << 20},
+		{"2G", 2 << 30},
+		{"8589934591GB", (1<<33 - 1) << 30},
+	}
+	for _, tc := range testCases {
+		if got, err := parseSize(tc.in); err != nil || got != tc.want {
+			t.Errorf("parseSize(%q) = %d, %v; want %d", tc.in, got, err, tc.want)
+		}
+	}
+	for _, bad := range []string{"", "MB", "-1", "ten", "99999999999GB", "8589934592G", "99999999999999999999"} {
+		if _, err := parseSize(bad); err == nil {
+			t.Errorf("parseSize(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// keyType says how a column is compared
+type keyType int
+
+const (
+	typeString keyType = iota // byte-wise
+	typeFold                  // case-insensitive
+	typeInt
+	typeFloat
+)
+
+var keyTypes = map[string]keyType{
+	"string": typeString,
+	"str":    typeString,
+	"fold":   typeFold,
+	"int":    typeInt,
+	"float":  typeFloat,
+	"num":    typeFloat,
+}
+
+// key is one sort criterion, parsed from "column[:type][:desc]"
+type key struct {
+	spec   string
+	column string // 1-based index or header name, as given
+	index  int    // 0-based field index, resolved against the header
+	typ    keyType
+	desc   bool
+}
+
+// parseKey parses a spec such as "price:float:desc", "3:int" or "name"
+func parseKey(spec string) (key, error) {
+	parts := strings.Split(spec, ":")
+	k := key{spec: spec, column: parts[0], index: -1}
+	if k.column == "" {
+		return key{}, fmt.Errorf("key %q: missing column", spec)
+	}
+	for _, part := range parts[1:] {
+		switch part {
+		case "desc":
+			k.desc = true
+		case "asc":
+			k.desc = false
+		default:
+			typ, ok := keyTypes[part]
+			if !ok {
+				return key{}, fmt.Errorf("key %q: unknown type or direction %q (want string, fold, int, float, asc or desc)", spec, part)
+			}
+			k.typ = typ
+		}
+	}
+	return k, nil
+}
+
+// resolve sets k.index from a 1-based column number or, with a header, a
+// column name
+func (k *key) resolve(header []string) error {
+	if n, err := strconv.Atoi(k.column); err == nil {
+		if n < 1 {
+			return fmt.Errorf("key %q: columns are numbered from 1", k.spec)
+		}
+		k.index = n - 1
+		return nil
+	}
+	for i, name := range header {
+		if name == k.column {
+			k.index = i
+			return nil
+		}
+	}
+	if header == nil {
+		return fmt.Errorf("key %q: column names need -header", k.spec)
+	}
+	return fmt.Errorf("key %q: no column named %q in header %q", k.spec, k.column, header)
+}
+
+// keyValue is a parsed column value; only the field for the key's type is
+// set. Empty numeric fields are marked blank and sort before every number.
+type keyValue struct {
+	s     string
+	i     int64
+	f     float64
+	blank bool
+}
+
+// parse converts one field for comparison
+func (k key) parse(field string) (keyValue, error) {
+	if (k.typ == typeInt || k.typ == typeFloat) && strings.TrimSpace(field) == "" {
+		return keyValue{blank: true}, nil
+	}
+	switch k.typ {
+	case typeFold:
+		return keyValue{s: strings.ToLower(field)}, nil
+	case typeInt:
+		i, err := strconv.ParseInt(strings.TrimSpace(field), 10, 64)
+		if err != nil {
+			return keyValue{}, fmt.Errorf("column %s: %q is not an int", k.column, field)
+		}
+		return keyValue{i: i}, nil
+	case typeFloat:
+		f, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return keyValue{}, fmt.Errorf("column %s: %q is not a number", k.column, field)
+		}
+		return keyValue{f: f}, nil
+	}
+	return keyValue{s: field}, nil
+}
+
+func (k key) compare(a, b keyValue) int {
+	var c int
+	switch {
+	case a.blank && b.blank:
+		c = 0
+	case a.blank:
+		c = -1
+	case b.blank:
+		c = 1
+	case k.typ == typeInt:
+		c = cmp.Compare(a.i, b.i)
+	case k.typ == typeFloat:
+		c = cmp.Compare(a.f, b.f)
+	default:
+		c = strings.Compare(a.s, b.s)
+	}
+	if k.desc {
+		return -c
+	}
+	return c
+}
+
+// compareKeys compares two records' key values, criterion by criterion
+func compareKeys(keys []key, a, b []keyValue) int {
+	for i, k := range keys {
+		if c := k.compare(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
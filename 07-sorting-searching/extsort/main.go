@@ -0,0 +1,204 @@
+// Command extsort sorts line-oriented or CSV files that are too big to sort
+// in memory. Records are read in chunks of about -mem bytes; each chunk is
+// sorted and written to a temporary run file, and the runs are then merged
+// with a heap, -fanin at a time. Input that fits in -mem never touches the
+// disk. The sort is stable, and the temporary files are removed even when
+// the command is interrupted.
+//
+// Usage:
+//
+//	go run ./07-sorting-searching/extsort [flags] [file ...]
+//
+// Sort keys are given as column[:type][:desc], repeated or comma-separated,
+// the first key taking priority as in sortByMultipleCriteria. Columns are
+// numbered from 1, or named when -header is set. Types are string
+// (default), fold (case-insensitive), int and float. For plain lines the
+// columns are whitespace-separated fields; without -k whole lines are
+// compared.
+//
+//	extsort -csv -header -k category,price:float:desc,name products.csv
+//	extsort -k 2:int:desc -mem 256MB access.log > sorted.log
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// parseSize parses a byte count such as 512KB, 64MB or 1GB
+func parseSize(s string) (int64, error) {
+	units := []struct {
+		suffix string
+		size   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1}}
+	upper := strings.ToUpper(strings.TrimSpace(s))
+	mult := int64(1)
+	for _, u := range units {
+		if strings.HasSuffix(upper, u.suffix) {
+			upper, mult = strings.TrimSuffix(upper, u.suffix), u.size
+			break
+		}
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(upper), 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	if n > math.MaxInt64/mult {
+		return 0, fmt.Errorf("size %q is too large", s)
+	}
+	return n * mult, nil
+}
+
+// openInputs opens the named files, or returns stdin for none. The files
+// are kept apart rather than joined with io.MultiReader, which would run a
+// file's unterminated last line into the next file's first.
+func openInputs(paths []string) ([]io.Reader, func(), error) {
+	if len(paths) == 0 {
+		return []io.Reader{os.Stdin}, func() {}, nil
+	}
+	var readers []io.Reader
+	var files []*os.File
+	closeAll := func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+		files = append(files, f)
+		readers = append(readers, f)
+	}
+	return readers, closeAll, nil
+}
+
+// outputFile is written beside its destination and renamed over it only
+// when the sort succeeds. The inputs are still intact while they are read,
+// so -o may name one of them, and a failed sort leaves the destination as
+// it was.
+type outputFile struct {
+	*os.File
+	path string
+}
+
+func createOutput(path string) (*outputFile, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".extsort-*")
+	if err != nil {
+		return nil, err
+	}
+	mode := os.FileMode(0o644)
+	if fi, err := os.Stat(path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return &outputFile{File: f, path: path}, nil
+}
+
+// commit closes the file and moves it into place
+func (f *outputFile) commit() error {
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), f.path)
+}
+
+// abort closes and removes the file
+func (f *outputFile) abort() {
+	f.Close()
+	os.Remove(f.Name())
+}
+
+func main() {
+	var cfg config
+	flag.BoolVar(&cfg.csv, "csv", false, "read and write CSV instead of lines")
+	comma := flag.String("comma", ",", "CSV field separator")
+	flag.BoolVar(&cfg.header, "header", false, "treat the first record as a header: keep it first and allow column names in -k")
+	flag.Func("k", "sort key column[:type][:desc]; repeat or separate with commas", func(s string) error {
+		for _, spec := range strings.Split(s, ",") {
+			k, err := parseKey(spec)
+			if err != nil {
+				return err
+			}
+			cfg.keys = append(cfg.keys, k)
+		}
+		return nil
+	})
+	mem := flag.String("mem", "64MB", "memory budget for records before spilling a sorted run to disk")
+	flag.IntVar(&cfg.fanIn, "fanin", 16, "runs merged at once")
+	flag.StringVar(&cfg.tempDir, "tmpdir", "", "directory for run files (default: system temp dir)")
+	output := flag.String("o", "", "output file (default: stdout)")
+	verbose := flag.Bool("v", false, "report records, runs and merge passes on stderr")
+	flag.Parse()
+
+	fail := func(err error) {
+		fmt.Fprintln(os.Stderr, "extsort:", err)
+		if errors.Is(err, context.Canceled) {
+			os.Exit(130)
+		}
+		os.Exit(1)
+	}
+
+	var err error
+	if cfg.memory, err = parseSize(*mem); err != nil {
+		fail(err)
+	}
+	if r := []rune(*comma); len(r) != 1 {
+		fail(fmt.Errorf("-comma must be a single character, got %q", *comma))
+	} else {
+		cfg.comma = r[0]
+	}
+
+	// Interrupting cancels the sort, which removes its run files
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	inputs, closeInputs, err := openInputs(flag.Args())
+	if err != nil {
+		fail(err)
+	}
+	defer closeInputs()
+
+	out := io.Writer(os.Stdout)
+	var outFile *outputFile
+	if *output != "" {
+		if outFile, err = createOutput(*output); err != nil {
+			closeInputs()
+			fail(err)
+		}
+		out = outFile
+	}
+
+	st, err := sortStream(ctx, cfg, out, inputs...)
+	closeInputs()
+	if outFile != nil {
+		if err != nil {
+			outFile.abort() // don't leave a half-sorted file behind
+		} else {
+			err = outFile.commit()
+		}
+	}
+	if err != nil {
+		fail(err)
+	}
+	if *verbose {
+		fmt.Fprintf(os.Stderr, "extsort: %d records, %d runs, %d merge passes\n", st.Records, st.Runs, st.Passes)
+	}
+}
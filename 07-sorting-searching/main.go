@@ -1,8 +1,14 @@
 package main
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"sort"
+
+	"grok-study-plan/07-sorting-searching/order"
+	"grok-study-plan/07-sorting-searching/search"
+	"grok-study-plan/07-sorting-searching/sorts"
 )
 
 // Person struct for custom sorting
@@ -21,9 +27,10 @@ func (p People) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
 
 // Product struct for sorting by multiple criteria
 type Product struct {
-	Name  string
-	Price float64
-	Stock int
+	Name     string
+	Price    float64
+	Stock    int
+	Category string
 }
 
 // ByPrice implements sort.Interface for sorting products by price
@@ -40,51 +47,6 @@ func (p ByName) Len() int           { return len(p) }
 func (p ByName) Less(i, j int) bool { return p[i].Name < p[j].Name }
 func (p ByName) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
 
-// Binary search for integers
-func binarySearch(arr []int, target int) int {
-	low, high := 0, len(arr)-1
-
-	for low <= high {
-		mid := low + (high-low)/2
-
-		if arr[mid] == target {
-			return mid
-		} else if arr[mid] < target {
-			low = mid + 1
-		} else {
-			high = mid - 1
-		}
-	}
-
-	return -1 // not found
-}
-
-// Binary search for strings
-func binarySearchString(arr []string, target string) int {
-	low, high := 0, len(arr)-1
-
-	for low <= high {
-		mid := low + (high-low)/2
-
-		if arr[mid] == target {
-			return mid
-		} else if arr[mid] < target {
-			low = mid + 1
-		} else {
-			high = mid - 1
-		}
-	}
-
-	return -1 // not found
-}
-
-// Generic binary search using sort.Search
-func findInSorted(arr []int, target int) int {
-	return sort.Search(len(arr), func(i int) bool {
-		return arr[i] >= target
-	})
-}
-
 // Linear search for comparison
 func linearSearch(arr []int, target int) int {
 	for i, v := range arr {
@@ -95,116 +57,42 @@ func linearSearch(arr []int, target int) int {
 	return -1
 }
 
-// Bubble sort (educational - don't use in production)
-func bubbleSort(arr []int) {
-	n := len(arr)
-	for i := 0; i < n-1; i++ {
-		for j := 0; j < n-i-1; j++ {
-			if arr[j] > arr[j+1] {
-				arr[j], arr[j+1] = arr[j+1], arr[j]
-			}
+// daysToShip counts the days needed to ship packages in order when each
+// day's load may not exceed capacity
+func daysToShip(packages []int, capacity int) int {
+	days, load := 1, 0
+	for _, p := range packages {
+		if load+p > capacity {
+			days++
+			load = 0
 		}
+		load += p
 	}
+	return days
 }
 
-// Selection sort (educational - don't use in production)
-func selectionSort(arr []int) {
-	n := len(arr)
-	for i := 0; i < n-1; i++ {
-		minIdx := i
-		for j := i + 1; j < n; j++ {
-			if arr[j] < arr[minIdx] {
-				minIdx = j
-			}
-		}
-		arr[i], arr[minIdx] = arr[minIdx], arr[i]
+func sum(s []int) int {
+	total := 0
+	for _, v := range s {
+		total += v
 	}
+	return total
 }
 
-// Insertion sort (educational - don't use in production)
-func insertionSort(arr []int) {
-	for i := 1; i < len(arr); i++ {
-		key := arr[i]
-		j := i - 1
-
-		for j >= 0 && arr[j] > key {
-			arr[j+1] = arr[j]
-			j--
-		}
-		arr[j+1] = key
-	}
-}
-
-// Quick sort implementation
-func quickSort(arr []int) {
-	if len(arr) <= 1 {
-		return
-	}
-
-	pivot := arr[len(arr)/2]
-	left, right := 0, len(arr)-1
-
-	for left <= right {
-		for arr[left] < pivot {
-			left++
-		}
-		for arr[right] > pivot {
-			right--
-		}
-		if left <= right {
-			arr[left], arr[right] = arr[right], arr[left]
-			left++
-			right--
-		}
-	}
-
-	quickSort(arr[:right+1])
-	quickSort(arr[left:])
+// Product orderings that can be combined, or picked by name from a spec
+var productFields = order.Fields[Product]{
+	"name":     order.ByFold(func(p Product) string { return p.Name }),
+	"price":    order.By(func(p Product) float64 { return p.Price }),
+	"stock":    order.By(func(p Product) int { return p.Stock }),
+	"category": order.By(func(p Product) string { return p.Category }),
 }
 
-// Merge sort implementation
-func mergeSort(arr []int) []int {
-	if len(arr) <= 1 {
-		return arr
-	}
-
-	mid := len(arr) / 2
-	left := mergeSort(arr[:mid])
-	right := mergeSort(arr[mid:])
-
-	return merge(left, right)
-}
-
-func merge(left, right []int) []int {
-	result := make([]int, 0, len(left)+len(right))
-	i, j := 0, 0
-
-	for i < len(left) && j < len(right) {
-		if left[i] <= right[j] {
-			result = append(result, left[i])
-			i++
-		} else {
-			result = append(result, right[j])
-			j++
-		}
-	}
-
-	// Append remaining elements
-	result = append(result, left[i:]...)
-	result = append(result, right[j:]...)
-
-	return result
-}
-
-// Custom sort with sort.Slice
+// Multi-key sort built from key functions
 func sortByMultipleCriteria(products []Product) {
 	// Sort by price ascending, then by name ascending for same price
-	sort.Slice(products, func(i, j int) bool {
-		if products[i].Price != products[j].Price {
-			return products[i].Price < products[j].Price
-		}
-		return products[i].Name < products[j].Name
-	})
+	order.By(func(p Product) float64 { return p.Price }).
+		ThenBy(productFields["name"]).
+		Sort(products)
 }
 
 // Find minimum and maximum in unsorted array
@@ -279,11 +167,11 @@ func main() {
 	fmt.Println("\n=== Sorting Products by Different Criteria ===")
 
 	products := []Product{
-		{"Laptop", 999.99, 5},
-		{"Mouse", 25.50, 20},
-		{"Keyboard", 75.00, 15},
-		{"Monitor", 299.99, 8},
-		{"Mouse", 30.00, 10}, // Same name as another mouse
+		{"Laptop", 999.99, 5, "computers"},
+		{"Mouse", 25.50, 20, "accessories"},
+		{"Keyboard", 75.00, 15, "accessories"},
+		{"Monitor", 299.99, 8, "displays"},
+		{"Mouse", 30.00, 10, "accessories"}, // Same name as another mouse
 	}
 
 	fmt.Println("Original products:")
@@ -311,16 +199,15 @@ func main() {
 	sortedInts := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
 	target := 7
 
-	// Manual binary search
-	index := binarySearch(sortedInts, target)
-	if index != -1 {
-		fmt.Printf("Found %d at index %d (manual binary search)\n", target, index)
+	// Find returns the index, or where the target would be inserted
+	if index, found := search.Find(sortedInts, target); found {
+		fmt.Printf("Found %d at index %d (search.Find)\n", target, index)
 	} else {
 		fmt.Printf("%d not found\n", target)
 	}
 
-	// Using sort.Search
-	searchResult := findInSorted(sortedInts, target)
+	// The same search with sort.Search
+	searchResult := sort.SearchInts(sortedInts, target)
 	if searchResult < len(sortedInts) && sortedInts[searchResult] == target {
 		fmt.Printf("Found %d at index %d (sort.Search)\n", target, searchResult)
 	}
@@ -329,10 +216,63 @@ func main() {
 	sortedStrings := []string{"apple", "banana", "cherry", "date", "elderberry"}
 	targetStr := "cherry"
 
-	strIndex := binarySearchString(sortedStrings, targetStr)
-	if strIndex != -1 {
+	if strIndex, found := search.Find(sortedStrings, targetStr); found {
 		fmt.Printf("Found '%s' at index %d\n", targetStr, strIndex)
 	}
+	if strIndex, found := search.Find(sortedStrings, "coconut"); !found {
+		fmt.Printf("'coconut' not found; it belongs at index %d\n", strIndex)
+	}
+
+	fmt.Println("\n=== Bounds and Ranges ===")
+
+	scores := []int{55, 62, 70, 70, 70, 81, 90, 90, 97}
+	fmt.Printf("Scores: %v\n", scores)
+	lo, hi := search.EqualRange(scores, 70)
+	fmt.Printf("Scores of 70: indices [%d, %d), %d students\n", lo, hi, hi-lo)
+	fmt.Printf("Scores of at least 80: %v\n", scores[search.LowerBound(scores, 80):])
+	fmt.Printf("Scores of at most 70: %v\n", scores[:search.UpperBound(scores, 70)])
+	fmt.Printf("Scores in [60, 90): %v\n", scores[search.LowerBound(scores, 60):search.LowerBound(scores, 90)])
+
+	// Search a slice of structs by one field, without a struct to compare with
+	byAge := slices.Clone(people)
+	slices.SortFunc(byAge, func(a, b Person) int { return cmp.Compare(a.Age, b.Age) })
+	first := search.LowerBoundFunc(byAge, 30, func(p Person, age int) int { return cmp.Compare(p.Age, age) })
+	fmt.Printf("People aged 30 or over: %v\n", byAge[first:])
+
+	fmt.Println("\n=== Specialised Searches ===")
+
+	// Exponential search only reads as far as it needs, so it suits data
+	// of unknown length such as a stream of squares
+	reads := 0
+	squares := func(i int) (int, bool) {
+		reads++
+		return i * i, true
+	}
+	root, found := search.Exponential(squares, 1_000_000)
+	fmt.Printf("Exponential: 1000000 is square %d (exact=%t) after %d reads\n", root, found, reads)
+
+	// Interpolation search guesses the position from the value
+	evenly := make([]int, 1000)
+	for i := range evenly {
+		evenly[i] = 3 * i
+	}
+	pos, found := search.Interpolation(evenly, 2400)
+	fmt.Printf("Interpolation: 2400 at index %d (found=%t)\n", pos, found)
+
+	// Ternary search finds the peak of a function that rises then falls
+	revenue := func(price float64) float64 { return price * (100 - 2*price) }
+	best := search.TernaryMax(0, 50, 1e-6, revenue)
+	fmt.Printf("Ternary: revenue peaks at price %.2f (%.2f)\n", best, revenue(best))
+
+	// Binary search on the answer: the smallest capacity that ships every
+	// package within 5 days, and the square root of 2 to 6 places
+	packages := []int{3, 2, 2, 4, 1, 4, 7, 5, 3}
+	capacity := search.FirstTrue(slices.Max(packages), sum(packages)+1, func(c int) bool {
+		return daysToShip(packages, c) <= 5
+	})
+	fmt.Printf("Answer search: capacity %d ships %v in %d days\n", capacity, packages, daysToShip(packages, capacity))
+	sqrt2 := search.FirstTrueFloat(0, 2, 1e-6, func(x float64) bool { return x*x >= 2 })
+	fmt.Printf("Answer search: sqrt(2) is about %.6f\n", sqrt2)
 
 	fmt.Println("\n=== Search Performance Comparison ===")
 
@@ -349,7 +289,7 @@ func main() {
 	fmt.Printf("Linear search found %d at index %d\n", target, linearIndex)
 
 	// Binary search
-	binaryIndex := binarySearch(largeArray, target)
+	binaryIndex, _ := search.Find(largeArray, target)
 	fmt.Printf("Binary search found %d at index %d\n", target, binaryIndex)
 
 	fmt.Println("\n=== Educational Sort Algorithms ===")
@@ -359,41 +299,81 @@ func main() {
 
 	fmt.Printf("Original array: %v\n", unsorted)
 
-	// Bubble sort
-	bubbleArr := make([]int, len(unsorted))
-	copy(bubbleArr, unsorted)
-	bubbleSort(bubbleArr)
-	fmt.Printf("Bubble sort: %v\n", bubbleArr)
+	// Each algorithm lives in the sorts package, generic over the element type
+	algorithms := []struct {
+		name string
+		sort func([]int)
+	}{
+		{"Bubble sort", sorts.Bubble[[]int]},
+		{"Selection sort", sorts.Selection[[]int]},
+		{"Insertion sort", sorts.Insertion[[]int]},
+		{"Quick sort", sorts.Quick[[]int]},
+		{"Merge sort", sorts.Merge[[]int]},
+		{"Heap sort", sorts.Heap[[]int]},
+		{"Introsort", sorts.Intro[[]int]},
+		{"Timsort", sorts.Tim[[]int]},
+	}
+	for _, alg := range algorithms {
+		arr := slices.Clone(unsorted)
+		alg.sort(arr)
+		fmt.Printf("%s: %v\n", alg.name, arr)
+	}
+
+	// Integers and strings can be sorted without comparing elements
+	ages := []uint8{34, 25, 61, 25, 18, 42, 34}
+	sorts.Counting(ages)
+	fmt.Printf("Counting sort (ages): %v\n", ages)
+
+	temps := []int{-5, 12, -30, 0, 7, -5}
+	sorts.RadixLSD(temps)
+	fmt.Printf("LSD radix sort (negatives too): %v\n", temps)
 
-	// Selection sort
-	selectionArr := make([]int, len(unsorted))
-	copy(selectionArr, unsorted)
-	selectionSort(selectionArr)
-	fmt.Printf("Selection sort: %v\n", selectionArr)
+	words := []string{"banana", "band", "ban", "apple", "bandana", "app"}
+	sorts.RadixMSDStrings(words)
+	fmt.Printf("MSD radix sort (strings): %v\n", words)
 
-	// Insertion sort
-	insertionArr := make([]int, len(unsorted))
-	copy(insertionArr, unsorted)
-	insertionSort(insertionArr)
-	fmt.Printf("Insertion sort: %v\n", insertionArr)
+	// Large slices can be sorted on every core
+	big := make([]int, 200_000)
+	for i := range big {
+		big[i] = (i * 7919) % len(big)
+	}
+	sorts.ParallelMerge(big, sorts.ParallelOptions{})
+	fmt.Printf("Parallel merge sort of %d ints sorted: %t\n", len(big), slices.IsSorted(big))
 
-	// Quick sort
-	quickArr := make([]int, len(unsorted))
-	copy(quickArr, unsorted)
-	quickSort(quickArr)
-	fmt.Printf("Quick sort: %v\n", quickArr)
+	fmt.Println("\n=== Generic Sorts with Comparators ===")
 
-	// Merge sort
-	mergeArr := mergeSort(unsorted)
-	fmt.Printf("Merge sort: %v\n", mergeArr)
+	// No sort.Interface boilerplate: pass a comparator instead
+	people2 := []Person{{"Alice", 25}, {"Bob", 30}, {"Charlie", 20}, {"Dana", 25}}
+	sorts.InsertionFunc(people2, func(a, b Person) int {
+		return cmp.Compare(a.Age, b.Age)
+	})
+	fmt.Printf("People by age (stable, Alice stays before Dana): %v\n", people2)
+
+	// A stable sort by price keeps the earlier name order for equal prices
+	products3 := []Product{
+		{"Widget D", 20.00, 2, "widgets"},
+		{"Widget C", 10.99, 8, "widgets"},
+		{"Widget B", 15.50, 3, "widgets"},
+		{"Widget A", 10.99, 5, "widgets"},
+	}
+	sorts.MergeFunc(products3, func(a, b Product) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	sorts.MergeFunc(products3, func(a, b Product) int {
+		return cmp.Compare(a.Price, b.Price)
+	})
+	fmt.Println("Products by price, then name:")
+	for _, p := range products3 {
+		fmt.Printf("  %s: $%.2f\n", p.Name, p.Price)
+	}
 
-	fmt.Println("\n=== Advanced Sorting with sort.Slice ===")
+	fmt.Println("\n=== Advanced Sorting with Multiple Keys ===")
 
 	products2 := []Product{
-		{"Widget A", 10.99, 5},
-		{"Widget B", 15.50, 3},
-		{"Widget C", 10.99, 8}, // Same price as Widget A
-		{"Widget D", 20.00, 2},
+		{"Widget A", 10.99, 5, "widgets"},
+		{"Widget B", 15.50, 3, "widgets"},
+		{"Widget C", 10.99, 8, "widgets"}, // Same price as Widget A
+		{"Widget D", 20.00, 2, "widgets"},
 	}
 
 	fmt.Println("Before custom sort:")
@@ -408,6 +388,34 @@ func main() {
 		fmt.Printf("  %s: $%.2f\n", p.Name, p.Price)
 	}
 
+	fmt.Println("\n=== Multi-Key Comparators ===")
+
+	// The order can come from a flag or query string: category A-Z, then
+	// most expensive first, then name
+	spec := "category,-price,name"
+	byInventory, err := order.Parse(spec, productFields)
+	if err != nil {
+		fmt.Println("Bad spec:", err)
+		return
+	}
+	byInventory.Sort(products)
+	fmt.Printf("Sorted by %q:\n", spec)
+	for _, p := range products {
+		fmt.Printf("  %-11s %-8s $%.2f\n", p.Category, p.Name, p.Price)
+	}
+
+	// Parse rejects unknown fields with the list of valid ones
+	if _, err := order.Parse("category,-weight", productFields); err != nil {
+		fmt.Println("Error:", err)
+	}
+
+	// People by age, oldest first, then name ignoring case
+	team := []Person{{"bob", 30}, {"Alice", 30}, {"carol", 25}, {"Dave", 35}}
+	order.By(func(p Person) int { return p.Age }).Desc().
+		ThenBy(order.ByFold(func(p Person) string { return p.Name })).
+		Sort(team)
+	fmt.Printf("Team by age desc, then name: %v\n", team)
+
 	fmt.Println("\n=== Utility Functions ===")
 
 	// Find min/max
@@ -0,0 +1,159 @@
+// Package order builds multi-key comparators from key functions instead of
+// hand-written closures:
+//
+//	byCategory := order.By(func(p Product) string { return p.Category })
+//	byPrice := order.By(func(p Product) float64 { return p.Price })
+//	byName := order.ByFold(func(p Product) string { return p.Name })
+//
+//	order.By(...).ThenBy(byPrice).Desc().ThenBy(byName).Sort(products)
+//
+// Each key compares ascending. Desc reverses everything the last By or
+// ThenBy added, so a multi-key order passed to ThenBy is reversed as a
+// whole, as Parse does for "-field"; NilsLast modifies the last key.
+// Parse builds the same thing from a spec such as "category,-price,name",
+// so the order can come from a flag or a query string.
+package order
+
+import (
+	"cmp"
+	"unicode"
+	"unicode/utf8"
+
+	"grok-study-plan/07-sorting-searching/sorts"
+)
+
+// key is one criterion of an Order
+type key[T any] struct {
+	compare func(a, b T) int
+	desc    bool
+	// isNil reports a missing key value, for ByPtr keys; nil values sort
+	// first unless nilsLast is set, whatever the direction
+	isNil    func(T) bool
+	nilsLast bool
+}
+
+func (k key[T]) apply(a, b T) int {
+	if k.isNil != nil {
+		aNil, bNil := k.isNil(a), k.isNil(b)
+		switch {
+		case aNil && bNil:
+			return 0
+		case aNil != bNil:
+			if aNil != k.nilsLast {
+				return -1
+			}
+			return 1
+		}
+	}
+	c := k.compare(a, b)
+	if k.desc {
+		return -c
+	}
+	return c
+}
+
+// Order is a comparator made of keys compared in turn, the first one that
+// differs deciding. Build one with By, ByFold, ByPtr or ByFunc and extend it
+// with ThenBy. ThenBy, Desc, NilsLast and Stable modify the Order they are
+// called on and return it, so build shared orders in a function rather than
+// extending one variable in two ways.
+type Order[T any] struct {
+	keys   []key[T]
+	group  int // index of the first key added by the last ThenBy
+	stable bool
+}
+
+// By orders values by the key function's result
+func By[T any, K cmp.Ordered](fn func(T) K) *Order[T] {
+	return ByFunc(func(a, b T) int {
+		return cmp.Compare(fn(a), fn(b))
+	})
+}
+
+// ByFold orders values by a string key, ignoring case
+func ByFold[T any](fn func(T) string) *Order[T] {
+	return ByFunc(func(a, b T) int {
+		return compareFold(fn(a), fn(b))
+	})
+}
+
+// ByPtr orders values by a key that may be missing. Values whose key is nil
+// sort first, or last after NilsLast, in either direction.
+func ByPtr[T any, K cmp.Ordered](fn func(T) *K) *Order[T] {
+	o := ByFunc(func(a, b T) int {
+		return cmp.Compare(*fn(a), *fn(b))
+	})
+	o.keys[0].isNil = func(v T) bool { return fn(v) == nil }
+	return o
+}
+
+// ByFunc orders values with an existing comparator
+func ByFunc[T any](compare func(a, b T) int) *Order[T] {
+	return &Order[T]{keys: []key[T]{{compare: compare}}}
+}
+
+// ThenBy appends next's keys, used when all keys so far are equal. next
+// itself is not modified.
+func (o *Order[T]) ThenBy(next *Order[T]) *Order[T] {
+	o.group = len(o.keys)
+	o.keys = append(o.keys, next.keys...)
+	return o
+}
+
+// Desc reverses the keys added last: all of next's keys after ThenBy(next),
+// or the whole Order when ThenBy hasn't been called
+func (o *Order[T]) Desc() *Order[T] {
+	for i := o.group; i < len(o.keys); i++ {
+		o.keys[i].desc = !o.keys[i].desc
+	}
+	return o
+}
+
+// NilsLast puts nil values of the last ByPtr key after the others
+func (o *Order[T]) NilsLast() *Order[T] {
+	o.keys[len(o.keys)-1].nilsLast = true
+	return o
+}
+
+// Stable makes Sort keep equal values in their original order
+func (o *Order[T]) Stable() *Order[T] {
+	o.stable = true
+	return o
+}
+
+// Compare compares a and b key by key, for use with slices.SortFunc,
+// slices.BinarySearchFunc and the sorts package
+func (o *Order[T]) Compare(a, b T) int {
+	for _, k := range o.keys {
+		if c := k.apply(a, b); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// Sort sorts s in place; it is stable if Stable was called
+func (o *Order[T]) Sort(s []T) {
+	if o.stable {
+		sorts.TimFunc(s, o.Compare)
+		return
+	}
+	sorts.IntroFunc(s, o.Compare)
+}
+
+// compareFold compares strings rune by rune after simple case folding to
+// lower case, without allocating lowered copies
+func compareFold(a, b string) int {
+	for a != "" && b != "" {
+		ra, na := utf8.DecodeRuneInString(a)
+		rb, nb := utf8.DecodeRuneInString(b)
+		if ra != rb {
+			la, lb := unicode.ToLower(ra), unicode.ToLower(rb)
+			if la != lb {
+				return cmp.Compare(la, lb)
+			}
+		}
+		a, b = a[na:], b[nb:]
+	}
+	return cmp.Compare(len(a), len(b))
+}
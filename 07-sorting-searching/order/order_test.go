@@ -0,0 +1,214 @@
+package order
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type product struct {
+	Name     string
+	Category string
+	Price    float64
+	Brand    *string
+}
+
+func ptr(s string) *string { return &s }
+
+var (
+	byName     = ByFold(func(p product) string { return p.Name })
+	byCategory = By(func(p product) string { return p.Category })
+	byPrice    = By(func(p product) float64 { return p.Price })
+	byBrand    = ByPtr(func(p product) *string { return p.Brand })
+)
+
+func names(ps []product) string {
+	var out []string
+	for _, p := range ps {
+		out = append(out, p.Name)
+	}
+	return strings.Join(out, ",")
+}
+
+func catalog() []product {
+	return []product{
+		{"mouse", "accessories", 25.5, ptr("Logi")},
+		{"Laptop", "computers", 999.99, nil},
+		{"cable", "accessories", 9.99, ptr("Anker")},
+		{"Desktop", "computers", 999.99, ptr("Dell")},
+		{"Keyboard", "accessories", 75, nil},
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	testCases := []struct {
+		name  string
+		order *Order[product]
+		want  string
+	}{
+		{"name ignores case", ByFold(func(p product) string { return p.Name }), "cable,Desktop,Keyboard,Laptop,mouse"},
+		{"case-sensitive name", By(func(p product) string { return p.Name }), "Desktop,Keyboard,Laptop,cable,mouse"},
+		{"category then price desc", By(func(p product) string { return p.Category }).ThenBy(byPrice).Desc(), "Keyboard,mouse,cable,Laptop,Desktop"},
+		{"category, price desc, name", By(func(p product) string { return p.Category }).ThenBy(byPrice).Desc().ThenBy(byName), "Keyboard,mouse,cable,Desktop,Laptop"},
+		{"category desc", By(func(p product) string { return p.Category }).Desc().ThenBy(byName), "Desktop,Laptop,cable,Keyboard,mouse"},
+		{"nil brands first", ByPtr(func(p product) *string { return p.Brand }).ThenBy(byName), "Keyboard,Laptop,cable,Desktop,mouse"},
+		{"nil brands last", ByPtr(func(p product) *string { return p.Brand }).NilsLast().ThenBy(byName), "cable,Desktop,mouse,Keyboard,Laptop"},
+		{"brand desc keeps nils first", ByPtr(func(p product) *string { return p.Brand }).Desc().ThenBy(byName), "Keyboard,Laptop,mouse,Desktop,cable"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ps := catalog()
+			tc.order.Sort(ps)
+			if got := names(ps); got != tc.want {
+				t.Errorf("got %s; want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestThenByDoesNotModifyArgument(t *testing.T) {
+	price := By(func(p product) float64 { return p.Price })
+	By(func(p product) string { return p.Category }).ThenBy(price).Desc()
+
+	ps := catalog()
+	price.Sort(ps)
+	if !slices.IsSortedFunc(ps, func(a, b product) int { return cmp.Compare(a.Price, b.Price) }) {
+		t.Errorf("Desc on the chain reversed the shared key: %s", names(ps))
+	}
+}
+
+func TestStable(t *testing.T) {
+	type row struct {
+		group int
+		seq   int
+	}
+	rows := make([]row, 300)
+	for i := range rows {
+		rows[i] = row{group: (i * 7) % 5, seq: i}
+	}
+	By(func(r row) int { return r.group }).Stable().Sort(rows)
+	for i := 1; i < len(rows); i++ {
+		if rows[i-1].group == rows[i].group && rows[i-1].seq > rows[i].seq {
+			t.Fatalf("Stable sort reordered equal rows at %d: %v, %v", i, rows[i-1], rows[i])
+		}
+	}
+}
+
+func TestCompareFold(t *testing.T) {
+	testCases := []struct {
+		a, b string
+		want int
+	}{
+		{"apple", "APPLE", 0},
+		{"apple", "Banana", -1},
+		{"Zebra", "apple", 1},
+		{"app", "APPLE", -1},
+		{"Éclair", "éclair", 0},
+		{"", "", 0},
+	}
+	for _, tc := range testCases {
+		if got := compareFold(tc.a, tc.b); got != tc.want {
+			t.Errorf("compareFold(%q, %q) = %d; want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+var fields = Fields[product]{
+	"name":     byName,
+	"category": byCategory,
+	"price":    byPrice,
+	"brand":    byBrand,
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		spec string
+		want string
+	}{
+		{"category,-price,name", "Keyboard,mouse,cable,Desktop,Laptop"},
+		{" +category , -price , -name ", "Keyboard,mouse,cable,Laptop,Desktop"},
+		{"-price", ""}, // ties between Laptop and Desktop; checked below
+		{"brand,name", "Keyboard,Laptop,cable,Desktop,mouse"},
+	}
+	for _, tc := range testCases {
+		o, err := Parse(tc.spec, fields)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tc.spec, err)
+		}
+		ps := catalog()
+		o.Sort(ps)
+		if tc.want != "" && names(ps) != tc.want {
+			t.Errorf("Parse(%q) sorted %s; want %s", tc.spec, names(ps), tc.want)
+		}
+		if tc.spec == "-price" && ps[0].Price != 999.99 {
+			t.Errorf("Parse(-price) put %s first", ps[0].Name)
+		}
+	}
+}
+
+// A field built from several keys is reversed as a whole
+func TestParseMultiKeyField(t *testing.T) {
+	f := Fields[product]{
+		"shelf": By(func(p product) string { return p.Category }).ThenBy(byName),
+	}
+	o, err := Parse("-shelf", f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps := catalog()
+	o.Sort(ps)
+	if got, want := names(ps), "Laptop,Desktop,mouse,Keyboard,cable"; got != want {
+		t.Errorf("got %s; want %s", got, want)
+	}
+}
+
+// Desc after ThenBy reverses every key of the group, so the builder and
+// Parse agree
+func TestDescReversesThenByGroup(t *testing.T) {
+	shelf := func() *Order[product] {
+		return By(func(p product) string { return p.Category }).ThenBy(byName)
+	}
+	built := ByFunc(func(a, b product) int { return 0 }).ThenBy(shelf()).Desc()
+	parsed, err := Parse("-shelf", Fields[product]{"shelf": shelf()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, b := catalog(), catalog()
+	built.Sort(a)
+	parsed.Sort(b)
+	if names(a) != names(b) || names(a) != "Laptop,Desktop,mouse,Keyboard,cable" {
+		t.Errorf("builder sorted %s, Parse sorted %s; want both Laptop,Desktop,mouse,Keyboard,cable", names(a), names(b))
+	}
+
+	// Inside shelf, the group is byName alone
+	ps := catalog()
+	shelf().Desc().Sort(ps)
+	if got, want := names(ps), "mouse,Keyboard,cable,Laptop,Desktop"; got != want {
+		t.Errorf("shelf().Desc() sorted %s; want %s", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := []struct {
+		spec string
+		want string
+	}{
+		{"", "empty"},
+		{"  ", "empty"},
+		{"weight", `unknown field "weight" (have brand, category, name, price)`},
+		{"name,-name", `field "name" used twice`},
+		{"name,,price", `unknown field ""`},
+		{"+-price", `"+-price" has more than one sign`},
+		{"--price", `"--price" has more than one sign`},
+		{"-", `unknown field ""`},
+	}
+	for _, tc := range testCases {
+		_, err := Parse(tc.spec, fields)
+		if !errors.Is(err, ErrBadSpec) || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Parse(%q) error = %v; want ErrBadSpec mentioning %q", tc.spec, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Fields names the keys a spec may use, such as
+//
+//	order.Fields[Product]{
+//	    "name":  order.ByFold(func(p Product) string { return p.Name }),
+//	    "price": order.By(func(p Product) float64 { return p.Price }),
+//	}
+type Fields[T any] map[string]*Order[T]
+
+// ErrBadSpec is wrapped by every error Parse returns
+var ErrBadSpec = errors.New("invalid sort spec")
+
+// Parse turns a comma-separated spec such as "category,-price,name" into
+// an Order. Each item names a field; a leading "-" sorts it descending and
+// an optional "+" ascending, and at most one sign is allowed. Call Stable on
+// the result for a stable sort.
+func Parse[T any](spec string, fields Fields[T]) (*Order[T], error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, fmt.Errorf("%w: empty", ErrBadSpec)
+	}
+
+	var o *Order[T]
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(spec, ",") {
+		name := strings.TrimSpace(item)
+		desc := strings.HasPrefix(name, "-")
+		if desc || strings.HasPrefix(name, "+") {
+			name = name[1:]
+		}
+		if strings.HasPrefix(name, "+") || strings.HasPrefix(name, "-") {
+			return nil, fmt.Errorf("%w: %q has more than one sign", ErrBadSpec, strings.TrimSpace(item))
+		}
+
+		field, ok := fields[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown field %q (have %s)",
+				ErrBadSpec, name, strings.Join(slices.Sorted(maps.Keys(fields)), ", "))
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("%w: field %q used twice", ErrBadSpec, name)
+		}
+		seen[name] = true
+
+		if o == nil {
+			o = &Order[T]{}
+		}
+		o.ThenBy(field)
+		if desc {
+			o.Desc()
+		}
+	}
+	return o, nil
+}
@@ -0,0 +1,64 @@
+package search
+
+import "math"
+
+// Integer is any integer type, the domain of FirstTrue
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// FirstTrue returns the least x in [lo, hi) for which pred is true, or hi if
+// there is none. pred must be monotonic: false up to some point and true
+// from there on. It is called O(log(hi-lo)) times, and hi-lo must fit in T.
+//
+// This is binary search on the answer. For example, the smallest daily
+// capacity that ships every package within five days:
+//
+//	capacity := search.FirstTrue(maxWeight, totalWeight+1, func(c int) bool {
+//	    return daysNeeded(weights, c) <= 5
+//	})
+//
+// The largest x for which a predicate holds is one less than FirstTrue of
+// its negation.
+func FirstTrue[T Integer](lo, hi T, pred func(T) bool) T {
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if pred(mid) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo
+}
+
+// maxBisections bounds the float searches, which otherwise stop when the
+// interval is within tol. 200 steps shrink it by a factor of at least 10³⁵,
+// far past any useful tol, and keep a tol of 0 from looping forever.
+const maxBisections = 200
+
+// FirstTrueFloat is FirstTrue over the reals: it narrows [lo, hi] until it
+// is at most tol wide and returns its upper end, the smallest x seen for
+// which pred is true. pred is assumed true at hi; if it is true nowhere in
+// the interval the result is hi.
+//
+//	sqrt2 := search.FirstTrueFloat(0, 2, 1e-9, func(x float64) bool { return x*x >= 2 })
+func FirstTrueFloat(lo, hi, tol float64, pred func(float64) bool) float64 {
+	tol = math.Max(tol, 0)
+	for range maxBisections {
+		if hi-lo <= tol {
+			break
+		}
+		mid := lo + (hi-lo)/2
+		if mid == lo || mid == hi {
+			break // no float between them
+		}
+		if pred(mid) {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi
+}
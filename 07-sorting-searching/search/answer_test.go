@@ -0,0 +1,114 @@
+package search
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFirstTrue(t *testing.T) {
+	testCases := []struct {
+		name   string
+		lo, hi int
+		pred   func(int) bool
+		want   int
+	}{
+		{"boundary inside", 0, 100, func(x int) bool { return x*x >= 50 }, 8},
+		{"true everywhere", 3, 10, func(int) bool { return true }, 3},
+		{"true nowhere", 3, 10, func(int) bool { return false }, 10},
+		{"empty range", 5, 5, func(int) bool { return true }, 5},
+		{"negative domain", -100, 0, func(x int) bool { return x >= -37 }, -37},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := FirstTrue(tc.lo, tc.hi, tc.pred); got != tc.want {
+				t.Errorf("FirstTrue(%d, %d) = %d; want %d", tc.lo, tc.hi, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFirstTrueShipWithinDays(t *testing.T) {
+	weights := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	daysNeeded := func(capacity int) int {
+		days, load := 1, 0
+		for _, w := range weights {
+			if load+w > capacity {
+				days++
+				load = 0
+			}
+			load += w
+		}
+		return days
+	}
+
+	got := FirstTrue(10, 56, func(c int) bool { return daysNeeded(c) <= 5 })
+	if got != 15 {
+		t.Errorf("least capacity for 5 days = %d; want 15", got)
+	}
+}
+
+func TestFirstTrueUnsignedFullRange(t *testing.T) {
+	// hi-lo is the whole of uint64, which would overflow a signed midpoint
+	got := FirstTrue(uint64(0), math.MaxUint64, func(x uint64) bool { return x >= 1<<63+5 })
+	if got != 1<<63+5 {
+		t.Errorf("FirstTrue = %d; want %d", got, uint64(1<<63+5))
+	}
+}
+
+func TestFirstTrueFloat(t *testing.T) {
+	got := FirstTrueFloat(0, 2, 1e-12, func(x float64) bool { return x*x >= 2 })
+	if math.Abs(got-math.Sqrt2) > 1e-12 || got*got < 2 {
+		t.Errorf("FirstTrueFloat(sqrt 2) = %v; want %v from above", got, math.Sqrt2)
+	}
+
+	// A tol of 0 must still stop, at adjacent floats
+	got = FirstTrueFloat(0, 1, 0, func(x float64) bool { return x >= 0.3 })
+	if got < 0.3 || math.Nextafter(got, 0) >= 0.3 {
+		t.Errorf("FirstTrueFloat(tol 0) = %v; want the least float >= 0.3", got)
+	}
+}
+
+func TestTernary(t *testing.T) {
+	peak := func(x float64) float64 { return -(x - 1.7) * (x - 1.7) }
+	if got := TernaryMax(-10, 10, 1e-9, peak); math.Abs(got-1.7) > 1e-6 {
+		t.Errorf("TernaryMax = %v; want 1.7", got)
+	}
+
+	valley := func(x float64) float64 { return math.Cosh(x + 0.4) }
+	if got := TernaryMin(-5, 5, 1e-9, valley); math.Abs(got+0.4) > 1e-6 {
+		t.Errorf("TernaryMin = %v; want -0.4", got)
+	}
+
+	// Projectile range peaks at 45 degrees
+	distance := func(angle float64) float64 { return math.Sin(2 * angle) }
+	if got := TernaryMax(0, math.Pi/2, 1e-12, distance); math.Abs(got-math.Pi/4) > 1e-6 {
+		t.Errorf("TernaryMax(range) = %v; want %v", got, math.Pi/4)
+	}
+}
+
+func TestTernaryInt(t *testing.T) {
+	mountain := []int{1, 3, 8, 12, 4, 2}
+	at := func(i int) int { return mountain[i] }
+	if got := TernaryMaxInt(0, len(mountain), at); got != 3 {
+		t.Errorf("TernaryMaxInt(mountain) = %d; want 3", got)
+	}
+
+	testCases := []struct {
+		name string
+		s    []int
+		want int
+	}{
+		{"valley", []int{9, 5, 2, 4, 7}, 2},
+		{"falling", []int{5, 4, 3}, 2},
+		{"rising", []int{1, 2, 3}, 0},
+		{"single", []int{7}, 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := TernaryMinInt(0, len(tc.s), func(i int) int { return tc.s[i] })
+			if got != tc.want {
+				t.Errorf("TernaryMinInt(%v) = %d; want %d", tc.s, got, tc.want)
+			}
+		})
+	}
+}
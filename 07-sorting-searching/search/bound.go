@@ -0,0 +1,74 @@
+// Package search finds things in sorted data and in monotonic or unimodal
+// functions. Instead of an index or -1, the slice searches return insertion
+// points, so a miss still says where the target belongs:
+//
+//	s := []int{10, 20, 20, 20, 30}
+//	search.LowerBound(s, 20)  // 1, the first 20
+//	search.UpperBound(s, 20)  // 4, just past the last 20
+//	search.EqualRange(s, 25)  // 4, 4: nothing equal, 25 belongs at 4
+//
+// Each slice search has an ordered form and a Func form that takes a
+// comparator, like slices.BinarySearch and slices.BinarySearchFunc. The
+// comparator compares an element with the target, so a slice of structs can
+// be searched by a key without building a struct to compare with.
+//
+// Beyond binary search on slices there is:
+//
+//	Exponential    sorted data of unknown length, such as a stream or a paged API
+//	Interpolation  sorted numbers spread roughly evenly, in O(log log n) probes
+//	TernaryMax     the peak of a unimodal function (TernaryMin for the valley)
+//	FirstTrue      binary search on the answer: the least x where a
+//	               monotonic predicate holds (FirstTrueFloat for reals)
+package search
+
+import "cmp"
+
+// LowerBound returns the first index i with s[i] >= target, or len(s) if
+// every element is smaller. s must be sorted in increasing order.
+func LowerBound[S ~[]E, E cmp.Ordered](s S, target E) int {
+	return LowerBoundFunc(s, target, cmp.Compare[E])
+}
+
+// LowerBoundFunc is LowerBound with a comparator that returns a negative
+// number when an element sorts before the target, zero when it matches and
+// a positive number when it sorts after
+func LowerBoundFunc[S ~[]E, E, T any](s S, target T, compare func(E, T) int) int {
+	return FirstTrue(0, len(s), func(i int) bool { return compare(s[i], target) >= 0 })
+}
+
+// UpperBound returns the first index i with s[i] > target, or len(s) if no
+// element is greater
+func UpperBound[S ~[]E, E cmp.Ordered](s S, target E) int {
+	return UpperBoundFunc(s, target, cmp.Compare[E])
+}
+
+// UpperBoundFunc is UpperBound with a comparator, as for LowerBoundFunc
+func UpperBoundFunc[S ~[]E, E, T any](s S, target T, compare func(E, T) int) int {
+	return FirstTrue(0, len(s), func(i int) bool { return compare(s[i], target) > 0 })
+}
+
+// EqualRange returns the half-open range [lo, hi) of elements equal to
+// target. When there are none, lo == hi is where target would be inserted.
+func EqualRange[S ~[]E, E cmp.Ordered](s S, target E) (lo, hi int) {
+	return EqualRangeFunc(s, target, cmp.Compare[E])
+}
+
+// EqualRangeFunc is EqualRange with a comparator, as for LowerBoundFunc
+func EqualRangeFunc[S ~[]E, E, T any](s S, target T, compare func(E, T) int) (lo, hi int) {
+	lo = LowerBoundFunc(s, target, compare)
+	// The upper bound can only be at or after the lower bound
+	hi = lo + UpperBoundFunc(s[lo:], target, compare)
+	return lo, hi
+}
+
+// Find returns the index of the first element equal to target and true, or
+// the insertion point and false when there is none
+func Find[S ~[]E, E cmp.Ordered](s S, target E) (int, bool) {
+	return FindFunc(s, target, cmp.Compare[E])
+}
+
+// FindFunc is Find with a comparator, as for LowerBoundFunc
+func FindFunc[S ~[]E, E, T any](s S, target T, compare func(E, T) int) (int, bool) {
+	i := LowerBoundFunc(s, target, compare)
+	return i, i < len(s) && compare(s[i], target) == 0
+}
@@ -0,0 +1,61 @@
+package search
+
+import (
+	"cmp"
+	"math"
+)
+
+// Exponential searches sorted data whose length is unknown or unbounded.
+// at(i) returns element i, with ok false once i is past the end. It probes
+// indices 0, 1, 3, 7, 15, ... until it passes target, then binary searches
+// the last gap, so finding index k costs O(log k) calls to at however long
+// the data is.
+//
+// It returns the first index i with at(i) >= target, or the end of the data,
+// and whether at(i) equals target.
+//
+//	// First record at or after since, fetching pages only as far as needed
+//	i, _ := search.Exponential(func(i int) (int64, bool) {
+//	    return store.TimestampAt(i)
+//	}, since)
+func Exponential[E cmp.Ordered](at func(i int) (E, bool), target E) (int, bool) {
+	return ExponentialFunc(at, target, cmp.Compare[E])
+}
+
+// ExponentialFunc is Exponential with a comparator, as for LowerBoundFunc
+func ExponentialFunc[E, T any](at func(i int) (E, bool), target T, compare func(E, T) int) (int, bool) {
+	// atOrPast reports whether index i holds target or something after it;
+	// the end of the data counts as after everything
+	atOrPast := func(i int) bool {
+		v, ok := at(i)
+		return !ok || compare(v, target) >= 0
+	}
+
+	// Everything before lo is known to be smaller than target; hi-1 is the
+	// first probe at or past it
+	lo, hi := 0, 1
+	for !atOrPast(hi - 1) {
+		lo = hi
+		if hi > math.MaxInt/2 {
+			hi = math.MaxInt
+			break
+		}
+		hi *= 2
+	}
+
+	i := FirstTrue(lo, hi-1, atOrPast)
+	v, ok := at(i)
+	return i, ok && compare(v, target) == 0
+}
+
+// At adapts a slice for Exponential, which is useful when the target is
+// expected near the front of a long slice
+func At[S ~[]E, E any](s S) func(i int) (E, bool) {
+	return func(i int) (E, bool) {
+		if i < 0 || i >= len(s) {
+			var zero E
+			return zero, false
+		}
+		return s[i], true
+	}
+}
@@ -0,0 +1,43 @@
+package search
+
+// Number is any integer or floating-point type, the element type of
+// Interpolation
+type Number interface {
+	Integer | ~float32 | ~float64
+}
+
+// Interpolation searches sorted numbers by guessing where target lies from
+// its value, the way one opens a phone book near the right letter. On
+// roughly uniform data it needs O(log log n) probes; on skewed data, such
+// as exponential growth, it can degrade to O(n), so binary search is the
+// safer default. s must not contain NaNs.
+//
+// Like Find, it returns the index of the first element equal to target and
+// true, or the insertion point and false.
+func Interpolation[S ~[]E, E Number](s S, target E) (int, bool) {
+	// Everything before lo is smaller than target; everything after hi is
+	// at least target
+	lo, hi := 0, len(s)-1
+	for lo <= hi {
+		if target <= s[lo] {
+			break
+		}
+		if target > s[hi] {
+			lo = hi + 1
+			break
+		}
+
+		// Now s[lo] < target <= s[hi], so the span is not zero. Converting
+		// to float64 avoids overflow in the subtraction and the product.
+		frac := (float64(target) - float64(s[lo])) / (float64(s[hi]) - float64(s[lo]))
+		pos := lo + int(frac*float64(hi-lo))
+		pos = min(max(pos, lo), hi)
+
+		if s[pos] < target {
+			lo = pos + 1
+		} else {
+			hi = pos - 1
+		}
+	}
+	return lo, lo < len(s) && s[lo] == target
+}
@@ -0,0 +1,157 @@
+package search
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// sortedInputs returns sorted slices with gaps, runs of duplicates and
+// skewed spacing
+func sortedInputs(r *rand.Rand) map[string][]int {
+	cases := map[string][]int{
+		"empty":  {},
+		"single": {5},
+		"same":   {3, 3, 3, 3},
+		"gaps":   {10, 20, 20, 20, 30},
+		"skewed": {1, 2, 4, 8, 16, 32, 64, 128, 1 << 20, 1 << 40},
+	}
+	for _, n := range []int{7, 100, 1000} {
+		uniform := make([]int, n)
+		dups := make([]int, n)
+		for i := range n {
+			uniform[i] = r.IntN(10 * n)
+			dups[i] = r.IntN(5)
+		}
+		slices.Sort(uniform)
+		slices.Sort(dups)
+		cases["uniform/"+strconv.Itoa(n)] = uniform
+		cases["duplicates/"+strconv.Itoa(n)] = dups
+	}
+	return cases
+}
+
+// targets returns each value in s, its neighbours, and values beyond both ends
+func targets(s []int) []int {
+	ts := []int{-1 << 50, 0, 1 << 50}
+	for _, v := range s {
+		ts = append(ts, v-1, v, v+1)
+	}
+	return ts
+}
+
+// bounds computes the expected answers by scanning
+func bounds(s []int, target int) (lo, hi int) {
+	for lo < len(s) && s[lo] < target {
+		lo++
+	}
+	for hi = lo; hi < len(s) && s[hi] == target; hi++ {
+	}
+	return lo, hi
+}
+
+func TestBounds(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for name, s := range sortedInputs(r) {
+		t.Run(name, func(t *testing.T) {
+			for _, target := range targets(s) {
+				wantLo, wantHi := bounds(s, target)
+				if got := LowerBound(s, target); got != wantLo {
+					t.Errorf("LowerBound(%d) = %d; want %d", target, got, wantLo)
+				}
+				if got := UpperBound(s, target); got != wantHi {
+					t.Errorf("UpperBound(%d) = %d; want %d", target, got, wantHi)
+				}
+				if lo, hi := EqualRange(s, target); lo != wantLo || hi != wantHi {
+					t.Errorf("EqualRange(%d) = %d, %d; want %d, %d", target, lo, hi, wantLo, wantHi)
+				}
+
+				want, wantFound := slices.BinarySearch(s, target)
+				if got, found := Find(s, target); got != want || found != wantFound {
+					t.Errorf("Find(%d) = %d, %t; want %d, %t", target, got, found, want, wantFound)
+				}
+				if got, found := Exponential(At(s), target); got != want || found != wantFound {
+					t.Errorf("Exponential(%d) = %d, %t; want %d, %t", target, got, found, want, wantFound)
+				}
+				if got, found := Interpolation(s, target); got != want || found != wantFound {
+					t.Errorf("Interpolation(%d) = %d, %t; want %d, %t", target, got, found, want, wantFound)
+				}
+			}
+		})
+	}
+}
+
+func TestFuncFormsSearchByKey(t *testing.T) {
+	type user struct {
+		Name string
+		Age  int
+	}
+	users := []user{{"ann", 20}, {"bob", 25}, {"cat", 25}, {"dan", 31}}
+	byAge := func(u user, age int) int { return cmp.Compare(u.Age, age) }
+
+	if lo, hi := EqualRangeFunc(users, 25, byAge); lo != 1 || hi != 3 {
+		t.Errorf("EqualRangeFunc(25) = %d, %d; want 1, 3", lo, hi)
+	}
+	if i, found := FindFunc(users, 30, byAge); i != 3 || found {
+		t.Errorf("FindFunc(30) = %d, %t; want 3, false", i, found)
+	}
+	if i, found := ExponentialFunc(At(users), 31, byAge); i != 3 || !found {
+		t.Errorf("ExponentialFunc(31) = %d, %t; want 3, true", i, found)
+	}
+}
+
+func TestExponentialProbesNearTheFront(t *testing.T) {
+	// An endless stream of even numbers; the search must not walk to the end
+	calls := 0
+	evens := func(i int) (int, bool) {
+		calls++
+		return 2 * i, true
+	}
+
+	testCases := []struct {
+		target    int
+		want      int
+		wantFound bool
+		maxCalls  int
+	}{
+		{0, 0, true, 2},
+		{7, 4, false, 8},
+		{2000, 1000, true, 25},
+	}
+	for _, tc := range testCases {
+		calls = 0
+		got, found := Exponential(evens, tc.target)
+		if got != tc.want || found != tc.wantFound {
+			t.Errorf("Exponential(%d) = %d, %t; want %d, %t", tc.target, got, found, tc.want, tc.wantFound)
+		}
+		if calls > tc.maxCalls {
+			t.Errorf("Exponential(%d) made %d calls; want at most %d", tc.target, calls, tc.maxCalls)
+		}
+	}
+}
+
+func TestInterpolationFloats(t *testing.T) {
+	s := []float64{-2.5, -1, 0, 0, 0.5, 3.25, 100}
+	for _, target := range []float64{-3, -2.5, -1.5, 0, 0.25, 3.25, 99, 100, 101} {
+		want, wantFound := slices.BinarySearch(s, target)
+		if got, found := Interpolation(s, target); got != want || found != wantFound {
+			t.Errorf("Interpolation(%v) = %d, %t; want %d, %t", target, got, found, want, wantFound)
+		}
+	}
+}
+
+func TestInterpolationExtremeValues(t *testing.T) {
+	// The span overflows int64 and uint8 arithmetic; the estimate must not
+	s := []int64{-1 << 63, -5, 0, 7, 1<<63 - 1}
+	for i, v := range s {
+		if got, found := Interpolation(s, v); got != i || !found {
+			t.Errorf("Interpolation(%d) = %d, %t; want %d, true", v, got, found, i)
+		}
+	}
+	u := []uint8{0, 1, 200, 255}
+	if got, found := Interpolation(u, 199); got != 2 || found {
+		t.Errorf("Interpolation(uint8 199) = %d, %t; want 2, false", got, found)
+	}
+}
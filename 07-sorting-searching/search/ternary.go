@@ -0,0 +1,66 @@
+package search
+
+import (
+	"cmp"
+	"math"
+)
+
+// TernaryMax returns x in [lo, hi], within tol, where the unimodal f is
+// greatest: f rises up to the peak and falls after it. Each step evaluates f
+// at the two points a third of the way in from each end and discards the
+// third that cannot hold the peak, so it takes about log₁.₅((hi-lo)/tol)
+// steps. Flat stretches, other than the peak itself, can mislead it.
+//
+//	// The launch angle that throws furthest
+//	angle := search.TernaryMax(0, math.Pi/2, 1e-9, distance)
+func TernaryMax(lo, hi, tol float64, f func(float64) float64) float64 {
+	return ternary(lo, hi, tol, func(a, b float64) bool { return f(a) < f(b) })
+}
+
+// TernaryMin is TernaryMax for a function that falls to a single minimum
+// and rises after it
+func TernaryMin(lo, hi, tol float64, f func(float64) float64) float64 {
+	return ternary(lo, hi, tol, func(a, b float64) bool { return f(a) > f(b) })
+}
+
+// ternary narrows [lo, hi] towards the extremum; worse reports whether a is
+// further from it than b
+func ternary(lo, hi, tol float64, worse func(a, b float64) bool) float64 {
+	tol = math.Max(tol, 0)
+	for range maxBisections {
+		if hi-lo <= tol {
+			break
+		}
+		m1 := lo + (hi-lo)/3
+		m2 := hi - (hi-lo)/3
+		if m1 <= lo && m2 >= hi {
+			break // no float between them
+		}
+		if worse(m1, m2) {
+			lo = m1
+		} else {
+			hi = m2
+		}
+	}
+	return lo + (hi-lo)/2
+}
+
+// TernaryMaxInt returns the x in [lo, hi) where f is greatest, for f strictly
+// increasing up to its peak and strictly decreasing after it. Over integers
+// the peak is the first x whose successor is no greater, so it compares
+// neighbours with FirstTrue rather than thirds, using fewer calls to f.
+func TernaryMaxInt[T Integer, V cmp.Ordered](lo, hi T, f func(T) V) T {
+	if lo >= hi {
+		return lo
+	}
+	return FirstTrue(lo, hi-1, func(x T) bool { return f(x) >= f(x+1) })
+}
+
+// TernaryMinInt is TernaryMaxInt for a strictly decreasing then strictly
+// increasing f
+func TernaryMinInt[T Integer, V cmp.Ordered](lo, hi T, f func(T) V) T {
+	if lo >= hi {
+		return lo
+	}
+	return FirstTrue(lo, hi-1, func(x T) bool { return f(x) <= f(x+1) })
+}
@@ -0,0 +1,111 @@
+package sorts
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// shapes are the inputs every benchmark runs on
+func shapes(n int) map[string][]int {
+	r := rand.New(rand.NewPCG(7, 8))
+	sorted := make([]int, n)
+	reversed := make([]int, n)
+	random := make([]int, n)
+	dups := make([]int, n)
+	for i := range n {
+		sorted[i] = i
+		reversed[i] = n - i
+		random[i] = r.IntN(1 << 30)
+		dups[i] = r.IntN(8)
+	}
+	return map[string][]int{
+		"sorted":     sorted,
+		"reversed":   reversed,
+		"random":     random,
+		"duplicates": dups,
+	}
+}
+
+var shapeOrder = []string{"sorted", "reversed", "random", "duplicates"}
+
+// BenchmarkQuadratic compares the O(n²) sorts on a size they can manage
+func BenchmarkQuadratic(b *testing.B) {
+	benchmarkInts(b, 1000, map[string]func([]int){
+		"Bubble":    Bubble[[]int],
+		"Selection": Selection[[]int],
+		"Insertion": Insertion[[]int],
+	})
+}
+
+func BenchmarkInts(b *testing.B) {
+	benchmarkInts(b, 100_000, map[string]func([]int){
+		"Quick":    Quick[[]int],
+		"Merge":    Merge[[]int],
+		"Heap":     Heap[[]int],
+		"Intro":    Intro[[]int],
+		"Tim":      Tim[[]int],
+		"Counting": Counting[[]int],
+		"RadixLSD": RadixLSD[[]int],
+		"RadixMSD": RadixMSD[[]int],
+		"slices":   slices.Sort[[]int],
+	})
+}
+
+func benchmarkInts(b *testing.B, n int, sorts map[string]func([]int)) {
+	inputs := shapes(n)
+	names := make([]string, 0, len(sorts))
+	for name := range sorts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	buf := make([]int, n)
+	for _, shape := range shapeOrder {
+		for _, name := range names {
+			b.Run(shape+"/"+name, func(b *testing.B) {
+				for b.Loop() {
+					copy(buf, inputs[shape])
+					sorts[name](buf)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkStrings(b *testing.B) {
+	r := rand.New(rand.NewPCG(9, 10))
+	const n = 50_000
+	inputs := map[string][]string{
+		"random":     make([]string, n),
+		"prefixed":   make([]string, n),
+		"duplicates": make([]string, n),
+	}
+	for i := range n {
+		inputs["random"][i] = strconv.FormatUint(r.Uint64(), 36)
+		inputs["prefixed"][i] = "customer/eu-west/" + strconv.Itoa(r.IntN(n))
+		inputs["duplicates"][i] = []string{"red", "green", "blue"}[r.IntN(3)]
+	}
+	sorts := []struct {
+		name string
+		sort func([]string)
+	}{
+		{"RadixLSD", RadixLSDStrings[[]string]},
+		{"RadixMSD", RadixMSDStrings[[]string]},
+		{"Tim", Tim[[]string]},
+		{"slices", slices.Sort[[]string]},
+	}
+
+	buf := make([]string, n)
+	for _, shape := range []string{"random", "prefixed", "duplicates"} {
+		for _, s := range sorts {
+			b.Run(shape+"/"+s.name, func(b *testing.B) {
+				for b.Loop() {
+					copy(buf, inputs[shape])
+					s.sort(buf)
+				}
+			})
+		}
+	}
+}
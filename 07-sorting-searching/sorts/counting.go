@@ -0,0 +1,48 @@
+package sorts
+
+// Integer is any integer type, the element type of Counting and the radix
+// sorts for numbers
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// maxCountingRange is the widest value range Counting tallies directly;
+// wider ranges would need an unreasonably large count table
+const maxCountingRange = 1 << 20
+
+// Counting sorts s in ascending order by counting how often each value
+// occurs, in O(n + k) time and O(k) space for values spanning a range of k.
+// It makes no comparisons, so it beats comparison sorts when k is small,
+// such as ages, scores or small enum values. When the range is wider than
+// about a million values it falls back to RadixLSD.
+func Counting[S ~[]E, E Integer](s S) {
+	if len(s) < 2 {
+		return
+	}
+	lo, hi := s[0], s[0]
+	for _, v := range s[1:] {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+
+	// Subtracting as uint64 gives the right span even when hi-lo overflows E
+	span := uint64(hi) - uint64(lo)
+	if span >= maxCountingRange {
+		RadixLSD(s)
+		return
+	}
+
+	counts := make([]int, span+1)
+	for _, v := range s {
+		counts[uint64(v)-uint64(lo)]++
+	}
+	i := 0
+	for offset, n := range counts {
+		v := E(uint64(lo) + uint64(offset))
+		for range n {
+			s[i] = v
+			i++
+		}
+	}
+}
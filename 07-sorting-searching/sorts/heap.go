@@ -0,0 +1,40 @@
+package sorts
+
+import "cmp"
+
+// Heap sorts s in ascending order. It is not stable.
+func Heap[S ~[]E, E cmp.Ordered](s S) {
+	HeapFunc(s, cmp.Compare[E])
+}
+
+// HeapFunc sorts s by compare using heapsort: it builds a max-heap in place,
+// then repeatedly swaps the largest element to the end. It runs in
+// O(n log n) time with no extra space whatever the input, but is not stable
+// and is usually slower than quicksort because of its scattered accesses.
+func HeapFunc[S ~[]E, E any](s S, compare func(a, b E) int) {
+	for i := len(s)/2 - 1; i >= 0; i-- {
+		siftDown(s, i, len(s), compare)
+	}
+	for end := len(s) - 1; end > 0; end-- {
+		s[0], s[end] = s[end], s[0]
+		siftDown(s, 0, end, compare)
+	}
+}
+
+// siftDown moves s[root] down until neither child within s[:end] is larger
+func siftDown[S ~[]E, E any](s S, root, end int, compare func(a, b E) int) {
+	for {
+		child := 2*root + 1
+		if child >= end {
+			return
+		}
+		if child+1 < end && compare(s[child], s[child+1]) < 0 {
+			child++
+		}
+		if compare(s[root], s[child]) >= 0 {
+			return
+		}
+		s[root], s[child] = s[child], s[root]
+		root = child
+	}
+}
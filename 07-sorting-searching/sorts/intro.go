@@ -0,0 +1,38 @@
+package sorts
+
+import (
+	"cmp"
+	"math/bits"
+)
+
+// Intro sorts s in ascending order. It is not stable.
+func Intro[S ~[]E, E cmp.Ordered](s S) {
+	IntroFunc(s, cmp.Compare[E])
+}
+
+// IntroFunc sorts s by compare using introsort: quicksort that switches to
+// heapsort once the recursion is 2*log2(n) levels deep, so it keeps
+// quicksort's speed on typical input but never degrades to O(n²). Short
+// partitions are finished with insertion sort. It is not stable.
+func IntroFunc[S ~[]E, E any](s S, compare func(a, b E) int) {
+	introSort(s, 2*bits.Len(uint(len(s))), compare)
+}
+
+func introSort[S ~[]E, E any](s S, depth int, compare func(a, b E) int) {
+	for len(s) > insertionCutoff {
+		if depth == 0 {
+			HeapFunc(s, compare)
+			return
+		}
+		depth--
+		p := partition(s, compare)
+		if p < len(s)-p {
+			introSort(s[:p], depth, compare)
+			s = s[p:]
+		} else {
+			introSort(s[p:], depth, compare)
+			s = s[:p]
+		}
+	}
+	InsertionFunc(s, compare)
+}
@@ -0,0 +1,54 @@
+package sorts
+
+import "cmp"
+
+// Merge sorts s in ascending order. It is stable.
+func Merge[S ~[]E, E cmp.Ordered](s S) {
+	MergeFunc(s, cmp.Compare[E])
+}
+
+// MergeFunc sorts s by compare using top-down merge sort. It is stable:
+// when elements compare equal the one from the left half is taken first.
+// It allocates one scratch buffer of len(s)/2 elements and reuses it for
+// every merge.
+func MergeFunc[S ~[]E, E any](s S, compare func(a, b E) int) {
+	if len(s) <= insertionCutoff {
+		InsertionFunc(s, compare)
+		return
+	}
+	buf := make([]E, len(s)/2)
+	mergeSort(s, buf, compare)
+}
+
+func mergeSort[E any](s, buf []E, compare func(a, b E) int) {
+	if len(s) <= insertionCutoff {
+		InsertionFunc(s, compare)
+		return
+	}
+	mid := len(s) / 2
+	mergeSort(s[:mid], buf, compare)
+	mergeSort(s[mid:], buf, compare)
+	if compare(s[mid-1], s[mid]) <= 0 {
+		return // already in order
+	}
+	merge(s, mid, buf, compare)
+}
+
+// merge combines the sorted runs s[:mid] and s[mid:] in place. The left run
+// is copied out to buf, so buf needs at least mid elements.
+func merge[E any](s []E, mid int, buf []E, compare func(a, b E) int) {
+	left := buf[:copy(buf, s[:mid])]
+	i, j, k := 0, mid, 0
+	for i < len(left) && j < len(s) {
+		if compare(s[j], left[i]) < 0 {
+			s[k] = s[j]
+			j++
+		} else {
+			s[k] = left[i]
+			i++
+		}
+		k++
+	}
+	// Whatever is left of the right run is already in place
+	copy(s[k:], left[i:])
+}
@@ -0,0 +1,209 @@
+package sorts
+
+import (
+	"cmp"
+	"math/bits"
+	"runtime"
+	"sync"
+)
+
+// DefaultParallelCutoff is the slice length below which the parallel sorts
+// stop forking and sort sequentially; smaller pieces cost more to hand to
+// another goroutine than to sort
+const DefaultParallelCutoff = 8192
+
+// ParallelOptions tune ParallelMerge and ParallelQuick. The zero value uses
+// GOMAXPROCS workers and DefaultParallelCutoff.
+type ParallelOptions struct {
+	Workers int // goroutines sorting at once, including the caller
+	Cutoff  int // pieces shorter than this are sorted on one goroutine
+}
+
+func (o ParallelOptions) withDefaults() ParallelOptions {
+	if o.Workers <= 0 {
+		o.Workers = runtime.GOMAXPROCS(0)
+	}
+	if o.Cutoff <= 0 {
+		o.Cutoff = DefaultParallelCutoff
+	}
+	return o
+}
+
+// forker runs work on extra goroutines while fewer than Workers are busy,
+// and inline otherwise, so the number of goroutines stays bounded however
+// deep the recursion goes
+type forker struct {
+	slots chan struct{}
+}
+
+func newForker(workers int) *forker {
+	return &forker{slots: make(chan struct{}, workers-1)}
+}
+
+// both runs a and b, in parallel if a worker is free
+func (f *forker) both(a, b func()) {
+	select {
+	case f.slots <- struct{}{}:
+		var wg sync.WaitGroup
+		wg.Go(func() {
+			defer func() { <-f.slots }()
+			a()
+		})
+		b()
+		wg.Wait()
+	default:
+		a()
+		b()
+	}
+}
+
+// ParallelMerge sorts s in ascending order on several goroutines. It is
+// stable.
+func ParallelMerge[S ~[]E, E cmp.Ordered](s S, opts ParallelOptions) {
+	ParallelMergeFunc(s, cmp.Compare[E], opts)
+}
+
+// ParallelMergeFunc sorts s by compare with a parallel merge sort: the two
+// halves are sorted concurrently, and large merges are split too, by
+// binary-searching the position of one run's middle element in the other
+// run. It allocates a single scratch buffer of len(s) and alternates
+// between it and s level by level, so no level allocates or copies back.
+// It is stable.
+func ParallelMergeFunc[S ~[]E, E any](s S, compare func(a, b E) int, opts ParallelOptions) {
+	opts = opts.withDefaults()
+	if len(s) < opts.Cutoff || opts.Workers == 1 {
+		MergeFunc(s, compare)
+		return
+	}
+	p := &parallelMerger[E]{compare: compare, cutoff: opts.Cutoff, fork: newForker(opts.Workers)}
+	p.sort(s, make([]E, len(s)), false)
+}
+
+type parallelMerger[E any] struct {
+	compare func(a, b E) int
+	cutoff  int
+	fork    *forker
+}
+
+// sort sorts s, leaving the result in buf if toBuf is set and in s
+// otherwise; s and buf have the same length and the other is scratch
+func (p *parallelMerger[E]) sort(s, buf []E, toBuf bool) {
+	if len(s) < p.cutoff {
+		mergeSort(s, buf, p.compare)
+		if toBuf {
+			copy(buf, s)
+		}
+		return
+	}
+
+	// Sort the halves into the other array, then merge them back
+	mid := len(s) / 2
+	p.fork.both(
+		func() { p.sort(s[:mid], buf[:mid], !toBuf) },
+		func() { p.sort(s[mid:], buf[mid:], !toBuf) },
+	)
+	src, dst := s, buf
+	if !toBuf {
+		src, dst = buf, s
+	}
+	p.merge(src[:mid], src[mid:], dst)
+}
+
+// merge merges the sorted runs a and b into dst, which has room for both.
+// Elements of a come first among equals.
+func (p *parallelMerger[E]) merge(a, b, dst []E) {
+	if len(a)+len(b) < p.cutoff {
+		mergeInto(a, b, dst, p.compare)
+		return
+	}
+
+	// Split on the middle of the longer run. Equal elements of a must end
+	// up before those of b on both sides of the split.
+	var i, j int // a[:i] and b[:j] go before the pivot
+	var pivot E
+	if len(a) >= len(b) {
+		i = len(a) / 2
+		pivot = a[i]
+		j = searchFunc(b, func(e E) bool { return p.compare(e, pivot) >= 0 })
+		dst[i+j] = pivot
+		p.fork.both(
+			func() { p.merge(a[:i], b[:j], dst[:i+j]) },
+			func() { p.merge(a[i+1:], b[j:], dst[i+j+1:]) },
+		)
+		return
+	}
+	j = len(b) / 2
+	pivot = b[j]
+	i = searchFunc(a, func(e E) bool { return p.compare(e, pivot) > 0 })
+	dst[i+j] = pivot
+	p.fork.both(
+		func() { p.merge(a[:i], b[:j], dst[:i+j]) },
+		func() { p.merge(a[i:], b[j+1:], dst[i+j+1:]) },
+	)
+}
+
+// mergeInto merges a and b into dst, taking from a first among equals
+func mergeInto[E any](a, b, dst []E, compare func(a, b E) int) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if compare(b[j], a[i]) < 0 {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
+
+// searchFunc returns the first index at which ok is true, assuming ok is
+// false and then true along s
+func searchFunc[E any](s []E, ok func(E) bool) int {
+	lo, hi := 0, len(s)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if ok(s[mid]) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo
+}
+
+// ParallelQuick sorts s in ascending order on several goroutines. It is not
+// stable.
+func ParallelQuick[S ~[]E, E cmp.Ordered](s S, opts ParallelOptions) {
+	ParallelQuickFunc(s, cmp.Compare[E], opts)
+}
+
+// ParallelQuickFunc sorts s by compare with a parallel quicksort: after
+// each partition the two sides are sorted concurrently. It needs no extra
+// memory. Pieces below the cutoff are sorted with IntroFunc, and like
+// introsort it switches to heapsort if partitioning goes badly, so it
+// never takes O(n²) time. It is not stable.
+func ParallelQuickFunc[S ~[]E, E any](s S, compare func(a, b E) int, opts ParallelOptions) {
+	opts = opts.withDefaults()
+	depth := 2 * bits.Len(uint(len(s)))
+	if len(s) < opts.Cutoff || opts.Workers == 1 {
+		introSort(s, depth, compare)
+		return
+	}
+	fork := newForker(opts.Workers)
+	parallelQuick(s, compare, opts.Cutoff, depth, fork)
+}
+
+func parallelQuick[S ~[]E, E any](s S, compare func(a, b E) int, cutoff, depth int, fork *forker) {
+	if len(s) < cutoff || depth == 0 {
+		introSort(s, depth, compare)
+		return
+	}
+	p := partition(s, compare)
+	fork.both(
+		func() { parallelQuick(s[:p], compare, cutoff, depth-1, fork) },
+		func() { parallelQuick(s[p:], compare, cutoff, depth-1, fork) },
+	)
+}
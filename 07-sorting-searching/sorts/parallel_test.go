@@ -0,0 +1,137 @@
+package sorts
+
+import (
+	"cmp"
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+// Small cutoffs force deep forking even on small inputs, so the race
+// detector sees plenty of concurrent work
+var parallelConfigs = []ParallelOptions{
+	{Workers: 1, Cutoff: 16},
+	{Workers: 2, Cutoff: 16},
+	{Workers: 8, Cutoff: 16},
+	{Workers: 8, Cutoff: 1000},
+	{}, // defaults
+}
+
+func TestParallelSorts(t *testing.T) {
+	r := rand.New(rand.NewPCG(21, 22))
+	for name, input := range inputs(r) {
+		want := slices.Clone(input)
+		slices.Sort(want)
+		for _, opts := range parallelConfigs {
+			for _, alg := range []struct {
+				name string
+				sort func([]int, ParallelOptions)
+			}{
+				{"ParallelMerge", ParallelMerge[[]int]},
+				{"ParallelQuick", ParallelQuick[[]int]},
+			} {
+				got := slices.Clone(input)
+				alg.sort(got, opts)
+				if !slices.Equal(got, want) {
+					t.Errorf("%s(%s, %+v) did not sort", alg.name, name, opts)
+				}
+			}
+		}
+	}
+}
+
+func TestParallelMergeStable(t *testing.T) {
+	r := rand.New(rand.NewPCG(23, 24))
+	input := make([]record, 20_000)
+	for i := range input {
+		input[i] = record{key: r.IntN(10), seq: i}
+	}
+	byKey := func(a, b record) int { return cmp.Compare(a.key, b.key) }
+	want := slices.Clone(input)
+	slices.SortStableFunc(want, byKey)
+
+	for _, opts := range parallelConfigs {
+		got := slices.Clone(input)
+		ParallelMergeFunc(got, byKey, opts)
+		if !slices.Equal(got, want) {
+			t.Errorf("ParallelMergeFunc(%+v) is not stable", opts)
+		}
+	}
+}
+
+// Adversarial input must not send ParallelQuick into O(n²) or unbounded
+// recursion; all-equal and sawtooth inputs stress the partitioning
+func TestParallelQuickAdversarial(t *testing.T) {
+	n := 50_000
+	equal := make([]int, n)
+	saw := make([]int, n)
+	for i := range n {
+		saw[i] = i % 3
+	}
+	for name, input := range map[string][]int{"equal": equal, "sawtooth": saw} {
+		got := slices.Clone(input)
+		ParallelQuick(got, ParallelOptions{Workers: 4, Cutoff: 32})
+		if !slices.IsSorted(got) {
+			t.Errorf("ParallelQuick(%s) did not sort", name)
+		}
+	}
+}
+
+func TestParallelMergeSplit(t *testing.T) {
+	// Runs of very different lengths and many duplicates exercise both
+	// split branches of the parallel merge
+	p := &parallelMerger[int]{compare: cmp.Compare[int], cutoff: 4, fork: newForker(4)}
+	a := []int{1, 2, 2, 2, 3, 5, 8, 8, 9, 12, 15, 20}
+	b := []int{2, 8}
+	for _, pair := range [][2][]int{{a, b}, {b, a}, {a, nil}, {nil, a}} {
+		dst := make([]int, len(pair[0])+len(pair[1]))
+		p.merge(pair[0], pair[1], dst)
+		want := slices.Concat(pair[0], pair[1])
+		slices.Sort(want)
+		if !slices.Equal(dst, want) {
+			t.Errorf("merge(%v, %v) = %v; want %v", pair[0], pair[1], dst, want)
+		}
+	}
+}
+
+// BenchmarkParallel sorts a million random ints with 1, 2, 4, ...
+// GOMAXPROCS workers; compare each against workers=1 for the speedup
+func BenchmarkParallel(b *testing.B) {
+	r := rand.New(rand.NewPCG(25, 26))
+	input := make([]int, 1_000_000)
+	for i := range input {
+		input[i] = r.Int()
+	}
+	buf := make([]int, len(input))
+
+	var workers []int
+	for w := 1; w < runtime.GOMAXPROCS(0); w *= 2 {
+		workers = append(workers, w)
+	}
+	workers = append(workers, runtime.GOMAXPROCS(0))
+
+	for _, alg := range []struct {
+		name string
+		sort func([]int, ParallelOptions)
+	}{
+		{"Merge", ParallelMerge[[]int]},
+		{"Quick", ParallelQuick[[]int]},
+	} {
+		for _, w := range workers {
+			b.Run(fmt.Sprintf("%s/workers=%d", alg.name, w), func(b *testing.B) {
+				for b.Loop() {
+					copy(buf, input)
+					alg.sort(buf, ParallelOptions{Workers: w})
+				}
+			})
+		}
+	}
+	b.Run("slices.Sort", func(b *testing.B) {
+		for b.Loop() {
+			copy(buf, input)
+			slices.Sort(buf)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package sorts
+
+import "cmp"
+
+// insertionCutoff is the length below which Quick and Merge finish with
+// insertion sort, which beats them on short slices
+const insertionCutoff = 12
+
+// Quick sorts s in ascending order. It is not stable.
+func Quick[S ~[]E, E cmp.Ordered](s S) {
+	QuickFunc(s, cmp.Compare[E])
+}
+
+// QuickFunc sorts s by compare using quicksort with a median-of-three pivot
+// and Hoare partitioning. It is not stable. Recursing into the smaller
+// partition and looping on the larger keeps the stack O(log n), though
+// adversarial input can still take O(n²) time.
+func QuickFunc[S ~[]E, E any](s S, compare func(a, b E) int) {
+	for len(s) > insertionCutoff {
+		p := partition(s, compare)
+		if p < len(s)-p {
+			QuickFunc(s[:p], compare)
+			s = s[p:]
+		} else {
+			QuickFunc(s[p:], compare)
+			s = s[:p]
+		}
+	}
+	InsertionFunc(s, compare)
+}
+
+// partition reorders s around a pivot and returns p such that every element
+// of s[:p] is <= the pivot and every element of s[p:] is >= it, with both
+// halves non-empty
+func partition[S ~[]E, E any](s S, compare func(a, b E) int) int {
+	// Hoare's scheme needs the pivot at or left of the middle to guarantee
+	// that the right half is non-empty
+	m := (len(s) - 1) / 2
+	mo := medianOfThree(s, compare)
+	s[m], s[mo] = s[mo], s[m]
+	pivot := s[m]
+
+	left, right := -1, len(s)
+	for {
+		for left++; compare(s[left], pivot) < 0; left++ {
+		}
+		for right--; compare(s[right], pivot) > 0; right-- {
+		}
+		if left >= right {
+			return right + 1
+		}
+		s[left], s[right] = s[right], s[left]
+	}
+}
+
+// medianOfThree returns the index of the median of the first, middle and
+// last elements, which avoids the worst case on sorted and reversed input
+func medianOfThree[S ~[]E, E any](s S, compare func(a, b E) int) int {
+	a, b, c := 0, len(s)/2, len(s)-1
+	if compare(s[a], s[b]) > 0 {
+		a, b = b, a
+	}
+	if compare(s[b], s[c]) > 0 {
+		b = c
+		if compare(s[a], s[b]) > 0 {
+			b = a
+		}
+	}
+	return b
+}
@@ -0,0 +1,216 @@
+package sorts
+
+import "unsafe"
+
+// radixCutoff is the bucket size below which MSD radix sorts switch to
+// insertion sort
+const radixCutoff = 32
+
+// radixKey maps integers to unsigned keys with the same order: values are
+// truncated to their own width and signed ones get their sign bit flipped,
+// so negative numbers sort before positive ones
+func radixKey[E Integer]() (key func(E) uint64, width int) {
+	var zero E
+	width = int(unsafe.Sizeof(zero))
+	bitsWidth := uint(width * 8)
+	mask := uint64(1)<<bitsWidth - 1 // wraps to all ones for 64 bits
+	var flip uint64
+	if ^zero < 0 { // signed
+		flip = 1 << (bitsWidth - 1)
+	}
+	return func(v E) uint64 {
+		return (uint64(v) ^ flip) & mask
+	}, width
+}
+
+// RadixLSD sorts s in ascending order one byte at a time, starting from the
+// least significant byte, with a stable counting pass per byte. It takes
+// O(n * w) time for w-byte integers and a buffer of len(s) elements. Passes
+// where every value has the same byte are skipped.
+func RadixLSD[S ~[]E, E Integer](s S) {
+	if len(s) < 2 {
+		return
+	}
+	key, width := radixKey[E]()
+	src, dst := []E(s), make([]E, len(s))
+	for pass := range width {
+		shift := uint(pass * 8)
+		var counts [256]int
+		for _, v := range src {
+			counts[byte(key(v)>>shift)]++
+		}
+		if counts[byte(key(src[0])>>shift)] == len(src) {
+			continue // every value has this byte
+		}
+		offsets := prefixSums(&counts)
+		for _, v := range src {
+			d := byte(key(v) >> shift)
+			dst[offsets[d]] = v
+			offsets[d]++
+		}
+		src, dst = dst, src
+	}
+	if &src[0] != &s[0] {
+		copy(s, src)
+	}
+}
+
+// RadixMSD sorts s in ascending order starting from the most significant
+// byte: it splits s into 256 buckets by that byte and sorts each bucket by
+// the next, finishing small buckets with insertion sort. Unlike RadixLSD it
+// stops early on buckets that need no more bytes to tell values apart.
+func RadixMSD[S ~[]E, E Integer](s S) {
+	if len(s) < 2 {
+		return
+	}
+	key, width := radixKey[E]()
+	radixMSD(s, make([]E, len(s)), key, uint((width-1)*8))
+}
+
+func radixMSD[E Integer](s, buf []E, key func(E) uint64, shift uint) {
+	if len(s) <= radixCutoff {
+		Insertion(s)
+		return
+	}
+	var counts [256]int
+	for _, v := range s {
+		counts[byte(key(v)>>shift)]++
+	}
+	if counts[byte(key(s[0])>>shift)] == len(s) && shift > 0 {
+		// One bucket holds everything; go straight to the next byte
+		radixMSD(s, buf, key, shift-8)
+		return
+	}
+	starts := prefixSums(&counts)
+	offsets := starts
+	for _, v := range s {
+		d := byte(key(v) >> shift)
+		buf[offsets[d]] = v
+		offsets[d]++
+	}
+	copy(s, buf[:len(s)])
+	if shift == 0 {
+		return
+	}
+	for d, n := range counts {
+		if n > 1 {
+			lo := starts[d]
+			radixMSD(s[lo:lo+n], buf[lo:lo+n], key, shift-8)
+		}
+	}
+}
+
+// prefixSums turns byte counts into the index where each byte's bucket
+// starts
+func prefixSums(counts *[256]int) [256]int {
+	var offsets [256]int
+	sum := 0
+	for d, n := range counts {
+		offsets[d] = sum
+		sum += n
+	}
+	return offsets
+}
+
+// RadixLSDStrings sorts s byte-wise, from the last position of the longest
+// string back to the first, with a stable counting pass per position.
+// Strings shorter than the position go in a bucket before every byte, so
+// "ab" sorts before "abc". It takes O(n * L) time for longest length L,
+// which suits many strings of similar, short length such as codes or IDs.
+func RadixLSDStrings[S ~[]E, E ~string](s S) {
+	if len(s) < 2 {
+		return
+	}
+	maxLen := 0
+	for _, v := range s {
+		maxLen = max(maxLen, len(v))
+	}
+	src, dst := []E(s), make([]E, len(s))
+	for pos := maxLen - 1; pos >= 0; pos-- {
+		var counts [257]int
+		for _, v := range src {
+			counts[charAt(v, pos)]++
+		}
+		sum := 0
+		for d, n := range counts {
+			counts[d] = sum
+			sum += n
+		}
+		for _, v := range src {
+			c := charAt(v, pos)
+			dst[counts[c]] = v
+			counts[c]++
+		}
+		src, dst = dst, src
+	}
+	if &src[0] != &s[0] {
+		copy(s, src)
+	}
+}
+
+// RadixMSDStrings sorts s byte-wise from the first position: it splits s
+// into buckets by the first byte, with strings that have ended first, and
+// sorts each bucket by the next byte. Buckets of strings that share a long
+// prefix only examine that prefix once, and small buckets are finished with
+// insertion sort. It suits strings of very different lengths.
+func RadixMSDStrings[S ~[]E, E ~string](s S) {
+	if len(s) < 2 {
+		return
+	}
+	radixMSDStrings(s, make([]E, len(s)), 0)
+}
+
+func radixMSDStrings[E ~string](s, buf []E, pos int) {
+	if len(s) <= radixCutoff {
+		// Every string already agrees on s[:pos]
+		InsertionFunc(s, func(a, b E) int {
+			return compareFrom(a, b, pos)
+		})
+		return
+	}
+	var counts [257]int
+	for _, v := range s {
+		counts[charAt(v, pos)]++
+	}
+	var starts [257]int
+	sum := 0
+	for d, n := range counts {
+		starts[d] = sum
+		sum += n
+	}
+	offsets := starts
+	for _, v := range s {
+		c := charAt(v, pos)
+		buf[offsets[c]] = v
+		offsets[c]++
+	}
+	copy(s, buf[:len(s)])
+
+	// Bucket 0 holds strings that have ended; they are all equal
+	for c := 1; c < len(counts); c++ {
+		if n := counts[c]; n > 1 {
+			lo := starts[c]
+			radixMSDStrings(s[lo:lo+n], buf[lo:lo+n], pos+1)
+		}
+	}
+}
+
+// charAt returns 1 + the byte at pos, or 0 if v is too short
+func charAt[E ~string](v E, pos int) int {
+	if pos < len(v) {
+		return int(v[pos]) + 1
+	}
+	return 0
+}
+
+// compareFrom compares a and b from byte pos on
+func compareFrom[E ~string](a, b E, pos int) int {
+	a, b = a[min(pos, len(a)):], b[min(pos, len(b)):]
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
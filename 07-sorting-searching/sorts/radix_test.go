@@ -0,0 +1,147 @@
+package sorts
+
+import (
+	"cmp"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// checkIntegers sorts copies of input with every integer sort and compares
+// the results with slices.Sort
+func checkIntegers[E Integer](t *testing.T, name string, input []E) {
+	t.Helper()
+	want := slices.Clone(input)
+	slices.Sort(want)
+	for _, alg := range []struct {
+		name string
+		sort func([]E)
+	}{
+		{"Counting", Counting[[]E]},
+		{"RadixLSD", RadixLSD[[]E]},
+		{"RadixMSD", RadixMSD[[]E]},
+	} {
+		got := slices.Clone(input)
+		alg.sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("%s(%s) = %v; want %v", alg.name, name, got, want)
+		}
+	}
+}
+
+func TestIntegerSorts(t *testing.T) {
+	r := rand.New(rand.NewPCG(11, 12))
+	for name, input := range inputs(r) {
+		checkIntegers(t, name, input)
+	}
+
+	checkIntegers(t, "int8 extremes", []int8{127, -128, 0, -1, 1, -128, 127})
+	checkIntegers(t, "uint8", []uint8{255, 0, 128, 127, 1, 255})
+	checkIntegers(t, "int64 extremes", []int64{math.MaxInt64, math.MinInt64, 0, -1, 1, math.MinInt64 + 1})
+	checkIntegers(t, "uint64 extremes", []uint64{math.MaxUint64, 0, 1 << 63, 1<<63 - 1, 42})
+
+	wide := make([]int32, 5000)
+	for i := range wide {
+		wide[i] = r.Int32() - math.MaxInt32/2
+	}
+	checkIntegers(t, "wide int32", wide) // Counting falls back to radix
+
+	type celsius int16
+	temps := make([]celsius, 3000)
+	for i := range temps {
+		temps[i] = celsius(r.IntN(200) - 100)
+	}
+	checkIntegers(t, "named int16", temps)
+}
+
+func TestRadixStrings(t *testing.T) {
+	r := rand.New(rand.NewPCG(13, 14))
+	cases := map[string][]string{
+		"empty":    {},
+		"single":   {"x"},
+		"prefixes": {"abc", "ab", "", "a", "abcd", "b", "", "ab"},
+		"bytes":    {"\xff", "\x00", "\x00\x00", "\x7f", "\x80", ""},
+	}
+	random := make([]string, 2000)
+	shared := make([]string, 2000)
+	for i := range random {
+		b := make([]byte, r.IntN(12))
+		for j := range b {
+			b[j] = byte('a' + r.IntN(4))
+		}
+		random[i] = string(b)
+		shared[i] = strings.Repeat("prefix/", 5) + string(b)
+	}
+	cases["random"] = random
+	cases["shared prefix"] = shared
+
+	for name, input := range cases {
+		want := slices.Clone(input)
+		slices.Sort(want)
+		for _, alg := range []struct {
+			name string
+			sort func([]string)
+		}{
+			{"RadixLSDStrings", RadixLSDStrings[[]string]},
+			{"RadixMSDStrings", RadixMSDStrings[[]string]},
+		} {
+			got := slices.Clone(input)
+			alg.sort(got)
+			if !slices.Equal(got, want) {
+				t.Errorf("%s(%s) = %q; want %q", alg.name, name, got, want)
+			}
+		}
+	}
+}
+
+// Forcing the depth limit to zero exercises the heapsort fallback that
+// protects introsort from quicksort's worst case
+func TestIntroHeapFallback(t *testing.T) {
+	r := rand.New(rand.NewPCG(15, 16))
+	for _, depth := range []int{0, 1, 2} {
+		s := make([]int, 500)
+		for i := range s {
+			s[i] = r.IntN(100)
+		}
+		want := slices.Clone(s)
+		slices.Sort(want)
+		introSort(s, depth, cmp.Compare[int])
+		if !slices.Equal(s, want) {
+			t.Errorf("introSort with depth %d did not sort", depth)
+		}
+	}
+}
+
+// Tim should find a single pass over sorted or reversed input
+func TestTimNaturalRuns(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		input []int
+		want  int
+	}{
+		{"ascending", []int{1, 2, 2, 3, 5}, 5},
+		{"descending", []int{5, 3, 2, 1}, 4},
+		{"descending stops at equal", []int{5, 3, 3, 1}, 2}, // reversing equal elements would break stability
+		{"short", []int{7}, 1},
+	} {
+		s := slices.Clone(tc.input)
+		if got := naturalRun(s, cmp.Compare[int]); got != tc.want {
+			t.Errorf("naturalRun(%v) = %d; want %d", tc.input, got, tc.want)
+		}
+		if !slices.IsSorted(s[:tc.want]) {
+			t.Errorf("naturalRun(%v) left the run unsorted: %v", tc.input, s)
+		}
+	}
+
+	calls := 0
+	s := make([]int, 10_000)
+	for i := range s {
+		s[i] = len(s) - i
+	}
+	TimFunc(s, func(a, b int) int { calls++; return cmp.Compare(a, b) })
+	if calls >= len(s) {
+		t.Errorf("reversed input took %d comparisons; want fewer than %d", calls, len(s))
+	}
+}
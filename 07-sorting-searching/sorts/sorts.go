@@ -0,0 +1,95 @@
+// Package sorts implements the classic comparison sorts generically. Each
+// algorithm comes in two forms, following the slices package:
+//
+//	sorts.Insertion(ints)                                  // any cmp.Ordered element
+//	sorts.InsertionFunc(people, func(a, b Person) int {    // any element, with a comparator
+//	    return cmp.Compare(a.Age, b.Age)
+//	})
+//
+// Comparators return a negative number when a sorts before b, a positive
+// number when it sorts after, and zero when they are equal, like cmp.Compare.
+// The ordered forms use cmp.Compare, so NaNs sort before other floats.
+//
+// All sorts work in place. Stability, whether equal elements keep their
+// original order, is part of each function's contract:
+//
+//	Bubble     stable    O(n²)
+//	Selection  unstable  O(n²)
+//	Insertion  stable    O(n²), O(n) when nearly sorted
+//	Quick      unstable  O(n log n) average, O(n²) worst
+//	Merge      stable    O(n log n), O(n) extra space
+//	Heap       unstable  O(n log n), no extra space
+//	Intro      unstable  O(n log n): quicksort with a heapsort fallback
+//	Tim        stable    O(n log n), O(n) for presorted runs
+//
+// Integers and strings can also be sorted without comparisons, by counting
+// (Counting) or byte by byte (RadixLSD, RadixMSD, RadixLSDStrings and
+// RadixMSDStrings).
+//
+// ParallelMerge and ParallelQuick split the work across goroutines for
+// large slices.
+package sorts
+
+import "cmp"
+
+// Bubble sorts s in ascending order. It is stable.
+func Bubble[S ~[]E, E cmp.Ordered](s S) {
+	BubbleFunc(s, cmp.Compare[E])
+}
+
+// BubbleFunc sorts s by compare, repeatedly swapping adjacent elements that
+// are out of order. It stops early once a pass makes no swaps. It is stable:
+// only strictly greater elements are swapped past each other.
+func BubbleFunc[S ~[]E, E any](s S, compare func(a, b E) int) {
+	for n := len(s); n > 1; n-- {
+		swapped := false
+		for j := 1; j < n; j++ {
+			if compare(s[j-1], s[j]) > 0 {
+				s[j-1], s[j] = s[j], s[j-1]
+				swapped = true
+			}
+		}
+		if !swapped {
+			return
+		}
+	}
+}
+
+// Selection sorts s in ascending order. It is not stable.
+func Selection[S ~[]E, E cmp.Ordered](s S) {
+	SelectionFunc(s, cmp.Compare[E])
+}
+
+// SelectionFunc sorts s by compare, moving the smallest remaining element
+// to the front on each pass. It makes at most n-1 swaps, but is not stable:
+// a swap can carry an element past others equal to it.
+func SelectionFunc[S ~[]E, E any](s S, compare func(a, b E) int) {
+	for i := 0; i < len(s)-1; i++ {
+		minIdx := i
+		for j := i + 1; j < len(s); j++ {
+			if compare(s[j], s[minIdx]) < 0 {
+				minIdx = j
+			}
+		}
+		s[i], s[minIdx] = s[minIdx], s[i]
+	}
+}
+
+// Insertion sorts s in ascending order. It is stable.
+func Insertion[S ~[]E, E cmp.Ordered](s S) {
+	InsertionFunc(s, cmp.Compare[E])
+}
+
+// InsertionFunc sorts s by compare, inserting each element into the sorted
+// prefix before it. It is stable, and fast for short or nearly sorted input.
+func InsertionFunc[S ~[]E, E any](s S, compare func(a, b E) int) {
+	for i := 1; i < len(s); i++ {
+		key := s[i]
+		j := i - 1
+		for j >= 0 && compare(s[j], key) > 0 {
+			s[j+1] = s[j]
+			j--
+		}
+		s[j+1] = key
+	}
+}
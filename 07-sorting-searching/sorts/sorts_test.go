@@ -0,0 +1,190 @@
+package sorts
+
+import (
+	"cmp"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+type algorithm struct {
+	name   string
+	sort   func([]int)
+	sortFn func([]record, func(a, b record) int)
+	stable bool
+}
+
+// record is sorted by key; seq remembers the original position
+type record struct {
+	key int
+	seq int
+}
+
+var algorithms = []algorithm{
+	{"Bubble", Bubble[[]int], BubbleFunc[[]record], true},
+	{"Selection", Selection[[]int], SelectionFunc[[]record], false},
+	{"Insertion", Insertion[[]int], InsertionFunc[[]record], true},
+	{"Quick", Quick[[]int], QuickFunc[[]record], false},
+	{"Merge", Merge[[]int], MergeFunc[[]record], true},
+	{"Heap", Heap[[]int], HeapFunc[[]record], false},
+	{"Intro", Intro[[]int], IntroFunc[[]record], false},
+	{"Tim", Tim[[]int], TimFunc[[]record], true},
+}
+
+// inputs returns slices of several shapes and sizes, including the
+// patterns that break naive quicksorts
+func inputs(r *rand.Rand) map[string][]int {
+	cases := map[string][]int{
+		"empty":  {},
+		"single": {1},
+		"pair":   {2, 1},
+	}
+	for _, n := range []int{5, 13, 100, 1000} {
+		random := make([]int, n)
+		dups := make([]int, n)
+		sorted := make([]int, n)
+		reversed := make([]int, n)
+		organ := make([]int, n)
+		for i := range n {
+			random[i] = r.IntN(1_000_000) - 500_000
+			dups[i] = r.IntN(4)
+			sorted[i] = i
+			reversed[i] = n - i
+			organ[i] = min(i, n-i)
+		}
+		cases["random/"+strconv.Itoa(n)] = random
+		cases["duplicates/"+strconv.Itoa(n)] = dups
+		cases["sorted/"+strconv.Itoa(n)] = sorted
+		cases["reversed/"+strconv.Itoa(n)] = reversed
+		cases["organ-pipe/"+strconv.Itoa(n)] = organ
+		cases["sorted-blocks/"+strconv.Itoa(n)] = sortedBlocks(r, n)
+	}
+	return cases
+}
+
+// sortedBlocks returns n values made of ascending and descending runs of
+// random lengths, the input Tim is built for
+func sortedBlocks(r *rand.Rand, n int) []int {
+	s := make([]int, n)
+	for start := 0; start < n; {
+		end := min(n, start+1+r.IntN(200))
+		base, step := r.IntN(1000), 1
+		if r.IntN(2) == 0 {
+			step = -1
+		}
+		for i := start; i < end; i++ {
+			s[i] = base + step*(i-start)
+		}
+		start = end
+	}
+	return s
+}
+
+func TestSortsMatchSlicesSort(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for name, input := range inputs(r) {
+		want := slices.Clone(input)
+		slices.Sort(want)
+		for _, alg := range algorithms {
+			t.Run(alg.name+"/"+name, func(t *testing.T) {
+				got := slices.Clone(input)
+				alg.sort(got)
+				if !slices.Equal(got, want) {
+					t.Errorf("%s(%v) = %v; want %v", alg.name, input, got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestStability(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	for _, n := range []int{10, 50, 500} {
+		input := make([]record, n)
+		for i := range input {
+			input[i] = record{key: r.IntN(5), seq: i}
+		}
+		byKey := func(a, b record) int { return cmp.Compare(a.key, b.key) }
+
+		for _, alg := range algorithms {
+			got := slices.Clone(input)
+			alg.sortFn(got, byKey)
+			if !slices.IsSortedFunc(got, byKey) {
+				t.Errorf("%s (n=%d): not sorted by key", alg.name, n)
+				continue
+			}
+			if !alg.stable {
+				continue
+			}
+			for i := 1; i < len(got); i++ {
+				if got[i-1].key == got[i].key && got[i-1].seq > got[i].seq {
+					t.Errorf("%s (n=%d): equal keys reordered at %d: %v before %v",
+						alg.name, n, i, got[i-1], got[i])
+					break
+				}
+			}
+		}
+	}
+}
+
+// Selection sort's instability is easy to show: the swap that brings 1 to
+// the front carries the first 2 past the second
+func TestSelectionUnstable(t *testing.T) {
+	got := []record{{2, 0}, {2, 1}, {1, 2}}
+	SelectionFunc(got, func(a, b record) int { return cmp.Compare(a.key, b.key) })
+	want := []record{{1, 2}, {2, 1}, {2, 0}}
+	if !slices.Equal(got, want) {
+		t.Errorf("SelectionFunc = %v; want %v", got, want)
+	}
+}
+
+func TestNamedSliceAndStrings(t *testing.T) {
+	type names []string
+	got := names{"zebra", "apple", "cherry", "banana"}
+	Merge(got)
+	if want := (names{"apple", "banana", "cherry", "zebra"}); !slices.Equal(got, want) {
+		t.Errorf("Merge = %v; want %v", got, want)
+	}
+}
+
+func TestFloatsWithNaN(t *testing.T) {
+	input := []float64{3, math.NaN(), -1, math.Inf(1), 0, math.NaN(), math.Inf(-1)}
+	want := slices.Clone(input)
+	slices.Sort(want)
+	for _, alg := range []struct {
+		name string
+		sort func([]float64)
+	}{
+		{"Bubble", Bubble[[]float64]},
+		{"Selection", Selection[[]float64]},
+		{"Insertion", Insertion[[]float64]},
+		{"Quick", Quick[[]float64]},
+		{"Merge", Merge[[]float64]},
+		{"Heap", Heap[[]float64]},
+		{"Intro", Intro[[]float64]},
+		{"Tim", Tim[[]float64]},
+	} {
+		got := slices.Clone(input)
+		alg.sort(got)
+		// NaN != NaN, so compare with cmp.Compare
+		if slices.CompareFunc(got, want, func(a, b float64) int { return cmp.Compare(a, b) }) != 0 {
+			t.Errorf("%s = %v; want %v", alg.name, got, want)
+		}
+	}
+}
+
+func TestDescendingComparator(t *testing.T) {
+	desc := func(a, b int) int { return cmp.Compare(b, a) }
+	for _, sortFn := range []func([]int, func(a, b int) int){
+		BubbleFunc[[]int], SelectionFunc[[]int], InsertionFunc[[]int], QuickFunc[[]int], MergeFunc[[]int],
+		HeapFunc[[]int], IntroFunc[[]int], TimFunc[[]int],
+	} {
+		got := []int{3, 1, 4, 1, 5, 9, 2, 6, 5, 3, 5, 8, 9, 7, 9, 3}
+		sortFn(got, desc)
+		if !slices.IsSortedFunc(got, desc) {
+			t.Errorf("not sorted descending: %v", got)
+		}
+	}
+}
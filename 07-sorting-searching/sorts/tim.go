@@ -0,0 +1,106 @@
+package sorts
+
+import "cmp"
+
+// minRun is the shortest run Tim builds before merging; shorter natural
+// runs are extended with insertion sort
+const minRun = 32
+
+// Tim sorts s in ascending order. It is stable.
+func Tim[S ~[]E, E cmp.Ordered](s S) {
+	TimFunc(s, cmp.Compare[E])
+}
+
+// TimFunc sorts s by compare with a timsort-style natural merge sort. It
+// scans for runs that are already ascending, or strictly descending and so
+// can be reversed without breaking stability, extends short runs to minRun
+// with insertion sort, and merges neighbouring runs while keeping their
+// lengths balanced. Sorted or reversed input takes one pass; input made of
+// a few sorted blocks takes a few merges. It is stable.
+//
+// Unlike CPython's timsort it does not gallop, so merges of runs with very
+// different lengths are linear rather than logarithmic in the shorter run.
+func TimFunc[S ~[]E, E any](s S, compare func(a, b E) int) {
+	if len(s) <= minRun {
+		InsertionFunc(s, compare)
+		return
+	}
+
+	var buf []E
+	var runs []run
+	for start := 0; start < len(s); {
+		end := start + naturalRun(s[start:], compare)
+		if end-start < minRun {
+			end = min(start+minRun, len(s))
+			InsertionFunc(s[start:end], compare)
+		}
+		runs = append(runs, run{start, end - start})
+		runs, buf = collapse(s, runs, buf, compare, false)
+		start = end
+	}
+	collapse(s, runs, buf, compare, true)
+}
+
+// run is a sorted stretch s[start : start+length]
+type run struct {
+	start, length int
+}
+
+// naturalRun returns the length of the run at the start of s, reversing it
+// first if it is strictly descending
+func naturalRun[S ~[]E, E any](s S, compare func(a, b E) int) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	n := 2
+	if compare(s[1], s[0]) < 0 {
+		for n < len(s) && compare(s[n], s[n-1]) < 0 {
+			n++
+		}
+		reverse(s[:n])
+		return n
+	}
+	for n < len(s) && compare(s[n], s[n-1]) >= 0 {
+		n++
+	}
+	return n
+}
+
+func reverse[S ~[]E, E any](s S) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// collapse merges runs on the stack until, from the top, each run is longer
+// than the next one up and longer than the two above it combined, which
+// keeps the stack O(log n) deep and merges of similar sizes. With all set it
+// merges everything into one run.
+func collapse[S ~[]E, E any](s S, runs []run, buf []E, compare func(a, b E) int, all bool) ([]run, []E) {
+	for len(runs) > 1 {
+		n := len(runs) - 1 // merge runs[n-1] and runs[n] unless told otherwise
+		switch {
+		case all:
+		case n >= 2 && runs[n-2].length <= runs[n-1].length+runs[n].length,
+			n >= 3 && runs[n-3].length <= runs[n-2].length+runs[n-1].length:
+			if runs[n-2].length < runs[n].length {
+				n-- // merge the smaller neighbour of runs[n-1] into it
+			}
+		case runs[n-1].length <= runs[n].length:
+		default:
+			return runs, buf
+		}
+
+		a, b := runs[n-1], runs[n]
+		if len(buf) < a.length {
+			buf = make([]E, max(a.length, 2*len(buf)))
+		}
+		merged := s[a.start : b.start+b.length]
+		if compare(merged[a.length-1], merged[a.length]) > 0 {
+			merge(merged, a.length, buf, compare)
+		}
+		runs[n-1] = run{a.start, a.length + b.length}
+		runs = append(runs[:n], runs[n+1:]...)
+	}
+	return runs, buf
+}